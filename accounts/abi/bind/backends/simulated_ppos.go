@@ -0,0 +1,435 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft"
+	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/evidence"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/cbfttypes"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/eth/filters"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/event"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	"github.com/PlatONnetwork/PlatON-Go/x/handler"
+	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
+	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+var errNoProducer = errors.New("no validator of the consensus round can produce blocks")
+
+// ValidatorBehaviour scripts how a validator of a SimulatedPPOSBackend acts
+// when it is its turn to produce blocks.
+type ValidatorBehaviour int
+
+const (
+	// ValidatorHonest produces the blocks of its turn.
+	ValidatorHonest ValidatorBehaviour = iota
+	// ValidatorOffline misses its turn, the next validator producing the blocks
+	// instead, so that it is slashed for a low block rate.
+	ValidatorOffline
+)
+
+var pposReactorOnce sync.Once
+
+// SimulatedPPOSBackend is a SimulatedBackend whose blocks are produced the way a
+// cbft validator produces them: the BlockChainReactor runs the staking,
+// governance, restricting, reward and slashing plugins around the transactions
+// of every block, and the plugins keep their data in the snapshot database.
+// It allows to test code interacting with the PlatON precompiled contracts
+// without a network.
+//
+// The reactor, the plugins and the snapshot database are process wide, only one
+// PPOS backend may be in use at a time and it must be closed before creating
+// another, which points them at its own chain. Neither can it be used along
+// with a SimulatedBackend.
+type SimulatedPPOSBackend struct {
+	*SimulatedBackend
+
+	reactor     *core.BlockChainReactor
+	snapshotDir string
+
+	keys      map[discover.NodeID]*ecdsa.PrivateKey // Keys of the nodes able to produce blocks
+	behaviour map[discover.NodeID]ValidatorBehaviour
+
+	header     *types.Header     // Header of the pending block, sealed on commit
+	producer   *ecdsa.PrivateKey // Key of the validator producing the pending block
+	gasPool    *core.GasPool
+	txs        []*types.Transaction
+	receipts   []*types.Receipt
+	timeOffset int64  // Milliseconds added to the time of the pending block
+	discarded  uint64 // Number of pending blocks discarded from the snapshot database
+}
+
+// NewSimulatedPPOSBackend creates a new binding backend running the PPOS plugins
+// on a simulated blockchain for testing purposes. The nodes of the given keys
+// are the initial validators of the chain, up to the number of consensus
+// validators, the remaining ones may be staked by the tests. Blocks are
+// produced in turn by the validators of each consensus round whose key is
+// known.
+func NewSimulatedPPOSBackend(alloc core.GenesisAlloc, gasLimit uint64, keys []*ecdsa.PrivateKey) (*SimulatedPPOSBackend, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one validator key is required")
+	}
+	xcom.GetEc(xcom.DefaultTestNet)
+
+	snapshotDir, err := ioutil.TempDir("", "simulated-ppos")
+	if err != nil {
+		return nil, err
+	}
+	snapshotdb.SetDBPathWithNode(snapshotDir)
+
+	nodes := make([]params.CbftNode, len(keys))
+	known := make(map[discover.NodeID]*ecdsa.PrivateKey, len(keys))
+	for i, key := range keys {
+		var blsKey bls.SecretKey
+		blsKey.SetByCSPRNG()
+		id := discover.PubkeyID(&key.PublicKey)
+		nodes[i] = params.CbftNode{Node: discover.Node{ID: id}, BlsPubKey: *blsKey.GetPublicKey()}
+		known[id] = key
+	}
+	config := &params.ChainConfig{
+		ChainID:     big.NewInt(1337),
+		EIP155Block: big.NewInt(0),
+		Cbft: &params.CbftConfig{
+			Period:        xcom.Interval(),
+			Amount:        uint32(xcom.BlocksWillCreate()),
+			InitialNodes:  nodes,
+			ValidatorMode: common.PPOS_VALIDATOR_MODE,
+		},
+	}
+	genesis := core.Genesis{Config: config, GasLimit: gasLimit, Alloc: alloc}
+
+	database := ethdb.NewMemDatabase()
+	genesisBlock, err := genesis.Commit(database, snapshotdb.Instance())
+	if err != nil {
+		snapshotdb.Instance().Clear()
+		return nil, err
+	}
+	blockchain, err := core.NewBlockChain(database, nil, config, cbft.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		snapshotdb.Instance().Clear()
+		return nil, err
+	}
+	snapshotdb.SetDBBlockChain(blockchain)
+
+	backend := &SimulatedPPOSBackend{
+		SimulatedBackend: &SimulatedBackend{
+			database:   database,
+			blockchain: blockchain,
+			config:     config,
+			events:     filters.NewEventSystem(new(event.TypeMux), &filterBackend{database, blockchain}, false),
+		},
+		reactor:     pposReactor(genesisBlock.Nonce()),
+		snapshotDir: snapshotDir,
+		keys:        known,
+		behaviour:   make(map[discover.NodeID]ValidatorBehaviour),
+	}
+	if err := backend.prepare(); err != nil {
+		backend.Close()
+		return nil, err
+	}
+	return backend, nil
+}
+
+// pposReactor starts the reactor in ppos mode and registers the plugins, as
+// a node of a ppos chain does, once per process. The VRF handler and the
+// reward plugin are reset to the chain of every new backend.
+func pposReactor(genesisNonce []byte) *core.BlockChainReactor {
+	pposReactorOnce.Do(func() {
+		reactor := core.NewBlockChainReactor(new(event.TypeMux))
+		reactor.Start(common.PPOS_VALIDATOR_MODE)
+		reactor.SetVRFhandler(handler.NewVrfHandler(genesisNonce))
+		reactor.SetPluginEventMux()
+
+		reactor.RegisterPlugin(xcom.SlashingRule, plugin.SlashInstance())
+		plugin.SlashInstance().SetDecodeEvidenceFun(evidence.NewEvidence)
+		reactor.RegisterPlugin(xcom.StakingRule, plugin.StakingInstance())
+		reactor.RegisterPlugin(xcom.RestrictingRule, plugin.RestrictingInstance())
+		reactor.RegisterPlugin(xcom.RewardRule, plugin.RewardMgrInstance())
+		reactor.RegisterPlugin(xcom.GovernanceRule, plugin.GovPluginInstance())
+		reactor.SetBeginRule([]int{xcom.SlashingRule, xcom.GovernanceRule})
+		reactor.SetEndRule([]int{xcom.RestrictingRule, xcom.RewardRule, xcom.GovernanceRule, xcom.StakingRule})
+
+		gov.RegisterGovernParamVerifiers()
+	})
+	handler.GetVrfHandlerInstance().Reset(snapshotdb.Instance(), genesisNonce)
+	plugin.RewardMgrInstance().Reset()
+	return core.GetReactorInstance()
+}
+
+// Close stops the blockchain and removes the snapshot database.
+func (b *SimulatedPPOSBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.blockchain.Stop()
+	err := snapshotdb.Instance().Clear()
+	os.RemoveAll(b.snapshotDir)
+	return err
+}
+
+// SetValidatorBehaviour scripts how a validator acts from the pending block on.
+func (b *SimulatedPPOSBackend) SetValidatorBehaviour(node discover.NodeID, behaviour ValidatorBehaviour) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.behaviour[node] = behaviour
+}
+
+// Blockchain returns the simulated chain, to inspect its blocks and states.
+func (b *SimulatedPPOSBackend) Blockchain() *core.BlockChain {
+	return b.blockchain
+}
+
+// Validators returns the validators of the consensus round of the pending block.
+func (b *SimulatedPPOSBackend) Validators() (*cbfttypes.Validators, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.reactor.GetValidator(b.header.Number.Uint64())
+}
+
+// Commit seals the pending block, with all the pending transactions, and
+// imports it as the irreversible head of the chain.
+func (b *SimulatedPPOSBackend) Commit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.commit(); err != nil {
+		panic(err) // This cannot happen unless the simulator is wrong, fail in that case
+	}
+}
+
+// AdvanceRound commits blocks until the end of the current consensus round,
+// the pending transactions going into the first of them.
+func (b *SimulatedPPOSBackend) AdvanceRound() {
+	b.advance(xutil.IsEndOfConsensus)
+}
+
+// AdvanceEpoch commits blocks until the end of the current epoch, the pending
+// transactions going into the first of them.
+func (b *SimulatedPPOSBackend) AdvanceEpoch() {
+	b.advance(xutil.IsEndOfEpoch)
+}
+
+func (b *SimulatedPPOSBackend) advance(isEnd func(uint64) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if err := b.commit(); err != nil {
+			panic(err) // This cannot happen unless the simulator is wrong, fail in that case
+		}
+		if isEnd(b.blockchain.CurrentBlock().NumberU64()) {
+			return
+		}
+	}
+}
+
+// Rollback aborts all pending transactions, reverting to the last committed state.
+func (b *SimulatedPPOSBackend) Rollback() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.reset(nil); err != nil {
+		panic(err)
+	}
+}
+
+// SendTransaction executes the given transaction on top of the pending block.
+// It panics if the transaction is invalid.
+func (b *SimulatedPPOSBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sender, err := types.Sender(types.NewEIP155Signer(b.config.ChainID), tx)
+	if err != nil {
+		panic(fmt.Errorf("invalid transaction: %v", err))
+	}
+	nonce := b.pendingState.GetNonce(sender)
+	if tx.Nonce() != nonce {
+		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
+	}
+	return b.applyTransaction(tx)
+}
+
+// AdjustTime adds a time shift to the simulated clock.
+func (b *SimulatedPPOSBackend) AdjustTime(adjustment time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.timeOffset += int64(adjustment / time.Millisecond)
+	return b.reset(b.txs)
+}
+
+// prepare opens the pending block on top of the chain head, running the begin
+// block hooks of the plugins.
+func (b *SimulatedPPOSBackend) prepare() error {
+	parent := b.blockchain.CurrentBlock()
+	number := new(big.Int).Add(parent.Number(), common.Big1)
+
+	producer, err := b.producerOf(number.Uint64())
+	if err != nil {
+		return err
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     number,
+		GasLimit:   parent.GasLimit(),
+		Time:       new(big.Int).Add(parent.Time(), big.NewInt(int64(xcom.Interval()*1000)+b.timeOffset)),
+		Extra:      make([]byte, 32+consensus.ExtraSeal),
+	}
+	b.reactor.SetPrivateKey(producer)
+	b.reactor.SetWorkerCoinBase(header, discover.PubkeyID(&producer.PublicKey))
+
+	statedb, err := b.blockchain.StateAt(parent.Root())
+	if err != nil {
+		return err
+	}
+	if err := b.reactor.BeginBlocker(header, statedb); err != nil {
+		return err
+	}
+	b.header, b.producer = header, producer
+	b.gasPool = new(core.GasPool).AddGas(header.GasLimit)
+	b.txs, b.receipts = nil, nil
+	b.pendingState = statedb
+	b.pendingBlock = types.NewBlock(header, nil, nil)
+	return nil
+}
+
+// producerOf returns the key of the validator producing the given block, the
+// validators taking turns to produce a number of blocks in each round.
+func (b *SimulatedPPOSBackend) producerOf(number uint64) (*ecdsa.PrivateKey, error) {
+	validators, err := b.reactor.GetValidator(number)
+	if err != nil {
+		return nil, err
+	}
+	turn := int((number - 1) % xutil.ConsensusSize() / xcom.BlocksWillCreate())
+	for i := 0; i < validators.Len(); i++ {
+		node, err := validators.FindNodeByIndex((turn + i) % validators.Len())
+		if err != nil {
+			return nil, err
+		}
+		key, ok := b.keys[node.NodeID]
+		if ok && b.behaviour[node.NodeID] != ValidatorOffline {
+			return key, nil
+		}
+	}
+	return nil, errNoProducer
+}
+
+func (b *SimulatedPPOSBackend) applyTransaction(tx *types.Transaction) error {
+	b.pendingState.Prepare(tx.Hash(), common.Hash{}, len(b.txs))
+	snap := b.pendingState.Snapshot()
+	receipt, _, err := core.ApplyTransaction(b.config, b.blockchain, b.gasPool, b.pendingState, b.header, tx, &b.header.GasUsed, vm.Config{})
+
+	// Calls on the pending state must not look like transactions to the
+	// precompiled contracts, or they would write to the snapshot database.
+	b.pendingState.Prepare(common.Hash{}, common.Hash{}, 0)
+	if err != nil {
+		b.pendingState.RevertToSnapshot(snap)
+		return err
+	}
+	b.txs = append(b.txs, tx)
+	b.receipts = append(b.receipts, receipt)
+	b.pendingBlock = types.NewBlock(b.header, b.txs, b.receipts)
+	return nil
+}
+
+// commit runs the end block hooks of the plugins, seals the pending block and
+// writes it with its state before confirming it to the reactor, then opens the
+// next pending block.
+func (b *SimulatedPPOSBackend) commit() error {
+	header := b.header
+	if err := b.reactor.EndBlocker(header, b.pendingState); err != nil {
+		return err
+	}
+	header.Root = b.pendingState.IntermediateRoot(true)
+
+	sign, err := crypto.Sign(header.SealHash().Bytes(), b.producer)
+	if err != nil {
+		return err
+	}
+	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign)
+	block := types.NewBlock(header, b.txs, b.receipts)
+
+	if err := b.reactor.Flush(block.Header()); err != nil {
+		return err
+	}
+	var logs []*types.Log
+	for _, receipt := range b.receipts {
+		for _, log := range receipt.Logs {
+			log.BlockHash = block.Hash()
+		}
+		logs = append(logs, receipt.Logs...)
+	}
+	if _, err := b.blockchain.WriteBlockWithState(block, b.receipts, b.pendingState); err != nil {
+		return err
+	}
+	if err := b.reactor.OnCommit(block); err != nil {
+		return err
+	}
+	b.blockchain.PostChainEvents([]interface{}{
+		core.ChainEvent{Block: block, Hash: block.Hash(), Logs: logs},
+		core.ChainHeadEvent{Block: block},
+	}, logs)
+
+	b.timeOffset = 0
+	return b.prepare()
+}
+
+// reset discards the pending block and opens a new one with the given
+// transactions.
+func (b *SimulatedPPOSBackend) reset(txs []*types.Transaction) error {
+	// The snapshot database only holds one pending block, the discarded one
+	// is moved aside as a fork which is never committed.
+	b.discarded++
+	fork := crypto.Keccak256Hash(b.header.SealHash().Bytes(), common.Uint64ToBytes(b.discarded))
+	if err := snapshotdb.Instance().Flush(fork, b.header.Number); err != nil {
+		return err
+	}
+	if err := b.prepare(); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err := b.applyTransaction(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
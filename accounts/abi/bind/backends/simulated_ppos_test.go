@@ -0,0 +1,157 @@
+package backends_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind/backends"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+func newValidatorKeys(n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	return keys
+}
+
+// TestSimulatedPPOSBackendSlashing checks that a validator missing its turns
+// for a whole consensus round is slashed for its low block rate in the next one.
+func TestSimulatedPPOSBackendSlashing(t *testing.T) {
+	keys := newValidatorKeys(4)
+	sim, err := backends.NewSimulatedPPOSBackend(core.GenesisAlloc{}, 100000000, keys)
+	if err != nil {
+		t.Fatalf("failed to create backend: %v", err)
+	}
+	defer sim.Close()
+
+	offline := discover.PubkeyID(&keys[3].PublicKey)
+	sim.SetValidatorBehaviour(offline, backends.ValidatorOffline)
+
+	sim.AdvanceRound()
+	if number := sim.Blockchain().CurrentBlock().NumberU64(); number != xutil.ConsensusSize() {
+		t.Fatalf("head mismatch after a round: have %d, want %d", number, xutil.ConsensusSize())
+	}
+	sim.AdvanceRound()
+
+	addr, _ := xutil.NodeId2Addr(offline)
+	can, err := plugin.StakingInstance().GetCandidateInfo(common.ZeroHash, addr)
+	if err != nil {
+		t.Fatalf("failed to get candidate: %v", err)
+	}
+	if !can.IsInvalid() {
+		t.Errorf("offline validator not slashed, status %d", can.Status)
+	}
+}
+
+// TestSimulatedPPOSBackendAdvanceEpoch checks that advancing an epoch stops at
+// its last block, the pending transactions going into the first one.
+func TestSimulatedPPOSBackendAdvanceEpoch(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		to     = common.Address{1}
+	)
+	sim, err := backends.NewSimulatedPPOSBackend(core.GenesisAlloc{from: {Balance: big.NewInt(1000000000)}}, 100000000, newValidatorKeys(4))
+	if err != nil {
+		t.Fatalf("failed to create backend: %v", err)
+	}
+	defer sim.Close()
+
+	tx, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), nil), types.NewEIP155Signer(big.NewInt(1337)), key)
+	if err := sim.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	sim.AdvanceEpoch()
+	if number := sim.Blockchain().CurrentBlock().NumberU64(); number != xutil.CalcBlocksEachEpoch() {
+		t.Fatalf("head mismatch after an epoch: have %d, want %d", number, xutil.CalcBlocksEachEpoch())
+	}
+	if txs := sim.Blockchain().GetBlockByNumber(1).Transactions(); len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+		t.Errorf("first block transactions mismatch: have %v, want %x", txs, tx.Hash())
+	}
+	sim.AdvanceEpoch()
+	if number := sim.Blockchain().CurrentBlock().NumberU64(); number != 2*xutil.CalcBlocksEachEpoch() {
+		t.Fatalf("head mismatch after two epochs: have %d, want %d", number, 2*xutil.CalcBlocksEachEpoch())
+	}
+}
+
+// TestSimulatedPPOSBackendRollback checks that rolled back transactions are
+// neither in the pending state nor in the next committed block.
+func TestSimulatedPPOSBackendRollback(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		to     = common.Address{1}
+		ctx    = context.Background()
+	)
+	sim, err := backends.NewSimulatedPPOSBackend(core.GenesisAlloc{from: {Balance: big.NewInt(1000000000)}}, 100000000, newValidatorKeys(4))
+	if err != nil {
+		t.Fatalf("failed to create backend: %v", err)
+	}
+	defer sim.Close()
+
+	tx, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), nil), types.NewEIP155Signer(big.NewInt(1337)), key)
+	if err := sim.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	sim.Rollback()
+	if nonce, _ := sim.PendingNonceAt(ctx, from); nonce != 0 {
+		t.Errorf("pending nonce mismatch after rollback: have %d, want 0", nonce)
+	}
+	sim.Commit()
+	if txs := sim.Blockchain().CurrentBlock().Transactions(); len(txs) != 0 {
+		t.Errorf("rolled back transactions committed: %v", txs)
+	}
+	if balance, _ := sim.BalanceAt(ctx, to, nil); balance.Sign() != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want 0", balance)
+	}
+	// The rolled back nonce can be used again
+	if err := sim.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send transaction again: %v", err)
+	}
+	sim.Commit()
+	if balance, _ := sim.BalanceAt(ctx, to, nil); balance.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want 1", balance)
+	}
+}
+
+// TestSimulatedPPOSBackendSequential checks that a backend created after
+// another one was closed runs the plugins against its own chain.
+func TestSimulatedPPOSBackendSequential(t *testing.T) {
+	first, err := backends.NewSimulatedPPOSBackend(core.GenesisAlloc{}, 100000000, newValidatorKeys(4))
+	if err != nil {
+		t.Fatalf("failed to create first backend: %v", err)
+	}
+	first.AdvanceRound()
+	first.Close()
+
+	keys := newValidatorKeys(4)
+	second, err := backends.NewSimulatedPPOSBackend(core.GenesisAlloc{}, 100000000, keys)
+	if err != nil {
+		t.Fatalf("failed to create second backend: %v", err)
+	}
+	defer second.Close()
+
+	second.AdvanceRound()
+	if number := second.Blockchain().CurrentBlock().NumberU64(); number != xutil.ConsensusSize() {
+		t.Fatalf("head mismatch after a round: have %d, want %d", number, xutil.ConsensusSize())
+	}
+	validators, err := second.Validators()
+	if err != nil {
+		t.Fatalf("failed to get validators: %v", err)
+	}
+	for _, key := range keys {
+		if _, err := validators.FindNodeByID(discover.PubkeyID(&key.PublicKey)); err != nil {
+			t.Errorf("validator %x of the second chain missing: %v", crypto.PubkeyToAddress(key.PublicKey), err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package backends_test
+
+import (
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind/backends"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/life/utils"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+var testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+// TestSimulatedBackendWasm checks that a WASM contract can be invoked through
+// the WASM bindings. Contract creation is not allowed by the state transition,
+// so the contract is placed in the genesis allocation.
+func TestSimulatedBackendWasm(t *testing.T) {
+	code, err := ioutil.ReadFile("../../../../life/contract/inputtest.wasm")
+	if err != nil {
+		t.Fatalf("failed to read contract code: %v", err)
+	}
+	abiJSON, err := ioutil.ReadFile("../../../../life/contract/inputtest.cpp.abi.json")
+	if err != nil {
+		t.Fatalf("failed to read contract abi: %v", err)
+	}
+	deployed, err := rlp.EncodeToBytes([][]byte{utils.Int64ToBytes(0), code, abiJSON})
+	if err != nil {
+		t.Fatalf("failed to encode contract: %v", err)
+	}
+	var (
+		auth     = bind.NewKeyedTransactor(testKey)
+		contract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: big.NewInt(10000000000000000)},
+		contract:  {Balance: new(big.Int), Code: deployed},
+	}, 100000000)
+
+	c, err := bind.NewWasmBoundContract(contract, abiJSON, sim, sim, sim)
+	if err != nil {
+		t.Fatalf("failed to bind contract: %v", err)
+	}
+	if _, err := c.Transact(auth, "set", int64(42)); err != nil {
+		t.Fatalf("failed to transact: %v", err)
+	}
+	sim.Commit()
+
+	var ret int64
+	if err := c.Call(nil, &ret, "get"); err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+	if ret != 42 {
+		t.Errorf("result mismatch: have %d, want 42", ret)
+	}
+}
@@ -127,24 +127,44 @@ func (c *BoundContract) Call(opts *CallOpts, result interface{}, method string,
 	if err != nil {
 		return err
 	}
+	output, err := c.call(opts, input)
+	if err != nil {
+		return err
+	}
+	return c.abi.Unpack(result, method, output)
+}
+
+// RawCall executes the already encoded input against the contract and returns
+// the raw output, for contracts whose calldata is not ABI encoded.
+func (c *BoundContract) RawCall(opts *CallOpts, input []byte) ([]byte, error) {
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	return c.call(opts, input)
+}
+
+// call executes the given raw input against the contract and returns the
+// output, making sure there is code at the address if the output is empty.
+func (c *BoundContract) call(opts *CallOpts, input []byte) ([]byte, error) {
 	var (
 		msg    = ethereum.CallMsg{From: opts.From, To: &c.address, Data: input}
 		ctx    = ensureContext(opts.Context)
 		code   []byte
 		output []byte
+		err    error
 	)
 	if opts.Pending {
 		pb, ok := c.caller.(PendingContractCaller)
 		if !ok {
-			return ErrNoPendingState
+			return nil, ErrNoPendingState
 		}
 		output, err = pb.PendingCallContract(ctx, msg)
 		if err == nil && len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
 			if code, err = pb.PendingCodeAt(ctx, c.address); err != nil {
-				return err
+				return nil, err
 			} else if len(code) == 0 {
-				return ErrNoCode
+				return nil, ErrNoCode
 			}
 		}
 	} else {
@@ -152,16 +172,13 @@ func (c *BoundContract) Call(opts *CallOpts, result interface{}, method string,
 		if err == nil && len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
 			if code, err = c.caller.CodeAt(ctx, c.address, opts.BlockNumber); err != nil {
-				return err
+				return nil, err
 			} else if len(code) == 0 {
-				return ErrNoCode
+				return nil, ErrNoCode
 			}
 		}
 	}
-	if err != nil {
-		return err
-	}
-	return c.abi.Unpack(result, method, output)
+	return output, err
 }
 
 // Transact invokes the (paid) contract method with params as input values.
@@ -174,6 +191,12 @@ func (c *BoundContract) Transact(opts *TransactOpts, method string, params ...in
 	return c.transact(opts, &c.address, input)
 }
 
+// RawTransact initiates a transaction with the already encoded calldata, for
+// contracts whose calldata is not ABI encoded.
+func (c *BoundContract) RawTransact(opts *TransactOpts, calldata []byte) (*types.Transaction, error) {
+	return c.transact(opts, &c.address, calldata)
+}
+
 // Transfer initiates a plain transaction to move funds to the contract, calling
 // its default method if one is available.
 func (c *BoundContract) Transfer(opts *TransactOpts) (*types.Transaction, error) {
@@ -258,6 +281,12 @@ func (c *BoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]int
 	if err != nil {
 		return nil, nil, err
 	}
+	return c.filterLogs(opts, topics)
+}
+
+// filterLogs retrieves the past logs of the contract matching the given topic
+// set and streams them through the returned channel.
+func (c *BoundContract) filterLogs(opts *FilterOpts, topics [][]common.Hash) (chan types.Log, event.Subscription, error) {
 	// Start the background filtering
 	logs := make(chan types.Log, 128)
 
@@ -307,6 +336,12 @@ func (c *BoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]inter
 	if err != nil {
 		return nil, nil, err
 	}
+	return c.watchLogs(opts, topics)
+}
+
+// watchLogs subscribes to the future logs of the contract matching the given
+// topic set.
+func (c *BoundContract) watchLogs(opts *WatchOpts, topics [][]common.Hash) (chan types.Log, event.Subscription, error) {
 	// Start the background filtering
 	logs := make(chan types.Log, 128)
 
@@ -0,0 +1,380 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/event"
+	"github.com/PlatONnetwork/PlatON-Go/life/utils"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// Transaction types of the RLP encoded [txType][funcName][args...] input the
+// WASM interpreter expects, see core/vm/interpreter_life.go.
+const (
+	wasmTxTypeInvoke = 1 // Invokes a function, returning 32 byte aligned results
+	wasmTxTypeCall   = 9 // Invokes a function, returning compact results
+)
+
+// wasmIntWidth is the encoded width in bytes of the integer types a WASM ABI
+// may use.
+var wasmIntWidth = map[string]int{
+	"int8": 1, "uint8": 1,
+	"int16": 2, "uint16": 2,
+	"int32": 4, "uint32": 4, "int": 4, "uint": 4,
+	"int64": 8, "uint64": 8,
+}
+
+// WasmBoundContract is the base wrapper object that reflects a WASM contract on
+// the PlatON network. It contains a collection of methods that are used by the
+// higher level contract bindings to operate.
+type WasmBoundContract struct {
+	*BoundContract
+	abi utils.WasmAbi // Parsed WASM ABI to look up functions and events
+}
+
+// NewWasmBoundContract creates a low level WASM contract interface through
+// which calls and transactions may be made through.
+func NewWasmBoundContract(address common.Address, abiJSON []byte, caller ContractCaller, transactor ContractTransactor, filterer ContractFilterer) (*WasmBoundContract, error) {
+	var parsed utils.WasmAbi
+	if err := parsed.FromJson(abiJSON); err != nil {
+		return nil, err
+	}
+	return &WasmBoundContract{
+		BoundContract: NewBoundContract(address, abi.ABI{}, caller, transactor, filterer),
+		abi:           parsed,
+	}, nil
+}
+
+// Call invokes the (constant) contract function with params as input values and
+// sets the output to result, which must be a pointer to the Go type matching
+// the return type of the function.
+func (c *WasmBoundContract) Call(opts *CallOpts, result interface{}, method string, params ...interface{}) error {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	fn, err := c.function(method)
+	if err != nil {
+		return err
+	}
+	input, err := packWasmInput(wasmTxTypeCall, fn, params)
+	if err != nil {
+		return err
+	}
+	output, err := c.call(opts, input)
+	if err != nil {
+		return err
+	}
+	if len(fn.Outputs) == 0 || fn.Outputs[0].Type == "void" || result == nil {
+		return nil
+	}
+	return unpackWasmValue(fn.Outputs[0].Type, output, result)
+}
+
+// Transact invokes the (paid) contract function with params as input values.
+func (c *WasmBoundContract) Transact(opts *TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	fn, err := c.function(method)
+	if err != nil {
+		return nil, err
+	}
+	input, err := packWasmInput(wasmTxTypeInvoke, fn, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.transact(opts, &c.address, input)
+}
+
+// FilterLogs filters contract logs for past blocks, returning the necessary
+// channels to construct a strongly typed bound iterator on top of them. The
+// query holds one rule set per indexed input of the event.
+func (c *WasmBoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(FilterOpts)
+	}
+	topics, err := c.topics(name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.filterLogs(opts, topics)
+}
+
+// WatchLogs filters subscribes to contract logs for future blocks, returning a
+// subscription object that can be used to tear down the watcher.
+func (c *WasmBoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(WatchOpts)
+	}
+	topics, err := c.topics(name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.watchLogs(opts, topics)
+}
+
+// UnpackLog unpacks a retrieved log into the provided struct. The indexed
+// inputs are taken from the topics following the event selector, the others
+// from the RLP list held in the log data.
+func (c *WasmBoundContract) UnpackLog(out interface{}, event string, log types.Log) error {
+	ev, err := c.event(event)
+	if err != nil {
+		return err
+	}
+	var data [][]byte
+	if len(log.Data) > 0 {
+		if err := rlp.DecodeBytes(log.Data, &data); err != nil {
+			return err
+		}
+	}
+	value := reflect.ValueOf(out).Elem()
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("wasm: cannot unpack log into %v", value.Type())
+	}
+	topics := log.Topics
+	if len(topics) > 0 {
+		topics = topics[1:]
+	}
+	if indexed := ev.IndexedInputs(); len(topics) != len(indexed) {
+		return fmt.Errorf("wasm: event %s has %d indexed inputs, log has %d topics", event, len(indexed), len(topics))
+	}
+	for i, input := range ev.Inputs {
+		field := value.FieldByName(wasmArgName(input.Name, i))
+		if !field.IsValid() {
+			return fmt.Errorf("wasm: field %s not found in %v", wasmArgName(input.Name, i), value.Type())
+		}
+		var enc []byte
+		if input.Indexed {
+			enc, topics = topics[0].Bytes(), topics[1:]
+			if input.Type == "string" {
+				// Indexed strings are only available as their hash
+				field.Set(reflect.ValueOf(common.BytesToHash(enc)))
+				continue
+			}
+		} else {
+			if len(data) == 0 {
+				return fmt.Errorf("wasm: missing data for input %d of event %s", i, event)
+			}
+			enc, data = data[0], data[1:]
+		}
+		if err := unpackWasmValue(input.Type, enc, field.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// function looks up the function with the given name in the contract ABI.
+func (c *WasmBoundContract) function(name string) (*utils.AbiStruct, error) {
+	for i, v := range c.abi.AbiArr {
+		if v.Type == "function" && v.Name == name {
+			return &c.abi.AbiArr[i], nil
+		}
+	}
+	return nil, fmt.Errorf("wasm: function %s not found", name)
+}
+
+// event looks up the event with the given name in the contract ABI.
+func (c *WasmBoundContract) event(name string) (*utils.AbiStruct, error) {
+	for i, v := range c.abi.AbiArr {
+		if v.Type == "event" && v.Name == name {
+			return &c.abi.AbiArr[i], nil
+		}
+	}
+	return nil, fmt.Errorf("wasm: event %s not found", name)
+}
+
+// topics constructs the topic set matching the given event and the rules for
+// its indexed inputs.
+func (c *WasmBoundContract) topics(name string, query [][]interface{}) ([][]common.Hash, error) {
+	ev, err := c.event(name)
+	if err != nil {
+		return nil, err
+	}
+	indexed := ev.IndexedInputs()
+	if len(query) > len(indexed) {
+		return nil, fmt.Errorf("wasm: event %s has %d indexed inputs, got %d rules", name, len(indexed), len(query))
+	}
+	topics := [][]common.Hash{{WasmEventID(name)}}
+	for i, rules := range query {
+		var topic []common.Hash
+		for _, rule := range rules {
+			hash, err := wasmTopic(indexed[i].Type, rule)
+			if err != nil {
+				return nil, err
+			}
+			topic = append(topic, hash)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+// WasmEventID returns the first topic of the logs emitted for the given event,
+// which is the hash of the event name.
+func WasmEventID(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte(name))
+}
+
+// wasmArgName returns the Go field name of the i'th argument, naming anonymous
+// ones after their position.
+func wasmArgName(name string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("Arg%d", i)
+	}
+	return capitalise(name)
+}
+
+// packWasmInput encodes a function invocation into the RLP input layout of the
+// WASM interpreter.
+func packWasmInput(txType int64, fn *utils.AbiStruct, params []interface{}) ([]byte, error) {
+	if len(params) != len(fn.Inputs) {
+		return nil, fmt.Errorf("wasm: argument count mismatch for %s: have %d, want %d", fn.Name, len(params), len(fn.Inputs))
+	}
+	input := [][]byte{utils.Int64ToBytes(txType), []byte(fn.Name)}
+	for i, param := range params {
+		enc, err := packWasmValue(fn.Inputs[i].Type, param)
+		if err != nil {
+			return nil, fmt.Errorf("wasm: argument %d of %s: %v", i, fn.Name, err)
+		}
+		input = append(input, enc)
+	}
+	return rlp.EncodeToBytes(input)
+}
+
+// packWasmValue encodes a single value of the given WASM ABI type: integers as
+// fixed width big endian, booleans as a single byte and strings as is.
+func packWasmValue(typ string, v interface{}) ([]byte, error) {
+	value := reflect.ValueOf(v)
+	switch {
+	case typ == "string":
+		if value.Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot use %T as %s", v, typ)
+		}
+		return []byte(value.String()), nil
+
+	case typ == "bool":
+		if value.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("cannot use %T as %s", v, typ)
+		}
+		if value.Bool() {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+
+	case wasmIntWidth[typ] != 0:
+		var n uint64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = uint64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = value.Uint()
+		default:
+			return nil, fmt.Errorf("cannot use %T as %s", v, typ)
+		}
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, n)
+		return buf[8-wasmIntWidth[typ]:], nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// unpackWasmValue decodes a value of the given WASM ABI type into the value out
+// points to. Integers may be encoded in any width up to eight bytes and are
+// truncated to the width of their type, so both the compact call results and
+// the fixed width topics and log data decode the same way.
+func unpackWasmValue(typ string, enc []byte, out interface{}) error {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("wasm: cannot unpack into %T", out)
+	}
+	value = value.Elem()
+
+	switch {
+	case typ == "string":
+		if value.Kind() != reflect.String {
+			return fmt.Errorf("wasm: cannot unpack %s into %v", typ, value.Type())
+		}
+		value.SetString(string(enc))
+		return nil
+
+	case typ == "bool" || wasmIntWidth[typ] != 0:
+		if len(enc) > 8 {
+			for _, b := range enc[:len(enc)-8] {
+				if b != 0 && b != 0xff {
+					return fmt.Errorf("wasm: %s value overflows: %x", typ, enc)
+				}
+			}
+			enc = enc[len(enc)-8:]
+		}
+		buf := make([]byte, 8)
+		copy(buf[8-len(enc):], enc)
+		n := binary.BigEndian.Uint64(buf)
+
+		switch value.Kind() {
+		case reflect.Bool:
+			value.SetBool(n != 0)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			shift := uint(64 - 8*wasmIntWidth[typ])
+			value.SetInt(int64(n<<shift) >> shift)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			shift := uint(64 - 8*wasmIntWidth[typ])
+			value.SetUint(n << shift >> shift)
+		default:
+			return fmt.Errorf("wasm: cannot unpack %s into %v", typ, value.Type())
+		}
+		return nil
+	}
+	return fmt.Errorf("wasm: unsupported type %s", typ)
+}
+
+// wasmTopic converts a filter rule for an indexed input of the given type into
+// a topic: integers and booleans are right aligned, strings are hashed.
+func wasmTopic(typ string, rule interface{}) (common.Hash, error) {
+	if hash, ok := rule.(common.Hash); ok {
+		return hash, nil
+	}
+	enc, err := packWasmValue(typ, rule)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if typ == "string" {
+		return crypto.Keccak256Hash(enc), nil
+	}
+	return common.BytesToHash(enc), nil
+}
+
+// wasmTypeGo maps a WASM ABI type to the Go type used in the bindings.
+func wasmTypeGo(typ string) (string, error) {
+	switch typ {
+	case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64", "bool", "string":
+		return typ, nil
+	case "int":
+		return "int32", nil
+	case "uint":
+		return "uint32", nil
+	}
+	return "", fmt.Errorf("wasm: unsupported type %s", typ)
+}
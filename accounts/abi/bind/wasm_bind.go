@@ -0,0 +1,165 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/utils"
+	"golang.org/x/tools/imports"
+)
+
+// BindWasm generates a Go wrapper around a WASM contract ABI, the counterpart
+// of Bind for contracts described by the WasmAbi JSON format. Bytecodes are the
+// hex encoded WASM modules used to generate the deploy methods. Only Go
+// bindings are supported.
+func BindWasm(types []string, abis []string, bytecodes []string, pkg string, lang Lang) (string, error) {
+	if lang != LangGo {
+		return "", fmt.Errorf("wasm bindings are only supported for Go")
+	}
+	contracts := make(map[string]*tmplWasmContract)
+
+	for i := 0; i < len(types); i++ {
+		// Parse the actual ABI to generate the binding for
+		var wasmABI utils.WasmAbi
+		if err := wasmABI.FromJson([]byte(abis[i])); err != nil {
+			return "", err
+		}
+		// Strip any whitespace from the JSON ABI
+		strippedABI := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, abis[i])
+
+		// Extract the call and transact functions and the events
+		var (
+			calls     = make(map[string]*tmplWasmMethod)
+			transacts = make(map[string]*tmplWasmMethod)
+			events    = make(map[string]*tmplWasmEvent)
+		)
+		for _, original := range wasmABI.AbiArr {
+			inputs, err := wasmArgs(original.Inputs)
+			if err != nil {
+				return "", fmt.Errorf("%s: %v", original.Name, err)
+			}
+			switch original.Type {
+			case "function":
+				method := &tmplWasmMethod{
+					Original: original.Name,
+					Name:     capitalise(original.Name),
+					Inputs:   inputs,
+				}
+				returns := "void"
+				if len(original.Outputs) > 0 && original.Outputs[0].Type != "void" {
+					typ, err := wasmTypeGo(original.Outputs[0].Type)
+					if err != nil {
+						return "", fmt.Errorf("%s: %v", original.Name, err)
+					}
+					method.Output = typ
+					returns = original.Outputs[0].Type
+				}
+				method.Signature = fmt.Sprintf("%s %s(%s)", returns, original.Name, wasmSignature(original.Inputs))
+
+				if original.Constant == "true" {
+					calls[original.Name] = method
+				} else {
+					transacts[original.Name] = method
+				}
+			case "event":
+				events[original.Name] = &tmplWasmEvent{
+					Original:  original.Name,
+					Name:      capitalise(original.Name),
+					Inputs:    inputs,
+					Signature: fmt.Sprintf("event %s(%s)", original.Name, wasmSignature(original.Inputs)),
+				}
+			}
+		}
+		contracts[types[i]] = &tmplWasmContract{
+			Type:      capitalise(types[i]),
+			InputABI:  strings.Replace(strippedABI, "\"", "\\\"", -1),
+			InputBin:  strings.TrimSpace(bytecodes[i]),
+			Calls:     calls,
+			Transacts: transacts,
+			Events:    events,
+		}
+	}
+	// Generate the contract template data content and render it
+	data := &tmplWasmData{
+		Package:   pkg,
+		Contracts: contracts,
+	}
+	buffer := new(bytes.Buffer)
+
+	tmpl := template.Must(template.New("").Parse(tmplSourceWasmGo))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	// Pass the code through goimports to clean it up and double check
+	code, err := imports.Process(".", buffer.Bytes(), nil)
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+	return string(code), nil
+}
+
+// wasmArgs converts the inputs of a WASM ABI entry into template arguments,
+// naming anonymous ones after their position.
+func wasmArgs(params []utils.InputParam) ([]tmplWasmArg, error) {
+	args := make([]tmplWasmArg, len(params))
+	for i, param := range params {
+		typ, err := wasmTypeGo(param.Type)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = tmplWasmArg{
+			Name:    param.Name,
+			Field:   wasmArgName(param.Name, i),
+			Type:    typ,
+			Indexed: param.Indexed,
+		}
+		if param.Name == "" {
+			args[i].Name = fmt.Sprintf("arg%d", i)
+		}
+		if param.Indexed && param.Type == "string" {
+			args[i].Topic = "common.Hash"
+		} else {
+			args[i].Topic = typ
+		}
+	}
+	return args, nil
+}
+
+// wasmSignature renders the inputs of a WASM ABI entry for documentation.
+func wasmSignature(params []utils.InputParam) string {
+	sig := make([]string, len(params))
+	for i, param := range params {
+		sig[i] = param.Type
+		if param.Indexed {
+			sig[i] += " indexed"
+		}
+		if param.Name != "" {
+			sig[i] += " " + param.Name
+		}
+	}
+	return strings.Join(sig, ", ")
+}
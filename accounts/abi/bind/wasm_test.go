@@ -0,0 +1,179 @@
+package bind_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethereum "github.com/PlatONnetwork/PlatON-Go"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+const wasmTestABI = `[
+	{"name": "set", "inputs": [{"name": "a", "type": "int64"}], "outputs": [], "constant": "false", "type": "function"},
+	{"name": "get", "inputs": [], "outputs": [{"name": "", "type": "int64"}], "constant": "true", "type": "function"},
+	{"name": "echo", "inputs": [{"name": "a", "type": "int16"}, {"name": "b", "type": "string"}], "outputs": [{"name": "", "type": "string"}], "constant": "true", "type": "function"},
+	{"name": "Transfer", "inputs": [{"name": "id", "type": "uint64", "indexed": true}, {"name": "tag", "type": "string", "indexed": true}, {"name": "amount", "type": "int16"}, {"type": "string"}], "type": "event"}
+]`
+
+type wasmTestEvent struct {
+	Id     uint64
+	Tag    common.Hash
+	Amount int16
+	Arg3   string
+	Raw    types.Log
+}
+
+type mockWasmBackend struct {
+	input  []byte
+	output []byte
+	query  ethereum.FilterQuery
+}
+
+func (mb *mockWasmBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1, 2, 3}, nil
+}
+
+func (mb *mockWasmBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	mb.input = call.Data
+	return mb.output, nil
+}
+
+func (mb *mockWasmBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	mb.query = query
+	return nil, nil
+}
+
+func (mb *mockWasmBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	mb.query = query
+	return nil, nil
+}
+
+func wasmInput(elems ...[]byte) []byte {
+	input, _ := rlp.EncodeToBytes(elems)
+	return input
+}
+
+func TestWasmCall(t *testing.T) {
+	mb := new(mockWasmBackend)
+	c, err := bind.NewWasmBoundContract(common.Address{}, []byte(wasmTestABI), mb, nil, mb)
+	if err != nil {
+		t.Fatalf("failed to bind contract: %v", err)
+	}
+
+	mb.output = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfb}
+	var ret int64
+	if err := c.Call(nil, &ret, "get"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if ret != -5 {
+		t.Errorf("result mismatch: have %d, want -5", ret)
+	}
+	if want := wasmInput([]byte{0, 0, 0, 0, 0, 0, 0, 9}, []byte("get")); !bytes.Equal(mb.input, want) {
+		t.Errorf("input mismatch: have %x, want %x", mb.input, want)
+	}
+
+	mb.output = []byte("hello")
+	var str string
+	if err := c.Call(nil, &str, "echo", int16(-2), "x"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if str != "hello" {
+		t.Errorf("result mismatch: have %q, want %q", str, "hello")
+	}
+	if want := wasmInput([]byte{0, 0, 0, 0, 0, 0, 0, 9}, []byte("echo"), []byte{0xff, 0xfe}, []byte("x")); !bytes.Equal(mb.input, want) {
+		t.Errorf("input mismatch: have %x, want %x", mb.input, want)
+	}
+
+	if err := c.Call(nil, &str, "echo", "x", int16(-2)); err == nil {
+		t.Errorf("expected error for mistyped arguments")
+	}
+	if err := c.Call(nil, &ret, "missing"); err == nil {
+		t.Errorf("expected error for unknown function")
+	}
+}
+
+func TestWasmFilterLogs(t *testing.T) {
+	mb := new(mockWasmBackend)
+	c, err := bind.NewWasmBoundContract(common.Address{}, []byte(wasmTestABI), mb, nil, mb)
+	if err != nil {
+		t.Fatalf("failed to bind contract: %v", err)
+	}
+	if _, _, err := c.FilterLogs(nil, "Transfer", []interface{}{uint64(7)}, []interface{}{"tag"}); err != nil {
+		t.Fatalf("filter failed: %v", err)
+	}
+	want := [][]common.Hash{
+		{crypto.Keccak256Hash([]byte("Transfer"))},
+		{common.BigToHash(big.NewInt(7))},
+		{crypto.Keccak256Hash([]byte("tag"))},
+	}
+	if len(mb.query.Topics) != len(want) {
+		t.Fatalf("topic count mismatch: have %d, want %d", len(mb.query.Topics), len(want))
+	}
+	for i := range want {
+		if len(mb.query.Topics[i]) != 1 || mb.query.Topics[i][0] != want[i][0] {
+			t.Errorf("topic %d mismatch: have %x, want %x", i, mb.query.Topics[i], want[i])
+		}
+	}
+	if _, _, err := c.FilterLogs(nil, "Transfer", nil, nil, nil); err == nil {
+		t.Errorf("expected error for too many rules")
+	}
+}
+
+func TestWasmUnpackLog(t *testing.T) {
+	c, err := bind.NewWasmBoundContract(common.Address{}, []byte(wasmTestABI), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to bind contract: %v", err)
+	}
+	log := types.Log{
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer")),
+			common.BigToHash(big.NewInt(7)),
+			crypto.Keccak256Hash([]byte("tag")),
+		},
+		Data: wasmInput([]byte{0xff, 0xfe}, []byte("memo")),
+	}
+	var ev wasmTestEvent
+	if err := c.UnpackLog(&ev, "Transfer", log); err != nil {
+		t.Fatalf("unpack failed: %v", err)
+	}
+	if ev.Id != 7 || ev.Tag != log.Topics[2] || ev.Amount != -2 || ev.Arg3 != "memo" {
+		t.Errorf("event mismatch: %+v", ev)
+	}
+	// Every indexed input needs its topic
+	log.Topics = log.Topics[:2]
+	if err := c.UnpackLog(&ev, "Transfer", log); err == nil {
+		t.Errorf("expected error for missing topic")
+	}
+}
+
+func TestBindWasm(t *testing.T) {
+	code, err := bind.BindWasm([]string{"token"}, []string{wasmTestABI}, []string{"0061736d01000000"}, "bindtest", bind.LangGo)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	for _, want := range []string{
+		"const TokenBin = `0061736d01000000`",
+		"func (_Token *TokenCaller) Get(opts *bind.CallOpts) (int64, error)",
+		"func (_Token *TokenCaller) Echo(opts *bind.CallOpts, a int16, b string) (string, error)",
+		"func (_Token *TokenTransactor) Set(opts *bind.TransactOpts, a int64) (*types.Transaction, error)",
+		"func (_Token *TokenFilterer) FilterTransfer(opts *bind.FilterOpts, id []uint64, tag []string) (*TokenTransferIterator, error)",
+		"func (_Token *TokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *TokenTransfer, id []uint64, tag []string) (event.Subscription, error)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding is missing %q", want)
+		}
+	}
+	if _, err := bind.BindWasm([]string{"token"}, []string{`[{"name": "f", "inputs": [{"type": "float64"}], "type": "function"}]`}, []string{""}, "bindtest", bind.LangGo); err == nil {
+		t.Errorf("expected error for unsupported type")
+	}
+	if _, err := bind.BindWasm([]string{"token"}, []string{wasmTestABI}, []string{""}, "bindtest", bind.LangJava); err == nil {
+		t.Errorf("expected error for unsupported language")
+	}
+}
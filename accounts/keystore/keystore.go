@@ -278,7 +278,7 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *b
 		return nil, ErrLocked
 	}
 	// Depending on the presence of the chain ID, sign with EIP155 or homestead
-	return types.SignTx(tx, types.NewEIP155Signer(chainID), unlockedKey.PrivateKey)
+	return types.SignTx(tx, txSigner(tx, chainID), unlockedKey.PrivateKey)
 }
 
 // SignHashWithPassphrase signs hash if the private key matching the given address
@@ -303,7 +303,19 @@ func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string,
 	defer zeroKey(key.PrivateKey)
 
 	// Depending on the presence of the chain ID, sign with EIP155 or homestead
-	return types.SignTx(tx, types.NewEIP155Signer(chainID), key.PrivateKey)
+	return types.SignTx(tx, txSigner(tx, chainID), key.PrivateKey)
+}
+
+// txSigner returns the signer for the sender of tx, which commits to the payer
+// for sponsored transactions and to every call for batch ones.
+func txSigner(tx *types.Transaction, chainID *big.Int) types.Signer {
+	switch tx.Type() {
+	case types.SponsoredTxType:
+		return types.NewSponsorSigner(chainID)
+	case types.BatchTxType:
+		return types.NewBatchSigner(chainID)
+	}
+	return types.NewEIP155Signer(chainID)
 }
 
 // Unlock unlocks the given account indefinitely.
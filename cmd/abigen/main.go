@@ -17,6 +17,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -37,6 +38,8 @@ var (
 	solcFlag = flag.String("solc", "solc", "Solidity compiler to use if source builds are requested")
 	excFlag  = flag.String("exc", "", "Comma separated types to exclude from binding")
 
+	wasmFlag = flag.Bool("wasm-abi", false, "Bind a WASM contract ABI, with --bin pointing to the compiled WASM module")
+
 	pkgFlag  = flag.String("pkg", "", "Package name to generate the binding into")
 	outFlag  = flag.String("out", "", "Output file for the generated binding (default = stdout)")
 	langFlag = flag.String("lang", "go", "Destination language for the bindings (go, java, objc)")
@@ -52,6 +55,9 @@ func main() {
 	} else if (*abiFlag != "" || *binFlag != "" || *typFlag != "") && *solFlag != "" {
 		fmt.Printf("Contract ABI (--abi), bytecode (--bin) and type (--type) flags are mutually exclusive with the Solidity source (--sol) flag\n")
 		os.Exit(-1)
+	} else if *wasmFlag && (*solFlag != "" || *abiFlag == "-") {
+		fmt.Printf("WASM contract ABI (--wasm-abi) bindings require an ABI file (--abi)\n")
+		os.Exit(-1)
 	}
 	if *pkgFlag == "" {
 		fmt.Printf("No destination package specified (--pkg)\n")
@@ -125,6 +131,10 @@ func main() {
 				os.Exit(-1)
 			}
 		}
+		if *wasmFlag {
+			// WASM modules are binary, embed them hex encoded
+			bin = []byte(hex.EncodeToString(bin))
+		}
 		bins = append(bins, string(bin))
 
 		kind := *typFlag
@@ -134,7 +144,11 @@ func main() {
 		types = append(types, kind)
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, *pkgFlag, lang)
+	generate := bind.Bind
+	if *wasmFlag {
+		generate = bind.BindWasm
+	}
+	code, err := generate(types, abis, bins, *pkgFlag, lang)
 	if err != nil {
 		fmt.Printf("Failed to generate ABI binding: %v\n", err)
 		os.Exit(-1)
@@ -23,8 +23,10 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
 
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -37,6 +39,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/console"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/eth/downloader"
@@ -142,6 +145,7 @@ The export-preimages command export hash preimages to an RLP encoded stream`,
 			utils.CacheFlag,
 			//	utils.SyncModeFlag,
 			utils.TestnetFlag,
+			utils.DBAncientFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -154,10 +158,12 @@ The first argument must be the directory containing the blockchain to download f
 		ArgsUsage: " ",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.DBAncientFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
-Remove blockchain and state databases`,
+Remove blockchain and state databases, including an ancient store kept outside
+the chain database directory`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -308,7 +314,7 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*ethdb.LDBDatabase)
+	db := rawdb.LevelDB(chainDb)
 
 	stats, err := db.LDB().GetProperty("leveldb.stats")
 	if err != nil {
@@ -399,7 +405,7 @@ func importPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := rawdb.LevelDB(utils.MakeChainDatabase(ctx, stack))
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -415,7 +421,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := rawdb.LevelDB(utils.MakeChainDatabase(ctx, stack))
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -439,7 +445,7 @@ func copyDb(ctx *cli.Context) error {
 
 	dl := downloader.New(syncmode, chainDb, localSnapshotDB, new(event.TypeMux), chain, nil, nil, nil)
 	// Create a source peer to satisfy downloader requests from
-	db, err := ethdb.NewLDBDatabase(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	db, err := openSourceDb(ctx, ctx.Args().First())
 	if err != nil {
 		return err
 	}
@@ -470,7 +476,7 @@ func copyDb(ctx *cli.Context) error {
 	// Compact the entire database to remove any sync overhead
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
+	if err = rawdb.LevelDB(chainDb).LDB().CompactRange(util.Range{}); err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
@@ -478,14 +484,48 @@ func copyDb(ctx *cli.Context) error {
 	return nil
 }
 
+// openSourceDb opens the chain database copied from by copydb, attaching its
+// ancient store if one exists at the configured location.
+func openSourceDb(ctx *cli.Context, path string) (ethdb.Database, error) {
+	db, err := ethdb.NewLDBDatabase(path, ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	if err != nil {
+		return nil, err
+	}
+	freezer := ctx.GlobalString(utils.DBAncientFlag.Name)
+	if freezer == "" {
+		return db, nil
+	}
+	if !filepath.IsAbs(freezer) {
+		freezer = filepath.Join(path, freezer)
+	}
+	if !common.FileExist(freezer) {
+		return db, nil
+	}
+	frdb, err := rawdb.NewDatabaseWithFreezer(db, freezer)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
 func removeDB(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 
-	for _, name := range []string{"chaindata", "lightchaindata"} {
+	dbs := [][2]string{{"chaindata", stack.ResolvePath("chaindata")}, {"lightchaindata", stack.ResolvePath("lightchaindata")}}
+
+	// An ancient store inside the chain database goes along with it, any other
+	// location has to be removed on its own
+	if freezer := ctx.GlobalString(utils.DBAncientFlag.Name); filepath.IsAbs(freezer) {
+		if rel, err := filepath.Rel(dbs[0][1], freezer); err != nil || strings.HasPrefix(rel, "..") {
+			dbs = append(dbs, [2]string{"ancient", freezer})
+		}
+	}
+	for _, db := range dbs {
 		// Ensure the database exists in the first place
+		name, dbdir := db[0], db[1]
 		logger := log.New("database", name)
 
-		dbdir := stack.ResolvePath(name)
 		if !common.FileExist(dbdir) {
 			logger.Info("Database doesn't exist, skipping", "path", dbdir)
 			continue
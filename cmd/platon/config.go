@@ -182,6 +182,10 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 
 	utils.RegisterEthService(stack, &cfg.Eth)
 
+	// Add the GraphQL endpoint if requested.
+	if endpoint := cfg.Node.GraphQLEndpoint(); endpoint != "" {
+		utils.RegisterGraphQLService(stack, endpoint, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts, cfg.Node.HTTPTimeouts)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, cfg.Ethstats.URL)
@@ -195,6 +199,10 @@ func makeFullNodeForCBFT(ctx *cli.Context) (*node.Node, gethConfig) {
 
 	utils.RegisterEthService(stack, &cfg.Eth)
 
+	// Add the GraphQL endpoint if requested.
+	if endpoint := cfg.Node.GraphQLEndpoint(); endpoint != "" {
+		utils.RegisterGraphQLService(stack, endpoint, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts, cfg.Node.HTTPTimeouts)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, cfg.Ethstats.URL)
@@ -1,8 +1,9 @@
 package main
 
 import (
-	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/core/lru"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
 	"github.com/PlatONnetwork/PlatON-Go/life/exec"
 	"bytes"
 	"errors"
@@ -94,7 +95,7 @@ func benchmark(wasmFile string, outDir string, loop int) error {
 		return err
 	}
 
-	m, functionCode, err := exec.ParseModuleAndFunc(code, nil)
+	m, functionCode, err := exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
 
 	if err != nil {
 		return err
@@ -104,11 +105,11 @@ func benchmark(wasmFile string, outDir string, loop int) error {
 		return err
 	}
 
-	addr := common.HexToAddress("0x43355c787c50b647c425f594b441d4bd751951c1")
-	lru.WasmCache().Add(addr, &lru.WasmModule{m, functionCode})
+	codeHash := crypto.Keccak256Hash(code)
+	lru.WasmCache().Add(codeHash, &lru.WasmModule{Module: m, FunctionCode: functionCode, Code: code})
 
 	for i := 0; i < loop; i++ {
-		m, ok := lru.WasmCache().Get(addr)
+		m, ok := lru.WasmCache().Get(codeHash)
 		if !ok {
 			return errors.New("get wasm cache error")
 		}
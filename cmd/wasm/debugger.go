@@ -0,0 +1,583 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"debug/dwarf"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/cmd/utils"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler/opcodes"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	BreakpointFlag = cli.StringSliceFlag{
+		Name:  "break",
+		Usage: "breakpoint to set before running, as <function>[+offset]",
+	}
+
+	errDebuggerQuit = errors.New("execution aborted by the debugger")
+
+	debugCommand = cli.Command{
+		Action:    debugCmd,
+		Name:      "debug",
+		Usage:     "step through a wasm contract interactively",
+		ArgsUsage: "",
+		Flags: []cli.Flag{
+			BreakpointFlag,
+		},
+		Description: `
+The debug command runs the code like the run command, against the state of the
+'--prestate' genesis file, but stops on breakpoints and lets the instructions
+of the contract be stepped through. Breakpoints are given as <function>[+offset]
+where the function is an exported function, a name from the name section or a
+function index, and the offset the offset of an instruction in the code of the
+function in the wasm module, as listed by wasm-objdump -d.
+
+Type 'help' at the prompt for the list of commands.`,
+	}
+)
+
+const debugHelp = `Commands:
+  break <function>[+offset]  set a breakpoint (alias: b)
+  delete <n>                 delete breakpoint n
+  breakpoints                list the breakpoints
+  step                       execute the next instruction (alias: s)
+  next                       execute the next instruction, stepping over calls (alias: n)
+  continue                   run until the next breakpoint (alias: c)
+  locals                     print the locals of the current frame
+  regs                       print the registers of the current frame
+  memory <addr> [len]        dump linear memory (alias: x)
+  storage <key>              print the value getState returns for the key, given as 0x-prefixed hex or text
+  backtrace                  print the call stack (alias: bt)
+  functions                  list the functions of the module
+  source                     print the source location of the current function
+  help                       print this help
+  quit                       abort the execution (alias: q)
+`
+
+func debugCmd(ctx *cli.Context) error {
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(ctx.GlobalInt(VerbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	if ctx.GlobalString(CodeFileFlag.Name) == "-" || ctx.GlobalString(AbiFileFlag.Name) == "-" {
+		utils.Fatalf("The debugger reads its commands from stdin, code and abi must be given in files")
+	}
+	debugger := newWasmDebugger(os.Stdin, os.Stdout)
+	for _, spec := range ctx.StringSlice(BreakpointFlag.Name) {
+		if err := debugger.addBreakpoint(spec); err != nil {
+			utils.Fatalf("Invalid breakpoint %q: %v", spec, err)
+		}
+	}
+	// Stop on the first instruction unless told where to break
+	debugger.stepping = len(debugger.breakpoints) == 0
+
+	_, runtimeConfig := newRuntimeConfig(ctx, vm.Config{Tracer: debugger, Debug: true})
+	ret, leftOverGas, err := execute(ctx, runtimeConfig)
+
+	fmt.Fprintf(debugger.out, "returned 0x%x, gas used %d\n", ret, runtimeConfig.GasLimit-leftOverGas)
+	if err != nil {
+		fmt.Fprintf(debugger.out, " error: %v\n", err)
+	}
+	return nil
+}
+
+// breakpoint stops the execution at an instruction of a function. Functions
+// given by name are resolved against every module run, so the breakpoint also
+// applies to contracts invoked through platonCall.
+type breakpoint struct {
+	name     string
+	function int
+	offset   int
+}
+
+func (b *breakpoint) String() string {
+	if b.name != "" {
+		return fmt.Sprintf("%s+%d", b.name, b.offset)
+	}
+	return fmt.Sprintf("#%d+%d", b.function, b.offset)
+}
+
+// sourceLocation is the position of a function in the source code as recorded
+// in the DWARF sections of a module.
+type sourceLocation struct {
+	file string
+	line int
+}
+
+// moduleSymbols caches what the debugger knows about the symbols of a module.
+type moduleSymbols struct {
+	functions  map[string]int            // Function index by export and name section name
+	sources    map[string]sourceLocation // Source location by DWARF function name
+	dwarfErr   error                     // Reason why no source locations are available
+	sourceMaps []compiler.SourceMap      // Wasm code offsets of the compiled instructions by function index
+}
+
+// wasmDebugger is an interactive vm.WasmTracer. It is called before every
+// instruction of the life interpreter and, when the instruction is stopped at,
+// reads commands until execution is resumed.
+type wasmDebugger struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	breakpoints []*breakpoint
+	stepping    bool // Stop at the next instruction
+	stepFrame   int  // Call stack depth to stop at or above when stepping over calls, -1 if none
+	stepDepth   int  // Invocation depth the step over was issued at
+	depth       int  // Depth of contract invocations
+	quit        bool // Abort the execution
+	symbols     map[*compiler.Module]*moduleSymbols
+}
+
+func newWasmDebugger(in io.Reader, out io.Writer) *wasmDebugger {
+	return &wasmDebugger{
+		in:        bufio.NewScanner(in),
+		out:       out,
+		stepFrame: -1,
+		symbols:   make(map[*compiler.Module]*moduleSymbols),
+	}
+}
+
+// CaptureStart implements the vm.Tracer interface.
+func (d *wasmDebugger) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the vm.Tracer interface, EVM steps are not debugged.
+func (d *wasmDebugger) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureFault implements the vm.Tracer interface.
+func (d *wasmDebugger) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the vm.Tracer interface.
+func (d *wasmDebugger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	return nil
+}
+
+// CaptureEnter implements the vm.WasmTracer interface.
+func (d *wasmDebugger) CaptureEnter(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	d.depth++
+	if d.stepping && d.stepFrame < 0 {
+		fmt.Fprintf(d.out, "enter %s %x -> %x, gas %d\n", typ, from, to, gas)
+	}
+}
+
+// CaptureExit implements the vm.WasmTracer interface.
+func (d *wasmDebugger) CaptureExit(output []byte, gasUsed uint64, err error) {
+	d.depth--
+	if d.stepping && d.stepFrame < 0 {
+		fmt.Fprintf(d.out, "exit 0x%x, gas used %d", output, gasUsed)
+		if err != nil {
+			fmt.Fprintf(d.out, ", error: %v", err)
+		}
+		fmt.Fprintln(d.out)
+	}
+}
+
+// CaptureHostCall implements the exec.Tracer interface and shows the host
+// functions run while stepping.
+func (d *wasmDebugger) CaptureHostCall(vm *exec.VirtualMachine, module, field string, args []int64) {
+	if d.stepping && d.stepFrame < 0 {
+		fmt.Fprintf(d.out, "host call %s.%s%v\n", module, field, args)
+	}
+}
+
+// CaptureHostReturn implements the exec.Tracer interface.
+func (d *wasmDebugger) CaptureHostReturn(vm *exec.VirtualMachine, module, field string, ret int64) {
+	if d.stepping && d.stepFrame < 0 {
+		fmt.Fprintf(d.out, "host return %s.%s = %d\n", module, field, ret)
+	}
+}
+
+// CaptureStep implements the exec.Tracer interface and stops the execution if
+// stepping or on a breakpoint.
+func (d *wasmDebugger) CaptureStep(vm *exec.VirtualMachine, frame *exec.Frame, ip int, op opcodes.Opcode, gas uint64) {
+	// Panics abort the execution of the vm with the error
+	if d.quit {
+		panic(errDebuggerQuit)
+	}
+	stop := d.stepping
+	if stop && d.stepFrame >= 0 {
+		// Stepping over calls, wait for the frame to be returned to
+		stop = d.depth < d.stepDepth || (d.depth == d.stepDepth && vm.CurrentFrame <= d.stepFrame)
+	}
+	if !stop {
+		for _, b := range d.breakpoints {
+			if d.resolve(vm, b) == frame.FunctionID && d.breakpointIP(vm, frame.FunctionID, b.offset) == ip {
+				fmt.Fprintf(d.out, "breakpoint %s\n", b)
+				stop = true
+				break
+			}
+		}
+	}
+	if !stop {
+		return
+	}
+	d.stepping, d.stepFrame = false, -1
+	fmt.Fprintf(d.out, "[%d:%d] %s: %s, gas %d\n", d.depth, vm.CurrentFrame, d.location(vm, frame.FunctionID, ip), op, gas)
+	d.prompt(vm, frame)
+	if d.quit {
+		panic(errDebuggerQuit)
+	}
+}
+
+// prompt reads and runs commands until the execution is resumed.
+func (d *wasmDebugger) prompt(vm *exec.VirtualMachine, frame *exec.Frame) {
+	for {
+		fmt.Fprint(d.out, "(wasm) ")
+		if !d.in.Scan() {
+			// Nothing more to read, run to completion
+			d.breakpoints = nil
+			return
+		}
+		args := strings.Fields(d.in.Text())
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "step", "s":
+			d.stepping = true
+			return
+		case "next", "n":
+			d.stepping, d.stepFrame, d.stepDepth = true, vm.CurrentFrame, d.depth
+			return
+		case "continue", "c":
+			return
+		case "break", "b":
+			if len(args) != 2 {
+				fmt.Fprintln(d.out, "usage: break <function>[+offset]")
+			} else if err := d.addBreakpoint(args[1]); err != nil {
+				fmt.Fprintf(d.out, "invalid breakpoint: %v\n", err)
+			}
+		case "delete":
+			n := -1
+			if len(args) == 2 {
+				n, _ = strconv.Atoi(args[1])
+			}
+			if n < 0 || n >= len(d.breakpoints) {
+				fmt.Fprintln(d.out, "usage: delete <n>")
+				continue
+			}
+			d.breakpoints = append(d.breakpoints[:n], d.breakpoints[n+1:]...)
+		case "breakpoints":
+			for i, b := range d.breakpoints {
+				fmt.Fprintf(d.out, "%d: %s\n", i, b)
+			}
+		case "locals":
+			printValues(d.out, "local", frame.Locals)
+		case "regs":
+			printValues(d.out, "reg", frame.Regs)
+		case "memory", "x":
+			d.printMemory(vm, args[1:])
+		case "storage":
+			d.printStorage(vm, args[1:])
+		case "backtrace", "bt":
+			for i := vm.CurrentFrame; i >= 0; i-- {
+				// Callers are shown at their call, not where they return to
+				f, ip := vm.CallStack[i], vm.CallStack[i].IP
+				if i < vm.CurrentFrame {
+					ip--
+				}
+				fmt.Fprintf(d.out, "<%d> %s\n", i, d.location(vm, f.FunctionID, ip))
+			}
+		case "functions":
+			d.printFunctions(vm)
+		case "source":
+			d.printSource(vm, frame.FunctionID)
+		case "help", "h":
+			fmt.Fprint(d.out, debugHelp)
+		case "quit", "q":
+			d.quit = true
+			return
+		default:
+			fmt.Fprintf(d.out, "unknown command %q, type 'help' for the list of commands\n", args[0])
+		}
+	}
+}
+
+// addBreakpoint parses a breakpoint given as <function>[+offset].
+func (d *wasmDebugger) addBreakpoint(spec string) error {
+	b := &breakpoint{function: -1}
+	name := spec
+	if i := strings.LastIndex(spec, "+"); i >= 0 {
+		offset, err := strconv.Atoi(spec[i+1:])
+		if err != nil || offset < 0 {
+			return fmt.Errorf("invalid offset %q", spec[i+1:])
+		}
+		name, b.offset = spec[:i], offset
+	}
+	if name == "" {
+		return fmt.Errorf("missing function")
+	}
+	if index, err := strconv.Atoi(strings.TrimPrefix(name, "#")); err == nil {
+		b.function = index
+	} else {
+		b.name = name
+	}
+	d.breakpoints = append(d.breakpoints, b)
+	return nil
+}
+
+// resolve returns the index of the breakpoint function in the module run by
+// the vm, or -1 if the module doesn't contain it.
+func (d *wasmDebugger) resolve(vm *exec.VirtualMachine, b *breakpoint) int {
+	if b.name == "" {
+		return b.function
+	}
+	if index, ok := d.moduleSymbols(vm).functions[b.name]; ok {
+		return index
+	}
+	return -1
+}
+
+// moduleSymbols returns the symbols of the module run by the vm, collecting
+// them on first use.
+func (d *wasmDebugger) moduleSymbols(vm *exec.VirtualMachine) *moduleSymbols {
+	if syms, ok := d.symbols[vm.Module]; ok {
+		return syms
+	}
+	syms := &moduleSymbols{functions: make(map[string]int)}
+	for index, name := range vm.Module.FunctionNames {
+		syms.functions[name] = index
+	}
+	if vm.Module.Base.Export != nil {
+		for name := range vm.Module.Base.Export.Entries {
+			if index, ok := vm.GetFunctionExport(name); ok {
+				syms.functions[name] = index
+			}
+		}
+	}
+	syms.sources, syms.dwarfErr = readSourceLocations(vm.Module)
+
+	// Compiling the module again the way it was compiled for the vm yields
+	// the same code, along with where its instructions come from.
+	var gasPolicy compiler.GasPolicy
+	if compiler.GasMetered(vm.FunctionCode) {
+		gasPolicy = compiler.DefaultGasPolicy
+	}
+	if _, sourceMaps, err := vm.Module.CompileWithSourceMaps(gasPolicy); err == nil {
+		syms.sourceMaps = sourceMaps
+	}
+	d.symbols[vm.Module] = syms
+	return syms
+}
+
+// breakpointIP returns the position of the compiled instruction to stop at
+// for a breakpoint at the given wasm code offset of a function.
+func (d *wasmDebugger) breakpointIP(vm *exec.VirtualMachine, index int, offset int) int {
+	sourceMaps := d.moduleSymbols(vm).sourceMaps
+	if index < 0 || index >= len(sourceMaps) {
+		return -1
+	}
+	return sourceMaps[index].IP(offset)
+}
+
+// location returns the function and the wasm code offset in it of the
+// compiled instruction at ip.
+func (d *wasmDebugger) location(vm *exec.VirtualMachine, index int, ip int) string {
+	sourceMaps := d.moduleSymbols(vm).sourceMaps
+	if index < 0 || index >= len(sourceMaps) || sourceMaps[index] == nil {
+		return fmt.Sprintf("%s (ip %d)", d.functionName(vm, index), ip)
+	}
+	return fmt.Sprintf("%s+%d", d.functionName(vm, index), sourceMaps[index].Offset(ip))
+}
+
+// functionName returns the name of the function with the given index, if the
+// module names it, or its index otherwise.
+func (d *wasmDebugger) functionName(vm *exec.VirtualMachine, index int) string {
+	if name, ok := vm.Module.FunctionNames[index]; ok {
+		return name
+	}
+	for name, i := range d.moduleSymbols(vm).functions {
+		if i == index {
+			return name
+		}
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+func (d *wasmDebugger) printFunctions(vm *exec.VirtualMachine) {
+	syms := d.moduleSymbols(vm)
+	names := make([]string, 0, len(syms.functions))
+	for name := range syms.functions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return syms.functions[names[i]] < syms.functions[names[j]] })
+	for _, name := range names {
+		fmt.Fprintf(d.out, "#%d %s\n", syms.functions[name], name)
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(d.out, "the module has no exports and no name section")
+	}
+}
+
+func (d *wasmDebugger) printSource(vm *exec.VirtualMachine, index int) {
+	syms := d.moduleSymbols(vm)
+	if syms.dwarfErr != nil {
+		fmt.Fprintf(d.out, "no source information: %v\n", syms.dwarfErr)
+		return
+	}
+	name, ok := vm.Module.FunctionNames[index]
+	if !ok {
+		fmt.Fprintf(d.out, "function #%d is not in the name section\n", index)
+		return
+	}
+	loc, ok := syms.sources[name]
+	if !ok {
+		fmt.Fprintf(d.out, "no source information for %s\n", name)
+		return
+	}
+	fmt.Fprintf(d.out, "%s at %s:%d\n", name, loc.file, loc.line)
+}
+
+func (d *wasmDebugger) printMemory(vm *exec.VirtualMachine, args []string) {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintln(d.out, "usage: memory <addr> [len]")
+		return
+	}
+	addr, err := strconv.ParseUint(args[0], 0, 32)
+	if err != nil {
+		fmt.Fprintf(d.out, "invalid address %q\n", args[0])
+		return
+	}
+	length := uint64(64)
+	if len(args) == 2 {
+		if length, err = strconv.ParseUint(args[1], 0, 32); err != nil {
+			fmt.Fprintf(d.out, "invalid length %q\n", args[1])
+			return
+		}
+	}
+	if vm.Memory == nil || addr+length > uint64(len(vm.Memory.Memory)) {
+		fmt.Fprintln(d.out, "out of bounds memory access")
+		return
+	}
+	mem := vm.Memory.Memory[addr : addr+length]
+	for off := 0; off < len(mem); off += 16 {
+		end := off + 16
+		if end > len(mem) {
+			end = len(mem)
+		}
+		fmt.Fprintf(d.out, "%08x  % x\n", addr+uint64(off), mem[off:end])
+	}
+}
+
+func (d *wasmDebugger) printStorage(vm *exec.VirtualMachine, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(d.out, "usage: storage <key>")
+		return
+	}
+	key := []byte(args[0])
+	if strings.HasPrefix(args[0], "0x") {
+		var err error
+		if key, err = hex.DecodeString(args[0][2:]); err != nil {
+			fmt.Fprintf(d.out, "invalid key %q\n", args[0])
+			return
+		}
+	}
+	value := vm.Context.StateDB.GetState(key)
+	fmt.Fprintf(d.out, "0x%x %q\n", value, value)
+}
+
+func printValues(out io.Writer, kind string, values []int64) {
+	for i, v := range values {
+		fmt.Fprintf(out, "%s %d: %d (0x%x)\n", kind, i, v, uint64(v))
+	}
+}
+
+// readSourceLocations maps the functions described by the DWARF sections of
+// the module to the source line of their entry. DWARF addresses point into
+// the original code section and not into the compiled functions, so the
+// location is only available per function.
+func readSourceLocations(m *compiler.Module) (map[string]sourceLocation, error) {
+	sections := make(map[string][]byte)
+	for _, s := range m.Base.Customs {
+		sections[s.Name] = s.Data
+	}
+	if sections[".debug_info"] == nil {
+		return nil, fmt.Errorf("the module has no DWARF sections")
+	}
+	data, err := dwarf.New(sections[".debug_abbrev"], sections[".debug_aranges"], sections[".debug_frame"],
+		sections[".debug_info"], sections[".debug_line"], sections[".debug_pubnames"], sections[".debug_ranges"], sections[".debug_str"])
+	if err != nil {
+		return nil, err
+	}
+	var (
+		sources = make(map[string]sourceLocation)
+		lines   = make(map[uint64]dwarf.LineEntry)
+		reader  = data.Reader()
+	)
+	for {
+		entry, err := reader.Next()
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			break
+		}
+		switch entry.Tag {
+		case dwarf.TagCompileUnit:
+			lines = make(map[uint64]dwarf.LineEntry)
+			lr, err := data.LineReader(entry)
+			if err != nil || lr == nil {
+				continue
+			}
+			var line dwarf.LineEntry
+			for lr.Next(&line) == nil {
+				if _, ok := lines[line.Address]; !ok && line.File != nil {
+					lines[line.Address] = line
+				}
+			}
+		case dwarf.TagSubprogram:
+			lowpc, ok := entry.Val(dwarf.AttrLowpc).(uint64)
+			if !ok {
+				continue
+			}
+			line, ok := lines[lowpc]
+			if !ok {
+				continue
+			}
+			loc := sourceLocation{file: line.File.Name, line: line.Line}
+			for _, attr := range []dwarf.Attr{dwarf.AttrName, dwarf.AttrLinkageName} {
+				if name, ok := entry.Val(attr).(string); ok {
+					sources[name] = loc
+				}
+			}
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+)
+
+// debugCallModule runs call.wat of the life test corpus, whose first function
+// calls the second with 10, which adds 42 to it, under the debugger.
+func debugCallModule(t *testing.T, gasPolicy compiler.GasPolicy, commands string, breakpoints ...string) (string, int64, error) {
+	code, err := ioutil.ReadFile(filepath.Join("..", "..", "life", "tests", "call.wasm"))
+	if err != nil {
+		t.Fatalf("failed to read module: %v", err)
+	}
+	out := new(bytes.Buffer)
+	debugger := newWasmDebugger(strings.NewReader(commands), out)
+	for _, spec := range breakpoints {
+		if err := debugger.addBreakpoint(spec); err != nil {
+			t.Fatalf("invalid breakpoint %q: %v", spec, err)
+		}
+	}
+	debugger.stepping = len(breakpoints) == 0
+
+	vm, err := exec.NewVirtualMachine(code, &exec.VMContext{GasLimit: 1000000, Tracer: debugger}, nil, gasPolicy)
+	if err != nil {
+		t.Fatalf("failed to create vm: %v", err)
+	}
+	ret, err := vm.Run(0)
+	return out.String(), ret, err
+}
+
+func TestDebuggerBreakpointOffset(t *testing.T) {
+	for _, gasPolicy := range []compiler.GasPolicy{nil, compiler.DefaultGasPolicy} {
+		// Offset 4 of the second function is its i32.add
+		out, ret, err := debugCallModule(t, gasPolicy, "locals\ncontinue\n", "#1+4")
+		if err != nil || ret != 52 {
+			t.Fatalf("run failed: have %d (%v), want 52", ret, err)
+		}
+		for _, want := range []string{"breakpoint #1+4\n", "#1+4: I32Add", "local 0: 10 (0xa)\n"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("gas policy %v: output is missing %q:\n%s", gasPolicy, want, out)
+			}
+		}
+	}
+}
+
+func TestDebuggerBacktrace(t *testing.T) {
+	out, _, err := debugCallModule(t, compiler.DefaultGasPolicy, "backtrace\ncontinue\n", "#1+2")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	// The caller is shown at the call, the instruction at offset 2
+	if !strings.Contains(out, "<1> #1+2\n<0> #0+2\n") {
+		t.Errorf("backtrace mismatch:\n%s", out)
+	}
+}
+
+func TestDebuggerQuit(t *testing.T) {
+	out, _, err := debugCallModule(t, compiler.DefaultGasPolicy, "quit\nlocals\n")
+	if err == nil || !strings.Contains(err.Error(), errDebuggerQuit.Error()) {
+		t.Fatalf("error mismatch: have %v, want %v", err, errDebuggerQuit)
+	}
+	if strings.Contains(out, "local") {
+		t.Errorf("commands read after quit:\n%s", out)
+	}
+}
+
+func TestDebuggerAddBreakpoint(t *testing.T) {
+	d := newWasmDebugger(strings.NewReader(""), ioutil.Discard)
+	for _, spec := range []string{"", "+1", "main+x", "main+-1"} {
+		if err := d.addBreakpoint(spec); err == nil {
+			t.Errorf("breakpoint %q accepted", spec)
+		}
+	}
+	for spec, want := range map[string]string{"main": "main+0", "main+12": "main+12", "#3+1": "#3+1", "2": "#2+0"} {
+		d.breakpoints = nil
+		if err := d.addBreakpoint(spec); err != nil {
+			t.Errorf("breakpoint %q rejected: %v", spec, err)
+		} else if have := d.breakpoints[0].String(); have != want {
+			t.Errorf("breakpoint %q mismatch: have %s, want %s", spec, have, want)
+		}
+	}
+}
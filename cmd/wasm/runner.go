@@ -66,13 +66,8 @@ func runCmd(ctx *cli.Context) error {
 	}
 
 	var (
-		tracer        vm.Tracer
-		debugLogger   *vm.StructLogger
-		statedb       *state.StateDB
-		chainConfig   *params.ChainConfig
-		sender        = common.BytesToAddress([]byte("sender"))
-		receiver      = common.BytesToAddress([]byte("receiver"))
-		genesisConfig *core.Genesis
+		tracer      vm.Tracer
+		debugLogger *vm.StructLogger
 	)
 	if ctx.GlobalBool(MachineFlag.Name) {
 		tracer = NewJSONLogger(logconfig, os.Stdout)
@@ -82,6 +77,50 @@ func runCmd(ctx *cli.Context) error {
 	} else {
 		debugLogger = vm.NewStructLogger(logconfig)
 	}
+	statedb, runtimeConfig := newRuntimeConfig(ctx, vm.Config{
+		Tracer: tracer,
+		Debug:  ctx.GlobalBool(DebugFlag.Name) || ctx.GlobalBool(MachineFlag.Name),
+	})
+
+	tstart := time.Now()
+	ret, leftOverGas, err := execute(ctx, runtimeConfig)
+	execTime := time.Since(tstart)
+
+	statedb.IntermediateRoot(true)
+	fmt.Println(string(statedb.Dump()))
+
+	if ctx.GlobalBool(StatDumpFlag.Name) {
+		var mem goruntime.MemStats
+		goruntime.ReadMemStats(&mem)
+		fmt.Fprintf(os.Stderr, `evm execution time: %v
+heap objects:       %d
+allocations:        %d
+total allocations:  %d
+GC calls:           %d
+Gas used:           %d
+
+`, execTime, mem.HeapObjects, mem.Alloc, mem.TotalAlloc, mem.NumGC, runtimeConfig.GasLimit-leftOverGas)
+	}
+
+	if tracer == nil {
+		fmt.Printf("0x%x\n", ret)
+		if err != nil {
+			fmt.Printf(" error: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// newRuntimeConfig builds the state described by the '--prestate' genesis file
+// and the runtime configuration to execute against it.
+func newRuntimeConfig(ctx *cli.Context, vmConfig vm.Config) (*state.StateDB, *runtime.Config) {
+	var (
+		statedb       *state.StateDB
+		chainConfig   *params.ChainConfig
+		sender        = common.BytesToAddress([]byte("sender"))
+		genesisConfig *core.Genesis
+	)
 	if ctx.GlobalString(GenesisFlag.Name) != "" {
 		gen := readGenesis(ctx.GlobalString(GenesisFlag.Name))
 		genesisConfig = gen
@@ -97,17 +136,40 @@ func runCmd(ctx *cli.Context) error {
 		sender = common.HexToAddress(ctx.GlobalString(SenderFlag.Name))
 	}
 	statedb.CreateAccount(sender)
-	if ctx.GlobalString(ReceiverFlag.Name) != "" {
-		receiver = common.HexToAddress(ctx.GlobalString(ReceiverFlag.Name))
+
+	initialGas := ctx.GlobalUint64(GasFlag.Name)
+	if genesisConfig.GasLimit != 0 {
+		initialGas = genesisConfig.GasLimit
+	}
+	runtimeConfig := &runtime.Config{
+		Origin:      sender,
+		State:       statedb,
+		GasLimit:    initialGas,
+		GasPrice:    utils.GlobalBig(ctx, GasPriceFlag.Name),
+		Value:       utils.GlobalBig(ctx, ValueFlag.Name),
+		Time:        new(big.Int).SetUint64(genesisConfig.Timestamp),
+		Coinbase:    genesisConfig.Coinbase,
+		BlockNumber: new(big.Int).SetUint64(genesisConfig.Number),
+		EVMConfig:   vmConfig,
 	}
+	if chainConfig != nil {
+		runtimeConfig.ChainConfig = chainConfig
+	}
+	return statedb, runtimeConfig
+}
 
+// execute creates the contract given by the code flags or calls the receiver
+// with the input flag, as selected by '--create'.
+func execute(ctx *cli.Context, runtimeConfig *runtime.Config) ([]byte, uint64, error) {
 	var (
-		code []byte
-		abi  []byte
-		ret  []byte
-		err  error
+		statedb  = runtimeConfig.State
+		receiver = common.BytesToAddress([]byte("receiver"))
+		code     []byte
+		abi      []byte
 	)
-
+	if ctx.GlobalString(ReceiverFlag.Name) != "" {
+		receiver = common.HexToAddress(ctx.GlobalString(ReceiverFlag.Name))
+	}
 	// The '--code' or '--codefile' flag overrides code in state
 	if ctx.GlobalString(CodeFileFlag.Name) != "" {
 		var hexcode []byte
@@ -150,33 +212,8 @@ func runCmd(ctx *cli.Context) error {
 		abi = []byte(ctx.GlobalString(AbiFlag.Name))
 	}
 
-	initialGas := ctx.GlobalUint64(GasFlag.Name)
-	if genesisConfig.GasLimit != 0 {
-		initialGas = genesisConfig.GasLimit
-	}
-	runtimeConfig := runtime.Config{
-		Origin:      sender,
-		State:       statedb,
-		GasLimit:    initialGas,
-		GasPrice:    utils.GlobalBig(ctx, GasPriceFlag.Name),
-		Value:       utils.GlobalBig(ctx, ValueFlag.Name),
-		Time:        new(big.Int).SetUint64(genesisConfig.Timestamp),
-		Coinbase:    genesisConfig.Coinbase,
-		BlockNumber: new(big.Int).SetUint64(genesisConfig.Number),
-		EVMConfig: vm.Config{
-			Tracer: tracer,
-			Debug:  ctx.GlobalBool(DebugFlag.Name) || ctx.GlobalBool(MachineFlag.Name),
-		},
-	}
-
-	if chainConfig != nil {
-		runtimeConfig.ChainConfig = chainConfig
-	}
-
 	txType := ctx.GlobalInt64(TxTypeFlag.Name)
 
-	tstart := time.Now()
-	var leftOverGas uint64
 	if ctx.GlobalBool(CreateFlag.Name) {
 		// Contract creation logic，Input is an external input, possibly a parameter。Need to be encoded in wasm to complete
 		rlpData := make([][]byte, 0)
@@ -187,42 +224,16 @@ func runCmd(ctx *cli.Context) error {
 		if err != nil {
 			utils.Fatalf("rlp parse fail: %v", err)
 		}
-		ret, _, leftOverGas, err = runtime.Create(buffer.Bytes(), &runtimeConfig)
-	} else {
-		if len(code) > 0 {
-			statedb.SetCode(receiver, code)
-		}
-		if len(abi) > 0 {
-			statedb.SetAbi(receiver, abi)
-		}
-		// input : rlp.encoded format.
-		input := common.Hex2Bytes(ctx.GlobalString(InputFlag.Name))
-		ret, leftOverGas, err = runtime.Call(receiver, input, &runtimeConfig)
+		ret, _, leftOverGas, err := runtime.Create(buffer.Bytes(), runtimeConfig)
+		return ret, leftOverGas, err
 	}
-	execTime := time.Since(tstart)
-
-	statedb.IntermediateRoot(true)
-	fmt.Println(string(statedb.Dump()))
-
-	if ctx.GlobalBool(StatDumpFlag.Name) {
-		var mem goruntime.MemStats
-		goruntime.ReadMemStats(&mem)
-		fmt.Fprintf(os.Stderr, `evm execution time: %v
-heap objects:       %d
-allocations:        %d
-total allocations:  %d
-GC calls:           %d
-Gas used:           %d
-
-`, execTime, mem.HeapObjects, mem.Alloc, mem.TotalAlloc, mem.NumGC, initialGas-leftOverGas)
+	if len(code) > 0 {
+		statedb.SetCode(receiver, code)
 	}
-
-	if tracer == nil {
-		fmt.Printf("0x%x\n", ret)
-		if err != nil {
-			fmt.Printf(" error: %v\n", err)
-		}
+	if len(abi) > 0 {
+		statedb.SetAbi(receiver, abi)
 	}
-
-	return nil
+	// input : rlp.encoded format.
+	input := common.Hex2Bytes(ctx.GlobalString(InputFlag.Name))
+	return runtime.Call(receiver, input, runtimeConfig)
 }
@@ -187,7 +187,7 @@ func runMain(wasm *exec.VirtualMachine) error {
 func runModule(m *compiler.Module, functionCode []compiler.InterpreterCode, db *leveldb.DB, logStream *bytes.Buffer) error {
 	context := newContext(logStream)
 
-	wasm, err := exec.NewVirtualMachineWithModule(m, functionCode, context, newUnitTestResolver(db, logStream), nil)
+	wasm, err := exec.NewVirtualMachineWithModule(m, functionCode, context, newUnitTestResolver(db, logStream), compiler.DefaultGasPolicy)
 
 	if err != nil {
 		return err
@@ -199,7 +199,7 @@ func runTest(code []byte, db *leveldb.DB, logStream *bytes.Buffer) error {
 	context := newContext(logStream)
 
 
-	wasm, err := exec.NewVirtualMachine(code, context, newUnitTestResolver(db, logStream), nil)
+	wasm, err := exec.NewVirtualMachine(code, context, newUnitTestResolver(db, logStream), compiler.DefaultGasPolicy)
 
 	if err != nil {
 		return err
@@ -241,6 +241,7 @@ func newUnitTestResolver(ldb *leveldb.DB, logStream *bytes.Buffer) *UnitTestReso
 			"platonCall":               &exec.FunctionImport{Execute: resolver.envPlatonCall, GasCost: constGasFunc},
 			"platonDelegateCall":       &exec.FunctionImport{Execute: resolver.envPlatonCall, GasCost: constGasFunc},
 			"emitEvent":                &exec.FunctionImport{Execute: resolver.envEmitEvent, GasCost: constGasFunc},
+			"emitEventIndexed":         &exec.FunctionImport{Execute: resolver.envEmitEventIndexed, GasCost: constGasFunc},
 			"bigintAdd":                &exec.FunctionImport{Execute: resolver.envBigintAdd, GasCost: constGasFunc},
 			"envMalloc":                &exec.FunctionImport{Execute: resolver.envMalloc, GasCost: constGasFunc},
 			"envFree":                  &exec.FunctionImport{Execute: resolver.envFree, GasCost: constGasFunc},
@@ -381,6 +382,20 @@ func (r *UnitTestResolver) envEmitEvent(vm *exec.VirtualMachine) int64 {
 	return 0
 }
 
+func (r *UnitTestResolver) envEmitEventIndexed(vm *exec.VirtualMachine) int64 {
+	topics := int(int32(vm.GetCurrentFrame().Locals[0]))
+	topicCount := int(int32(vm.GetCurrentFrame().Locals[1]))
+	data := int(int32(vm.GetCurrentFrame().Locals[2]))
+	dataLen := int(int32(vm.GetCurrentFrame().Locals[3]))
+	for i := 0; i < topicCount; i++ {
+		vm.Context.Log.Debug(hex.EncodeToString(vm.Memory.Memory[topics+i*32 : topics+(i+1)*32]))
+		vm.Context.Log.Debug(" ")
+	}
+	vm.Context.Log.Debug(hex.EncodeToString(vm.Memory.Memory[data : data+dataLen]))
+
+	return 0
+}
+
 func (r *UnitTestResolver) envBigintAdd(vm *exec.VirtualMachine) int64 {
 	frame := vm.GetCurrentFrame()
 	src := int(int32(frame.Locals[0]))
@@ -514,6 +529,15 @@ func (s *stateDB) Call(addr, params []byte) ([]byte, error) {
 func (s *stateDB) DelegateCall(addr, params []byte) ([]byte, error) {
 	return nil, nil
 }
+func (s *stateDB) Create(code []byte, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	return common.Address{}, gas, errors.New("create not supported")
+}
+func (s *stateDB) Clone(addr common.Address, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	return common.Address{}, gas, errors.New("clone not supported")
+}
+func (s *stateDB) Migrate(code, args []byte, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	return common.Address{}, gas, errors.New("migrate not supported")
+}
 
 
 //func (s *stateDB) CreateAccount(common.Address){}
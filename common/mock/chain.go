@@ -215,6 +215,18 @@ func (s *MockStateDB) ForEachStorage(common.Address, func(common.Hash, common.Ha
 	return
 }
 
+func (s *MockStateDB) StorageEntries(adr common.Address) uint64 {
+	return uint64(len(s.State[adr]))
+}
+
+func (s *MockStateDB) CopyStorage(from, to common.Address) {
+	storage := make(map[string][]byte, len(s.State[from]))
+	for key, value := range s.State[from] {
+		storage[key] = value
+	}
+	s.State[to] = storage
+}
+
 func (s *MockStateDB) TxHash() common.Hash {
 	return s.thash
 }
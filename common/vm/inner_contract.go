@@ -26,4 +26,5 @@ var (
 	SlashingContractAddr       = common.HexToAddress("0x1000000000000000000000000000000000000004") // The PlatON Precompiled contract addr for slashing
 	GovContractAddr            = common.HexToAddress("0x1000000000000000000000000000000000000005") // The PlatON Precompiled contract addr for governance
 	ValidatorInnerContractAddr = common.HexToAddress("0x2000000000000000000000000000000000000000") // The PlatON Precompiled contract addr for cbft inner
+	MigrationRecordAddr        = common.HexToAddress("0x1000000000000000000000000000000000000006") // The PlatON system account holding the forwarding records of migrated contracts
 )
@@ -66,6 +66,8 @@ type CacheConfig struct {
 	DBGCTimeout  time.Duration
 	DBGCMpt      bool
 	DBGCBlock    uint64
+
+	HistoryBlocks uint64 // Number of recent blocks whose bodies and receipts are retained (0 = keep all)
 }
 
 // mining related configuration
@@ -143,7 +145,9 @@ type BlockChain struct {
 	badBlocks      *lru.Cache              // Bad block cache
 	shouldPreserve func(*types.Block) bool // Function used to determine whether should preserve the given block.
 
-	cleaner *Cleaner
+	cleaner        *Cleaner
+	historyPruner  *HistoryPruner // Removes the history of old blocks, nil if all history is kept
+	oldestRetained uint64         // Oldest block whose history is retained, must be accessed atomically
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -217,6 +221,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	log.Debug("DB config", "DBDisabledGC", bc.cacheConfig.DBDisabledGC, "DBGCInterval", bc.cacheConfig.DBGCInterval, "DBGCTimeout", bc.cacheConfig.DBGCTimeout, "DBGCMpt", bc.cacheConfig.DBGCMpt)
 	bc.cleaner = NewCleaner(bc, bc.cacheConfig.DBGCInterval, bc.cacheConfig.DBGCTimeout, bc.cacheConfig.DBGCMpt)
 
+	bc.oldestRetained = rawdb.ReadOldestRetainedBlock(bc.db)
+	if bc.cacheConfig.HistoryBlocks > 0 {
+		bc.historyPruner = NewHistoryPruner(bc, bc.cacheConfig.HistoryBlocks)
+	}
+
 	// Take ownership of this particular state
 	go bc.update()
 	return bc, nil
@@ -680,6 +689,9 @@ func (bc *BlockChain) Stop() {
 	atomic.StoreInt32(&bc.procInterrupt, 1)
 
 	bc.cleaner.Stop()
+	if bc.historyPruner != nil {
+		bc.historyPruner.Stop()
+	}
 
 	bc.wg.Wait()
 
@@ -768,7 +780,7 @@ func (bc *BlockChain) Rollback(chain []common.Hash) {
 
 // SetReceiptsData computes all the non-consensus fields of the receipts
 func SetReceiptsData(config *params.ChainConfig, block *types.Block, receipts types.Receipts) error {
-	signer := types.NewEIP155Signer(config.ChainID)
+	signer := types.MakeSigner(config, block.Number())
 	transactions, logIndex := block.Transactions(), uint(0)
 	if len(transactions) != len(receipts) {
 		return errors.New("transaction and receipt count mismatch")
@@ -779,7 +791,7 @@ func SetReceiptsData(config *params.ChainConfig, block *types.Block, receipts ty
 		receipts[j].TxHash = transactions[j].Hash()
 
 		// The contract address can be derived from the transaction itself
-		if transactions[j].To() == nil {
+		if transactions[j].To() == nil && transactions[j].Type() != types.BatchTxType {
 			// Deriving the signer is expensive, only do if it's actually needed
 			from, _ := types.Sender(signer, transactions[j])
 			receipts[j].ContractAddress = crypto.CreateAddress(from, transactions[j].Nonce())
@@ -1090,6 +1102,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	if !bc.cacheConfig.DBDisabledGC.IsSet() && bc.cleaner.NeedCleanup() {
 		bc.cleaner.Cleanup()
 	}
+	if bc.historyPruner != nil {
+		bc.historyPruner.Prune()
+	}
 	return status, nil
 }
 
@@ -1152,7 +1167,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 	defer close(abort)
 
 	// Start a parallel signature recovery (signer will fluke on fork transition, minimal perf loss)
-	senderCacher.recoverFromBlocks(types.NewEIP155Signer(bc.chainConfig.ChainID), chain)
+	senderCacher.recoverFromBlocks(types.MakeSigner(bc.chainConfig, chain[0].Number()), chain)
 
 	// Pause engine
 	bc.engine.Pause()
@@ -1608,6 +1623,13 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// OldestRetainedBlock returns the number of the oldest block whose body and
+// receipts are retained. The history of the canonical blocks below it, apart
+// from the genesis block, was pruned.
+func (bc *BlockChain) OldestRetainedBlock() uint64 {
+	return atomic.LoadUint64(&bc.oldestRetained)
+}
+
 // EnableDBGC enable database garbage collection.
 func (bc *BlockChain) EnableDBGC() {
 	bc.cacheConfig.DBDisabledGC.Set(false)
@@ -140,8 +140,8 @@ func (c *Cleaner) loop() {
 func (c *Cleaner) cleanup() {
 	defer c.cleaning.Set(false)
 
-	db, ok := c.blockchain.db.(*ethdb.LDBDatabase)
-	if !ok {
+	db := rawdb.LevelDB(c.blockchain.db)
+	if db == nil {
 		log.Warn("The database not a leveldb, discard cleanup operation")
 		return
 	}
@@ -166,6 +166,10 @@ func (c *Cleaner) cleanup() {
 
 	if currentBlock.NumberU64()-c.lastNumber >= 2*c.interval {
 		number := lastNumber + 1
+		if oldest := c.blockchain.OldestRetainedBlock(); number < oldest {
+			// The history below was already removed by history expiry
+			number = oldest
+		}
 		for ; number <= currentBlock.NumberU64()-c.interval; number++ {
 			block := c.blockchain.GetBlockByNumber(number)
 			if block == nil {
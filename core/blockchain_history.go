@@ -0,0 +1,192 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+var (
+	// minHistoryBlocks is the smallest history window allowed. It keeps the
+	// bodies the chain indexers read until their sections are processed.
+	minHistoryBlocks = 2*params.AddressIndexBlocks + params.AddressIndexConfirms
+
+	// historyPruneStep is the number of blocks the chain has to grow past the
+	// history window before they are pruned.
+	historyPruneStep uint64 = 128
+
+	// historyPruneBatch is the number of blocks pruned in a single database batch.
+	historyPruneBatch uint64 = 1024
+
+	// historyPruneTimeout is the maximum time a single pruning run takes.
+	historyPruneTimeout = time.Minute
+)
+
+// HistoryPruner removes the bodies and receipts of the canonical blocks that
+// fell out of the history window. Headers are kept, so the chain can still be
+// verified and followed by number, and so are the transaction lookups, so the
+// transactions of pruned blocks are reported as pruned rather than unknown. It
+// runs independently of the state garbage collection done by the Cleaner. The
+// history of blocks already moved into the ancient store can't be deleted from
+// its append-only tables, it is left there and hidden from readers below the
+// oldest retained block.
+type HistoryPruner struct {
+	stopped common.AtomicBool
+	pruning common.AtomicBool
+	blocks  uint64 // Number of recent blocks whose history is retained
+
+	wg      sync.WaitGroup
+	exit    chan struct{}
+	pruneCh chan struct{}
+
+	blockchain *BlockChain
+}
+
+// NewHistoryPruner creates a history pruner retaining the history of the given
+// number of recent blocks.
+func NewHistoryPruner(blockchain *BlockChain, blocks uint64) *HistoryPruner {
+	if blocks < minHistoryBlocks {
+		log.Warn("Sanitizing history window", "provided", blocks, "updated", minHistoryBlocks)
+		blocks = minHistoryBlocks
+	}
+	p := &HistoryPruner{
+		blocks:     blocks,
+		exit:       make(chan struct{}),
+		pruneCh:    make(chan struct{}, 1),
+		blockchain: blockchain,
+	}
+	p.wg.Add(1)
+	go p.loop()
+	return p
+}
+
+// Stop terminates the pruner, waiting for a running pruning run to save its
+// progress.
+func (p *HistoryPruner) Stop() {
+	if p.stopped.IsSet() {
+		return
+	}
+	close(p.exit)
+
+	p.stopped.Set(true)
+	p.wg.Wait()
+}
+
+// Prune schedules a pruning run if the chain grew far enough past the history
+// window and none is running yet.
+func (p *HistoryPruner) Prune() {
+	if p.pruning.IsSet() {
+		return
+	}
+	head := p.blockchain.CurrentBlock().NumberU64()
+	if head < p.blocks+historyPruneStep || head-p.blocks-historyPruneStep < p.blockchain.OldestRetainedBlock() {
+		return
+	}
+	p.pruning.Set(true)
+	select {
+	case p.pruneCh <- struct{}{}:
+	default:
+	}
+}
+
+func (p *HistoryPruner) loop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.pruneCh:
+			p.prune()
+		case <-p.exit:
+			return
+		}
+	}
+}
+
+// prune removes the history of the canonical blocks below the history window,
+// moving the oldest retained block marker along with each database batch.
+func (p *HistoryPruner) prune() {
+	defer p.pruning.Set(false)
+
+	var (
+		db     = p.blockchain.db
+		head   = p.blockchain.CurrentBlock().NumberU64()
+		oldest = p.blockchain.OldestRetainedBlock()
+		number = oldest
+		frozen uint64
+		start  = time.Now()
+	)
+	if ancient, ok := db.(rawdb.AncientReader); ok {
+		frozen, _ = ancient.Ancients()
+	}
+	if head < p.blocks || head-p.blocks <= oldest {
+		return
+	}
+	limit := head - p.blocks
+	if number == 0 {
+		// The genesis block is never pruned
+		number = 1
+	}
+	log.Info("Start pruning chain history", "oldest", oldest, "limit", limit, "head", head)
+	defer func() {
+		log.Info("Finish pruning chain history", "oldest", p.blockchain.OldestRetainedBlock(), "elapsed", time.Since(start))
+	}()
+
+	batch := db.NewBatch()
+	for ; number < limit; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			log.Error("Missing canonical hash, aborting history pruning", "number", number)
+			break
+		}
+		// Frozen blocks have no history left in the key-value store
+		if number >= frozen {
+			rawdb.DeleteBody(batch, hash, number)
+			rawdb.DeleteReceipts(batch, hash, number)
+		}
+
+		if (number+1)%historyPruneBatch == 0 {
+			if err := p.commit(batch, number+1); err != nil {
+				return
+			}
+			if time.Since(start) >= historyPruneTimeout || p.stopped.IsSet() {
+				return
+			}
+		}
+	}
+	p.commit(batch, number)
+}
+
+// commit writes out the pruned history along with the new oldest retained block
+// in one batch, so readers never miss history above the marker.
+func (p *HistoryPruner) commit(batch ethdb.Batch, oldest uint64) error {
+	rawdb.WriteOldestRetainedBlock(batch, oldest)
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write pruned history", "err", err)
+		return err
+	}
+	batch.Reset()
+	atomic.StoreUint64(&p.blockchain.oldestRetained, oldest)
+	return nil
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryPruner(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "platon")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	db, err := ethdb.NewLDBDatabase(tmpDir, 100, 1024)
+	assert.Nil(t, err)
+
+	blockchain, err := newBlockChainForTesting(db)
+	assert.Nil(t, err)
+	assert.NotNil(t, blockchain)
+
+	pruner := NewHistoryPruner(blockchain, 100)
+	assert.Equal(t, minHistoryBlocks, pruner.blocks)
+	defer pruner.Stop()
+
+	// Nothing falls out of the window of a short chain
+	pruner.prune()
+	assert.Equal(t, uint64(0), blockchain.OldestRetainedBlock())
+
+	pruner.blocks = 100
+	pruner.prune()
+	assert.Equal(t, uint64(100), blockchain.OldestRetainedBlock())
+	assert.Equal(t, uint64(100), rawdb.ReadOldestRetainedBlock(db))
+
+	for _, number := range []uint64{1, 50, 99} {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		assert.NotNil(t, rawdb.ReadHeader(db, hash, number))
+		assert.Nil(t, rawdb.ReadBody(db, hash, number))
+		assert.Nil(t, rawdb.ReadReceipts(db, hash, number))
+	}
+	for _, number := range []uint64{0, 100, 200} {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		assert.NotNil(t, rawdb.ReadBody(db, hash, number))
+	}
+
+	// Pruning resumes from the oldest retained block
+	pruner.blocks = 50
+	pruner.prune()
+	assert.Equal(t, uint64(150), blockchain.OldestRetainedBlock())
+}
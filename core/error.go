@@ -32,4 +32,20 @@ var (
 	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than the
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
+
+	// ErrContractCreation is returned if a transaction creates a contract,
+	// which is not allowed.
+	ErrContractCreation = errors.New("contract creation is not allowed")
+
+	// ErrInvalidBatch is returned if a batch transaction has no calls or more
+	// than params.MaxBatchCalls.
+	ErrInvalidBatch = errors.New("invalid number of calls in batch")
+
+	// ErrBatchPPOSCall is returned if a call of a batch transaction targets a PPOS
+	// contract, whose writes a failing batch couldn't revert.
+	ErrBatchPPOSCall = errors.New("ppos contract call in batch")
+
+	// ErrHistoryPruned is returned if the body or receipts of a block were
+	// requested after history expiry removed them.
+	ErrHistoryPruned = errors.New("pruned history unavailable")
 )
@@ -162,6 +162,12 @@ func SetupGenesisBlock(db ethdb.Database, snapshotPath string, genesis *Genesis)
 		log.Error("Failed to SetupGenesisBlock, the config of genesis is nil")
 		return params.AllEthashProtocolChanges, common.Hash{}, errGenesisNoConfig
 	}
+	if genesis != nil {
+		if err := genesis.Config.CheckConfigForkOrder(); err != nil {
+			log.Error("Failed to SetupGenesisBlock, the fork order of genesis is invalid", "err", err)
+			return genesis.Config, common.Hash{}, err
+		}
+	}
 
 	// Just commit the new block if there is no stored genesis block.
 	stored := rawdb.ReadCanonicalHash(db, 0)
@@ -433,6 +433,18 @@ func (hc *HeaderChain) SetHead(head uint64, delFn DeleteCallback) {
 	}
 	batch.Write()
 
+	// Drop any frozen segment beyond the new head as well
+	if ancients, ok := hc.chainDb.(interface {
+		rawdb.AncientReader
+		rawdb.AncientWriter
+	}); ok {
+		if frozen, err := ancients.Ancients(); err == nil && frozen > head+1 {
+			if err := ancients.TruncateAncients(head + 1); err != nil {
+				log.Error("Failed to truncate ancient store", "head", head, "err", err)
+			}
+		}
+	}
+
 	// Clear out any stale content from the caches
 	hc.headerCache.Purge()
 	hc.numberCache.Purge()
@@ -3,37 +3,76 @@ package lru
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
+	"math"
 	"path/filepath"
 	"sync"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
 	"github.com/hashicorp/golang-lru/simplelru"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+// WasmCacheVersion must be increased whenever the output of the compiler
+// changes, so that modules cached on disk by older versions are discarded.
+const WasmCacheVersion = 1
+
 var (
 	DefaultWasmCacheSize = 1024
-	wasmCache, _         = NewWasmCache(DefaultWasmCacheSize)
-	DefaultWasmCacheDir  = "wasmcache"
+	// DefaultWasmCacheDiskSize is the maximum number of bytes of compiled
+	// modules kept on disk.
+	DefaultWasmCacheDiskSize int64 = 512 * 1024 * 1024
+	DefaultWasmCacheDir            = "wasmcache"
+	wasmCache, _                   = NewWasmCache(DefaultWasmCacheSize)
+
+	wasmCacheVersionKey   = []byte("version")
+	wasmCacheModulePrefix = []byte("m")
+
+	wasmCacheMemoryHitMeter = metrics.NewRegisteredMeter("wasm/cache/memory/hit", nil)
+	wasmCacheDiskHitMeter   = metrics.NewRegisteredMeter("wasm/cache/disk/hit", nil)
+	wasmCacheMissMeter      = metrics.NewRegisteredMeter("wasm/cache/miss", nil)
+	wasmCacheCorruptMeter   = metrics.NewRegisteredMeter("wasm/cache/disk/corrupt", nil)
+	wasmCacheDiskSizeGauge  = metrics.NewRegisteredGauge("wasm/cache/disk/size", nil)
+	wasmCacheDiskEvictMeter = metrics.NewRegisteredMeter("wasm/cache/disk/evict", nil)
 )
 
+// WasmLDBCache caches compiled WASM modules by code hash in memory, backed
+// by a size bounded leveldb database so that they survive restarts.
 type WasmLDBCache struct {
 	lru  *simplelru.LRU
 	db   *leveldb.DB
 	lock sync.RWMutex
+
+	// disk tracks the modules stored in db with their encoded size, in
+	// order of use, for evicting them once maxDiskSize is exceeded.
+	disk        *simplelru.LRU
+	diskSize    int64
+	maxDiskSize int64
 }
 
 type WasmModule struct {
 	Module       *compiler.Module
 	FunctionCode []compiler.InterpreterCode
+	Code         []byte
+}
+
+// wasmModuleData is the on-disk form of a WasmModule. The parsed module
+// cannot be encoded, so it is loaded again from the raw code.
+type wasmModuleData struct {
+	Code         []byte
+	FunctionCode []compiler.InterpreterCode
 }
 
 func WasmCache() *WasmLDBCache {
 	return wasmCache
 }
 
+// SetWasmDB opens the on-disk module cache inside dataDir.
 func SetWasmDB(dataDir string) error {
 	path := filepath.Join(dataDir, DefaultWasmCacheDir)
 
@@ -41,45 +80,36 @@ func SetWasmDB(dataDir string) error {
 	if err != nil {
 		return err
 	}
-	wasmCache.SetDB(db)
-	return nil
+	return wasmCache.SetDB(db)
 }
 
-func NewWasmCache(size int) (*WasmLDBCache, error) {
-	w := &WasmLDBCache{}
+// wasmCacheVersion identifies the compiler and the gas policy the cached
+// modules were compiled with.
+func wasmCacheVersion() []byte {
+	return []byte(fmt.Sprintf("%d/%T%+v", WasmCacheVersion, compiler.DefaultGasPolicy, compiler.DefaultGasPolicy))
+}
 
-	onEvicted := func(k interface{}, v interface{}) {
-		var addr common.Address
-		var module *WasmModule
-		var ok bool
+func wasmCacheModuleKey(hash common.Hash) []byte {
+	return append(append([]byte{}, wasmCacheModulePrefix...), hash.Bytes()...)
+}
 
-		if addr, ok = k.(common.Address); !ok {
-			return
-		}
+func NewWasmCache(size int) (*WasmLDBCache, error) {
+	lru, err := simplelru.NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	w := &WasmLDBCache{lru: lru, maxDiskSize: DefaultWasmCacheDiskSize}
 
-		if module, ok = v.(*WasmModule); !ok {
-			return
-		}
+	w.disk, err = simplelru.NewLRU(math.MaxInt32, func(k interface{}, v interface{}) {
+		w.diskSize -= v.(int64)
 		if w.db != nil {
-			if ok, err := w.db.Has(addr.Bytes(), nil); err != nil || !ok {
-				buffer := new(bytes.Buffer)
-				enc := gob.NewEncoder(buffer)
-				if err := enc.Encode(module); err != nil {
-					log.Error("encode module err:", err)
-					return
-				}
-				w.db.Put(addr.Bytes(), buffer.Bytes(), nil)
-			}
+			w.db.Delete(wasmCacheModuleKey(k.(common.Hash)), nil)
 		}
-	}
-
-	lru, err := simplelru.NewLRU(size, simplelru.EvictCallback(onEvicted))
-
+		wasmCacheDiskSizeGauge.Update(w.diskSize)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	w.lru = lru
 	return w, nil
 }
 
@@ -88,12 +118,152 @@ func NewWasmLDBCache(size int, db *leveldb.DB) (*WasmLDBCache, error) {
 	if err != nil {
 		return nil, err
 	}
-	w.db = db
+	if err := w.SetDB(db); err != nil {
+		return nil, err
+	}
 	return w, nil
 }
 
-func (w *WasmLDBCache) SetDB(db *leveldb.DB) {
+// SetDB replaces the database backing the cache, closing the previous one.
+// Modules compiled by a different compiler version or gas policy are
+// dropped from the new database.
+func (w *WasmLDBCache) SetDB(db *leveldb.DB) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.db != nil {
+		w.db.Close()
+	}
+	w.db = nil
+	w.disk.Purge()
+	w.diskSize = 0
+
+	version := wasmCacheVersion()
+	if stored, err := db.Get(wasmCacheVersionKey, nil); err != nil || !bytes.Equal(stored, version) {
+		if err == nil {
+			log.Info("Discarding outdated wasm module cache", "version", string(stored))
+		}
+		batch := new(leveldb.Batch)
+		it := db.NewIterator(util.BytesPrefix(wasmCacheModulePrefix), nil)
+		for it.Next() {
+			batch.Delete(common.CopyBytes(it.Key()))
+		}
+		it.Release()
+		batch.Put(wasmCacheVersionKey, version)
+		if err := db.Write(batch, nil); err != nil {
+			return err
+		}
+	}
+
+	it := db.NewIterator(util.BytesPrefix(wasmCacheModulePrefix), nil)
+	for it.Next() {
+		size := int64(len(it.Value()))
+		w.disk.Add(common.BytesToHash(it.Key()[len(wasmCacheModulePrefix):]), size)
+		w.diskSize += size
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
 	w.db = db
+	w.evictDisk()
+	return nil
+}
+
+// SetMaxDiskSize changes the number of bytes of modules kept on disk.
+func (w *WasmLDBCache) SetMaxDiskSize(size int64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.maxDiskSize = size
+	w.evictDisk()
+}
+
+// Close closes the database backing the cache.
+func (w *WasmLDBCache) Close() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.db != nil {
+		w.db.Close()
+		w.db = nil
+	}
+	w.disk.Purge()
+	w.diskSize = 0
+}
+
+// evictDisk removes the least recently used modules from disk until the
+// disk size limit is respected.
+func (w *WasmLDBCache) evictDisk() {
+	for w.diskSize > w.maxDiskSize && w.disk.Len() > 0 {
+		w.disk.RemoveOldest()
+		wasmCacheDiskEvictMeter.Mark(1)
+	}
+	wasmCacheDiskSizeGauge.Update(w.diskSize)
+}
+
+// store writes the module to disk, prefixed by the hash of its encoding. A
+// module already stored under the key is replaced, as modules are added again
+// once compiled with a different gas metering.
+func (w *WasmLDBCache) store(key common.Hash, module *WasmModule) {
+	if w.db == nil || module.Code == nil {
+		return
+	}
+	buffer := new(bytes.Buffer)
+	if err := gob.NewEncoder(buffer).Encode(&wasmModuleData{module.Code, module.FunctionCode}); err != nil {
+		log.Error("Failed to encode wasm module", "hash", key, "err", err)
+		return
+	}
+	data := append(crypto.Keccak256(buffer.Bytes()), buffer.Bytes()...)
+	if err := w.db.Put(wasmCacheModuleKey(key), data, nil); err != nil {
+		log.Error("Failed to store wasm module", "hash", key, "err", err)
+		return
+	}
+	if size, ok := w.disk.Peek(key); ok {
+		w.diskSize -= size.(int64)
+	}
+	w.disk.Add(key, int64(len(data)))
+	w.diskSize += int64(len(data))
+	w.evictDisk()
+}
+
+// load reads the module from disk, dropping it if the stored checksum does
+// not match its encoding.
+func (w *WasmLDBCache) load(key common.Hash) (*WasmModule, bool) {
+	if w.db == nil {
+		return nil, false
+	}
+	data, err := w.db.Get(wasmCacheModuleKey(key), nil)
+	if err != nil {
+		return nil, false
+	}
+	var stored wasmModuleData
+	if len(data) < common.HashLength || !bytes.Equal(crypto.Keccak256(data[common.HashLength:]), data[:common.HashLength]) {
+		log.Warn("Dropping corrupted wasm module", "hash", key)
+		wasmCacheCorruptMeter.Mark(1)
+		w.drop(key)
+		return nil, false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data[common.HashLength:])).Decode(&stored); err != nil {
+		log.Error("Failed to decode wasm module", "hash", key, "err", err)
+		wasmCacheCorruptMeter.Mark(1)
+		w.drop(key)
+		return nil, false
+	}
+	m, err := compiler.LoadModule(stored.Code)
+	if err != nil {
+		log.Error("Failed to load wasm module", "hash", key, "err", err)
+		wasmCacheCorruptMeter.Mark(1)
+		w.drop(key)
+		return nil, false
+	}
+	return &WasmModule{Module: m, FunctionCode: stored.FunctionCode, Code: stored.Code}, true
+}
+
+func (w *WasmLDBCache) drop(key common.Hash) {
+	if !w.disk.Remove(key) {
+		w.db.Delete(wasmCacheModuleKey(key), nil)
+	}
 }
 
 // Purge is used to completely clear the cache
@@ -103,69 +273,51 @@ func (w *WasmLDBCache) Purge() {
 	w.lock.Unlock()
 }
 
-// Add adds a value to the cache.  Returns true if an eviction occurred.
-func (w *WasmLDBCache) Add(key common.Address, value *WasmModule) bool {
+// Add adds a value to the cache and stores it on disk.  Returns true if an
+// eviction occurred.
+func (w *WasmLDBCache) Add(key common.Hash, value *WasmModule) bool {
 	w.lock.Lock()
 	defer w.lock.Unlock()
+	w.store(key, value)
 	return w.lru.Add(key, value)
 }
 
 // Get looks up a key's value from the cache.
-func (w *WasmLDBCache) Get(key common.Address) (*WasmModule, bool) {
+func (w *WasmLDBCache) Get(key common.Hash) (*WasmModule, bool) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	value, ok := w.lru.Get(key)
 	if !ok {
-		if w.db != nil {
-			if value, err := w.db.Get(key.Bytes(), nil); err == nil {
-				module := WasmModule{}
-				buffer := bytes.NewReader(value)
-				dec := gob.NewDecoder(buffer)
-				if err := dec.Decode(&module); err != nil {
-					log.Error("decode module err:", err)
-					return nil, false
-				}
-				w.lru.Add(key, &module)
-				return &module, true
-			}
+		if module, ok := w.load(key); ok {
+			wasmCacheDiskHitMeter.Mark(1)
+			w.disk.Get(key)
+			w.lru.Add(key, module)
+			return module, true
 		}
+		wasmCacheMissMeter.Mark(1)
 		return nil, false
 	}
+	wasmCacheMemoryHitMeter.Mark(1)
+	w.disk.Get(key)
 	return value.(*WasmModule), ok
 }
 
 // Check if a key is in the cache, without updating the recent-ness
 // or deleting it for being stale.
-func (w *WasmLDBCache) Contains(key common.Address) bool {
+func (w *WasmLDBCache) Contains(key common.Hash) bool {
 	w.lock.RLock()
 	defer w.lock.RUnlock()
-	if !w.lru.Contains(key) {
-		ok := false
-		if w.db != nil {
-			ok, _ = w.db.Has(key.Bytes(), nil)
-		}
-		return ok
-	}
-	return true
+	return w.lru.Contains(key) || w.disk.Contains(key)
 }
 
 // Returns the key value (or undefined if not found) without updating
 // the "recently used"-ness of the key.
-func (w *WasmLDBCache) Peek(key common.Address) (*WasmModule, bool) {
+func (w *WasmLDBCache) Peek(key common.Hash) (*WasmModule, bool) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	value, ok := w.lru.Peek(key)
 	if !ok {
-		if w.db != nil {
-			if value, err := w.db.Get(key.Bytes(), nil); err == nil {
-				var module WasmModule
-				buffer := bytes.NewReader(value)
-				dec := gob.NewDecoder(buffer)
-				dec.Decode(&module)
-				return &module, true
-			}
-		}
-		return nil, false
+		return w.load(key)
 	}
 	return value.(*WasmModule), ok
 }
@@ -173,24 +325,25 @@ func (w *WasmLDBCache) Peek(key common.Address) (*WasmModule, bool) {
 // ContainsOrAdd checks if a key is in the cache  without updating the
 // recent-ness or deleting it for being stale,  and if not, adds the value.
 // Returns whether found and whether an eviction occurred.
-func (w *WasmLDBCache) ContainsOrAdd(key common.Address, value *WasmModule) (ok, evict bool) {
+func (w *WasmLDBCache) ContainsOrAdd(key common.Hash, value *WasmModule) (ok, evict bool) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
 	if w.lru.Contains(key) {
 		return true, false
 	} else {
+		w.store(key, value)
 		evict := w.lru.Add(key, value)
 		return false, evict
 	}
 }
 
 // Remove removes the provided key from the cache.
-func (w *WasmLDBCache) Remove(key common.Address) {
+func (w *WasmLDBCache) Remove(key common.Hash) {
 	w.lock.Lock()
 	w.lru.Remove(key)
 	if w.db != nil {
-		w.db.Delete(key.Bytes(), nil)
+		w.drop(key)
 	}
 	w.lock.Unlock()
 }
@@ -215,3 +368,10 @@ func (w *WasmLDBCache) Len() int {
 	defer w.lock.RUnlock()
 	return w.lru.Len()
 }
+
+// DiskSize returns the number of bytes of modules stored on disk.
+func (w *WasmLDBCache) DiskSize() int64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.diskSize
+}
@@ -0,0 +1,173 @@
+package lru
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func newTestModule(n int) *WasmModule {
+	code := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	m, err := compiler.LoadModule(code)
+	if err != nil {
+		panic(err)
+	}
+	return &WasmModule{
+		Module: m,
+		FunctionCode: []compiler.InterpreterCode{
+			{NumRegs: n, Bytes: make([]byte, 100*n), GasMetered: true},
+		},
+		Code: code,
+	}
+}
+
+func openTestCache(t *testing.T, dir string) *WasmLDBCache {
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	cache, err := NewWasmLDBCache(DefaultWasmCacheSize, db)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	return cache
+}
+
+func TestWasmCachePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasmcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hash := common.HexToHash("0x01")
+	cache := openTestCache(t, dir)
+	cache.Add(hash, newTestModule(3))
+	cache.Close()
+
+	cache = openTestCache(t, dir)
+	defer cache.Close()
+	if cache.DiskSize() == 0 {
+		t.Fatalf("disk size not restored")
+	}
+	module, ok := cache.Get(hash)
+	if !ok {
+		t.Fatalf("module not found after reopening")
+	}
+	if module.FunctionCode[0].NumRegs != 3 || !module.FunctionCode[0].GasMetered {
+		t.Fatalf("module mismatch: %+v", module.FunctionCode[0])
+	}
+}
+
+func TestWasmCacheReplace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasmcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hash := common.HexToHash("0x01")
+	unmetered := newTestModule(3)
+	unmetered.FunctionCode[0].GasMetered = false
+
+	cache := openTestCache(t, dir)
+	cache.Add(hash, unmetered)
+	cache.Add(hash, newTestModule(4))
+	size := cache.DiskSize()
+	cache.Close()
+
+	cache = openTestCache(t, dir)
+	defer cache.Close()
+	if cache.DiskSize() != size {
+		t.Fatalf("disk size mismatch: have %d, want %d", cache.DiskSize(), size)
+	}
+	module, ok := cache.Get(hash)
+	if !ok {
+		t.Fatalf("module not found after reopening")
+	}
+	if module.FunctionCode[0].NumRegs != 4 || !module.FunctionCode[0].GasMetered {
+		t.Fatalf("replaced module returned: %+v", module.FunctionCode[0])
+	}
+}
+
+func TestWasmCacheCorruption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasmcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hash := common.HexToHash("0x01")
+	cache := openTestCache(t, dir)
+	defer cache.Close()
+	cache.Add(hash, newTestModule(3))
+
+	data, _ := cache.db.Get(wasmCacheModuleKey(hash), nil)
+	data[len(data)-1] ^= 0xff
+	cache.db.Put(wasmCacheModuleKey(hash), data, nil)
+	cache.Purge()
+
+	if _, ok := cache.Get(hash); ok {
+		t.Fatalf("corrupted module returned")
+	}
+	if has, _ := cache.db.Has(wasmCacheModuleKey(hash), nil); has {
+		t.Fatalf("corrupted module not dropped")
+	}
+	if cache.DiskSize() != 0 {
+		t.Fatalf("disk size mismatch: have %d, want 0", cache.DiskSize())
+	}
+}
+
+func TestWasmCacheVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasmcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hash := common.HexToHash("0x01")
+	cache := openTestCache(t, dir)
+	cache.Add(hash, newTestModule(3))
+	cache.db.Put(wasmCacheVersionKey, []byte("0/outdated"), nil)
+	cache.Close()
+
+	cache = openTestCache(t, dir)
+	defer cache.Close()
+	if _, ok := cache.Get(hash); ok {
+		t.Fatalf("module of outdated version returned")
+	}
+}
+
+func TestWasmCacheDiskEviction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasmcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := openTestCache(t, dir)
+	defer cache.Close()
+
+	hashes := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")}
+	cache.Add(hashes[0], newTestModule(10))
+	size := cache.DiskSize()
+	cache.SetMaxDiskSize(2 * size)
+
+	cache.Add(hashes[1], newTestModule(10))
+	// Touch the first module so that the second one is evicted next.
+	cache.Get(hashes[0])
+	cache.Add(hashes[2], newTestModule(10))
+
+	if cache.DiskSize() > 2*size {
+		t.Fatalf("disk size %d exceeds limit %d", cache.DiskSize(), 2*size)
+	}
+	for i, want := range []bool{true, false, true} {
+		if has, _ := cache.db.Has(wasmCacheModuleKey(hashes[i]), nil); has != want {
+			t.Errorf("module %d on disk: have %v, want %v", i, has, want)
+		}
+	}
+}
@@ -27,10 +27,42 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
+// readAncient retrieves an item of the given kind from the ancient store of the
+// database, nil if the database has none or the block was not frozen.
+func readAncient(db DatabaseReader, kind string, number uint64) []byte {
+	ancient, ok := db.(AncientReader)
+	if !ok {
+		return nil
+	}
+	data, _ := ancient.Ancient(kind, number)
+	return data
+}
+
+// readAncientByHash retrieves an item of the given kind from the ancient store if
+// the block with the given hash is the one frozen at its number.
+func readAncientByHash(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if frozen := readAncient(db, freezerHashTable, number); len(frozen) == 0 || common.BytesToHash(frozen) != hash {
+		return nil
+	}
+	return readAncient(db, kind, number)
+}
+
+// readAncientHistory retrieves the body or receipts of a frozen block, nil if
+// its history was pruned. Blocks frozen before the history pruner reached them
+// keep their history in the append-only tables, so it is hidden here.
+func readAncientHistory(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if number > 0 && number < ReadOldestRetainedBlock(db) {
+		return nil
+	}
+	return readAncientByHash(db, kind, hash, number)
+}
+
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
-
+	if len(data) == 0 {
+		data = readAncient(db, freezerHashTable, number)
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
@@ -128,16 +160,38 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 	}
 }
 
+// ReadOldestRetainedBlock retrieves the number of the oldest block whose body
+// and receipts are retained. The history of the canonical blocks below it,
+// apart from the genesis block, was pruned.
+func ReadOldestRetainedBlock(db DatabaseReader) uint64 {
+	data, _ := db.Get(oldestRetainedBlockKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteOldestRetainedBlock stores the number of the oldest block whose body and
+// receipts are retained.
+func WriteOldestRetainedBlock(db DatabaseWriter, number uint64) {
+	if err := db.Put(oldestRetainedBlockKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store oldest retained block", "err", err)
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+		return len(readAncientByHash(db, freezerHeaderTable, hash, number)) > 0
 	}
 	return true
 }
@@ -193,6 +247,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 // ReadBodyRLP retrieves the block body (transactions) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientHistory(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -206,7 +263,7 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+		return len(readAncientHistory(db, freezerBodiesTable, hash, number)) > 0
 	}
 	return true
 }
@@ -245,6 +302,9 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
 	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientHistory(db, freezerReceiptTable, hash, number)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -281,6 +341,9 @@ func WriteReceipts(db DatabaseWriter, hash common.Hash, number uint64, receipts
 // ReadBlockConfirmSigns retrieves all the block confirmSigns belonging to a block.
 func ReadBlockConfirmSigns(db DatabaseReader, hash common.Hash, number uint64) []*common.BlockConfirmSign {
 	data, _ := db.Get(blockConfirmSignsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerConfirmSignsTable, hash, number)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -239,6 +239,20 @@ func TestHeadStorage(t *testing.T) {
 	}
 }
 
+// Tests that the oldest retained block marker can be stored and retrieved.
+func TestOldestRetainedBlockStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	// Check that all history is retained in a pristine database
+	if number := ReadOldestRetainedBlock(db); number != 0 {
+		t.Fatalf("Non zero oldest retained block returned: %d", number)
+	}
+	WriteOldestRetainedBlock(db, 4096)
+	if number := ReadOldestRetainedBlock(db); number != 4096 {
+		t.Fatalf("Oldest retained block mismatch: have %d, want %d", number, 4096)
+	}
+}
+
 // Tests that receipts associated with a single block can be stored and retrieved.
 func TestBlockReceiptStorage(t *testing.T) {
 	db := ethdb.NewMemDatabase()
@@ -105,3 +105,30 @@ func WriteBloomBits(db DatabaseWriter, bit uint, section uint64, head common.Has
 		log.Crit("Failed to store bloom bits", "err", err)
 	}
 }
+
+// ReadAddressTxEntries retrieves the positions of the transactions the address
+// took part in within the given section, in chain order.
+func ReadAddressTxEntries(db DatabaseReader, address common.Address, section uint64, head common.Hash) []AddressTxEntry {
+	data, _ := db.Get(addressTxKey(address, section, head))
+	if len(data) == 0 {
+		return nil
+	}
+	var entries []AddressTxEntry
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Error("Invalid address transaction entries RLP", "address", address, "section", section, "err", err)
+		return nil
+	}
+	return entries
+}
+
+// WriteAddressTxEntries stores the positions of the transactions the address
+// took part in within the given section.
+func WriteAddressTxEntries(db DatabaseWriter, address common.Address, section uint64, head common.Hash, entries []AddressTxEntry) {
+	data, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		log.Crit("Failed to encode address transaction entries", "err", err)
+	}
+	if err := db.Put(addressTxKey(address, section, head), data); err != nil {
+		log.Crit("Failed to store address transaction entries", "err", err)
+	}
+}
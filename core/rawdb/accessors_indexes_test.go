@@ -18,6 +18,7 @@ package rawdb
 
 import (
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
@@ -66,3 +67,31 @@ func TestLookupStorage(t *testing.T) {
 		}
 	}
 }
+
+// Tests that address transaction entries are stored per section head, so the
+// entries of a reorged section head are not returned for the new one.
+func TestAddressTxStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	var (
+		addr  = common.BytesToAddress([]byte{0x11})
+		head  = common.BytesToHash([]byte{0x01})
+		reorg = common.BytesToHash([]byte{0x02})
+	)
+	if entries := ReadAddressTxEntries(db, addr, 1, head); entries != nil {
+		t.Fatalf("non existent entries returned: %v", entries)
+	}
+	want := []AddressTxEntry{{BlockNumber: 4096, Index: 0}, {BlockNumber: 4100, Index: 3}}
+	WriteAddressTxEntries(db, addr, 1, head, want)
+
+	have := ReadAddressTxEntries(db, addr, 1, head)
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("entries mismatch: have %v, want %v", have, want)
+	}
+	if entries := ReadAddressTxEntries(db, addr, 1, reorg); entries != nil {
+		t.Fatalf("entries of another section head returned: %v", entries)
+	}
+	if entries := ReadAddressTxEntries(db, common.BytesToAddress([]byte{0x22}), 1, head); entries != nil {
+		t.Fatalf("entries of another address returned: %v", entries)
+	}
+}
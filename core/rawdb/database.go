@@ -1,8 +1,12 @@
 package rawdb
 
 import (
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb/memorydb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
 )
 
 // NewMemoryDatabase creates an ephemeral in-memory key-value database without a
@@ -27,3 +31,76 @@ type nofreezedb struct {
 func (n *nofreezedb) Close() {
 	n.KeyValueStore.Close()
 }
+
+// freezerdb is a database wrapper that enables freezer data retrievals.
+type freezerdb struct {
+	*ethdb.LDBDatabase
+	*freezer
+}
+
+// Close implements ethdb.Database, closing both the fast key-value store and the
+// slow ancient tables.
+func (frdb *freezerdb) Close() {
+	if err := frdb.freezer.Close(); err != nil {
+		log.Error("Failed to close ancient database", "err", err)
+	}
+	frdb.LDBDatabase.Close()
+}
+
+// NewDatabaseWithFreezer creates a high level database on top of the given
+// leveldb with a freezer moving immutable chain segments into cold storage in
+// the given directory.
+func NewDatabaseWithFreezer(db *ethdb.LDBDatabase, freezer string) (ethdb.Database, error) {
+	frdb, err := newFreezer(freezer)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFreezer(db, frdb); err != nil {
+		frdb.Close()
+		return nil, err
+	}
+	frdb.wg.Add(1)
+	go frdb.freeze(db)
+
+	return &freezerdb{
+		LDBDatabase: db,
+		freezer:     frdb,
+	}, nil
+}
+
+// validateFreezer checks that the ancient store belongs to the chain in the
+// key-value store and that the key-value store continues where the ancient store
+// ends. Either fails if one of them was removed or swapped without the other.
+func validateFreezer(db ethdb.Database, frdb *freezer) error {
+	head := ReadHeadHeaderHash(db)
+	if head == (common.Hash{}) {
+		return nil
+	}
+	frozen, _ := frdb.Ancients()
+	if frozen > 0 {
+		if hash, _ := frdb.Ancient(freezerHashTable, 0); common.BytesToHash(hash) != ReadCanonicalHash(db, 0) {
+			return fmt.Errorf("ancient chain segment belongs to a different chain, genesis %x", hash)
+		}
+	}
+	// The genesis block is always kept in the key-value store
+	next := frozen
+	if next == 0 {
+		next = 1
+	}
+	if number := ReadHeaderNumber(db, head); number != nil && *number >= next && ReadCanonicalHash(db, next) == (common.Hash{}) {
+		return fmt.Errorf("key-value store lacks block %d following the %d ancient blocks", next, frozen)
+	}
+	return nil
+}
+
+// LevelDB returns the leveldb backing a chain database, nil if it is not backed
+// by one.
+func LevelDB(db ethdb.Database) *ethdb.LDBDatabase {
+	switch db := db.(type) {
+	case *ethdb.LDBDatabase:
+		return db
+	case *freezerdb:
+		return db.LDBDatabase
+	}
+	return nil
+}
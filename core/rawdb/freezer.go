@@ -0,0 +1,345 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+)
+
+// The tables of the ancient store, one item per canonical block.
+const (
+	// freezerHashTable indicates the name of the freezer canonical hash table.
+	freezerHashTable = "hashes"
+
+	// freezerHeaderTable indicates the name of the freezer header table.
+	freezerHeaderTable = "headers"
+
+	// freezerBodiesTable indicates the name of the freezer block body table.
+	freezerBodiesTable = "bodies"
+
+	// freezerReceiptTable indicates the name of the freezer receipts table.
+	freezerReceiptTable = "receipts"
+
+	// freezerConfirmSignsTable indicates the name of the freezer block confirm
+	// signatures table.
+	freezerConfirmSignsTable = "confirmsigns"
+)
+
+// freezerNoSnappy configures whether compression is disabled for the ancient
+// tables. Hashes do not compress.
+var freezerNoSnappy = map[string]bool{
+	freezerHashTable:         true,
+	freezerHeaderTable:       false,
+	freezerBodiesTable:       false,
+	freezerReceiptTable:      false,
+	freezerConfirmSignsTable: false,
+}
+
+// errUnknownTable is returned if the user attempts to read from a table that is
+// not tracked by the freezer.
+var errUnknownTable = errors.New("unknown table")
+
+const (
+	// freezerRecheckInterval is the frequency to check the key-value database for
+	// chain progression that might permit new blocks to be frozen into immutable
+	// storage.
+	freezerRecheckInterval = time.Minute
+
+	// freezerRecentBlocks is the number of recent committed blocks kept in the
+	// key-value database, where they are cheaper to read and write.
+	freezerRecentBlocks = 1024
+
+	// freezerBatchLimit is the maximum number of blocks to freeze in one batch
+	// before doing an fsync and deleting it from the key-value store.
+	freezerBatchLimit = 30000
+)
+
+// freezer is an append-only database to store immutable chain data into flat
+// files. Blocks committed by cbft are irreversible, so the chain segment below
+// the head block never changes and does not need the key-value store's
+// compaction.
+type freezer struct {
+	frozen uint64 // Number of blocks already frozen, must be accessed atomically
+
+	tables map[string]*freezerTable // Data tables for storing everything
+	lock   sync.Mutex               // Serializes appends against truncations
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// newFreezer creates a chain freezer that moves ancient chain data into
+// append-only flat file containers.
+func newFreezer(datadir string) (*freezer, error) {
+	freezer := &freezer{
+		tables: make(map[string]*freezerTable),
+		quit:   make(chan struct{}),
+	}
+	for name, disableSnappy := range freezerNoSnappy {
+		table, err := newTable(datadir, name, disableSnappy)
+		if err != nil {
+			for _, table := range freezer.tables {
+				table.Close()
+			}
+			return nil, err
+		}
+		freezer.tables[name] = table
+	}
+	if err := freezer.repair(); err != nil {
+		freezer.Close()
+		return nil, err
+	}
+	log.Info("Opened ancient database", "database", datadir, "frozen", freezer.frozen)
+	return freezer, nil
+}
+
+// repair truncates all data tables to the same length, dropping the blocks an
+// unclean shutdown left partially frozen.
+func (f *freezer) repair() error {
+	min := uint64(1<<64 - 1)
+	for _, table := range f.tables {
+		if items := table.Items(); items < min {
+			min = items
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(min); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, min)
+	return nil
+}
+
+// Close terminates the chain freezer, unmapping all the data files.
+func (f *freezer) Close() error {
+	f.closeOnce.Do(func() { close(f.quit) })
+	f.wg.Wait()
+
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return errs[0]
+	}
+	return nil
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists
+// in the freezer.
+func (f *freezer) HasAncient(kind string, number uint64) (bool, error) {
+	if _, ok := f.tables[kind]; !ok {
+		return false, errUnknownTable
+	}
+	return number < atomic.LoadUint64(&f.frozen), nil
+}
+
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
+func (f *freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.Retrieve(number)
+	}
+	return nil, errUnknownTable
+}
+
+// Ancients returns the length of the frozen items.
+func (f *freezer) Ancients() (uint64, error) {
+	return atomic.LoadUint64(&f.frozen), nil
+}
+
+// AppendAncient injects all binary blobs belong to block at the end of the
+// append-only immutable table files. A block whose history was pruned is
+// frozen with an empty body and receipts.
+func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, confirmSigns []byte) (err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if frozen := atomic.LoadUint64(&f.frozen); frozen != number {
+		return errOutOrderInsertion
+	}
+	// Roll back the tables already written to if any append fails
+	defer func() {
+		if err != nil {
+			if rerr := f.repair(); rerr != nil {
+				log.Crit("Failed to repair freezer", "err", rerr)
+			}
+			log.Error("Append ancient failed", "number", number, "err", err)
+		}
+	}()
+	blobs := map[string][]byte{
+		freezerHashTable:         hash,
+		freezerHeaderTable:       header,
+		freezerBodiesTable:       body,
+		freezerReceiptTable:      receipts,
+		freezerConfirmSignsTable: confirmSigns,
+	}
+	for name, blob := range blobs {
+		if err := f.tables[name].Append(number, blob); err != nil {
+			return err
+		}
+	}
+	atomic.AddUint64(&f.frozen, 1)
+	return nil
+}
+
+// TruncateAncients discards any recent data above the provided threshold number.
+func (f *freezer) TruncateAncients(items uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if atomic.LoadUint64(&f.frozen) <= items {
+		return nil
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, items)
+	return nil
+}
+
+// Sync flushes all data tables to disk.
+func (f *freezer) Sync() error {
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freeze is a background thread that periodically checks the blockchain for any
+// import progress and moves ancient data from the fast database into the freezer.
+//
+// This functionality is deliberately broken off from block importing to avoid
+// incurring additional data shuffling delays on block propagation.
+func (f *freezer) freeze(db ethdb.Database) {
+	defer f.wg.Done()
+
+	for {
+		if !f.freezeBatch(db) {
+			select {
+			case <-time.After(freezerRecheckInterval):
+			case <-f.quit:
+				return
+			}
+		}
+		select {
+		case <-f.quit:
+			return
+		default:
+		}
+	}
+}
+
+// freezeBatch moves the next batch of committed blocks past the recent window
+// into the freezer and deletes them from the key-value store. It reports whether
+// more blocks are ready to be frozen.
+func (f *freezer) freezeBatch(db ethdb.Database) bool {
+	// Retrieve the freezing threshold, the blocks up to the head are committed
+	hash := ReadHeadBlockHash(db)
+	if hash == (common.Hash{}) {
+		return false
+	}
+	number := ReadHeaderNumber(db, hash)
+	frozen := atomic.LoadUint64(&f.frozen)
+	switch {
+	case number == nil:
+		log.Error("Current full block number unavailable", "hash", hash)
+		return false
+
+	case *number < freezerRecentBlocks:
+		return false
+
+	case *number-freezerRecentBlocks < frozen:
+		return false
+	}
+	limit := *number - freezerRecentBlocks
+	if limit-frozen >= freezerBatchLimit {
+		limit = frozen + freezerBatchLimit - 1
+	}
+	// Move the blocks into the freezer
+	var (
+		start    = time.Now()
+		first    = frozen
+		oldest   = ReadOldestRetainedBlock(db)
+		ancients = make([]common.Hash, 0, limit-frozen+1)
+	)
+	for number := first; number <= limit; number++ {
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			log.Error("Canonical hash missing, can't freeze", "number", number)
+			break
+		}
+		header := ReadHeaderRLP(db, hash, number)
+		if len(header) == 0 {
+			log.Error("Block header missing, can't freeze", "number", number, "hash", hash)
+			break
+		}
+		// Bodies and receipts of the blocks whose history was pruned are frozen
+		// empty, even if the pruner did not get to delete them yet
+		var body, receipts []byte
+		if number == 0 || number >= oldest {
+			body = ReadBodyRLP(db, hash, number)
+			receipts, _ = db.Get(blockReceiptsKey(number, hash))
+		}
+		confirmSigns, _ := db.Get(blockConfirmSignsKey(number, hash))
+		if err := f.AppendAncient(number, hash.Bytes(), header, body, receipts, confirmSigns); err != nil {
+			break
+		}
+		ancients = append(ancients, hash)
+	}
+	if len(ancients) == 0 {
+		return false
+	}
+	// Batch of blocks have been frozen, flush them before wiping from the key-value store
+	if err := f.Sync(); err != nil {
+		log.Crit("Failed to flush frozen tables", "err", err)
+	}
+	// Wipe out all data from the active database, the genesis block is kept. The
+	// hash to number mappings stay, they are not part of the ancient store.
+	batch := db.NewBatch()
+	for i, hash := range ancients {
+		number := first + uint64(i)
+		if number == 0 {
+			continue
+		}
+		batch.Delete(headerHashKey(number))
+		batch.Delete(headerKey(number, hash))
+		batch.Delete(blockBodyKey(number, hash))
+		batch.Delete(blockReceiptsKey(number, hash))
+		batch.Delete(blockConfirmSignsKey(number, hash))
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete frozen canonical blocks", "err", err)
+	}
+	log.Info("Deep froze chain segment", "blocks", len(ancients), "elapsed", common.PrettyDuration(time.Since(start)), "number", first+uint64(len(ancients))-1, "hash", ancients[len(ancients)-1])
+
+	return len(ancients) == freezerBatchLimit
+}
@@ -0,0 +1,262 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/snappy"
+)
+
+var (
+	// errClosed is returned if an operation attempts to read from or write to the
+	// freezer table after it has already been closed.
+	errClosed = errors.New("closed")
+
+	// errOutOfBounds is returned if the item requested is not contained within the
+	// freezer table.
+	errOutOfBounds = errors.New("out of bounds")
+
+	// errOutOrderInsertion is returned if the user attempts to inject out-of-order
+	// binary blobs into the freezer.
+	errOutOrderInsertion = errors.New("the append operation is out-order")
+)
+
+// indexEntrySize is the size of an index entry, the big endian end offset of an
+// item in the data file.
+const indexEntrySize = 8
+
+// freezerTable is an append-only table of binary blobs stored in flat files. The
+// blobs are kept back to back in a data file, an index file holds the offset in
+// the data file each blob ends at. Items are numbered from zero.
+type freezerTable struct {
+	items uint64 // Number of items stored in the table, must be accessed atomically
+
+	noCompression bool     // Whether the blobs are stored as is instead of snappy compressed
+	data          *os.File // File descriptor of the data file
+	index         *os.File // File descriptor of the index file
+	dataBytes     uint64   // Number of bytes written to the data file
+
+	lock sync.RWMutex // Mutex protecting the files from concurrent truncation and closing
+}
+
+// newTable opens a freezer table, creating the data and index files if they do
+// not exist yet and repairing them after an unclean shutdown.
+func newTable(path string, name string, disableSnappy bool) (*freezerTable, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	ext := "cdat"
+	if disableSnappy {
+		ext = "rdat"
+	}
+	data, err := os.OpenFile(filepath.Join(path, fmt.Sprintf("%s.%s", name, ext)), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(path, fmt.Sprintf("%s.ridx", name)), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+	tab := &freezerTable{
+		noCompression: disableSnappy,
+		data:          data,
+		index:         index,
+	}
+	if err := tab.repair(); err != nil {
+		tab.Close()
+		return nil, err
+	}
+	return tab, nil
+}
+
+// repair cross checks the data and index files, truncating them to the last
+// item fully written to both.
+func (t *freezerTable) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	items := uint64(stat.Size()) / indexEntrySize
+	if stat, err = t.data.Stat(); err != nil {
+		return err
+	}
+	size := uint64(stat.Size())
+
+	// Drop the items whose data was not fully written
+	var end uint64
+	for ; items > 0; items-- {
+		if end, err = t.offset(items); err != nil {
+			return err
+		}
+		if end <= size {
+			break
+		}
+	}
+	if items == 0 {
+		end = 0
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(end)); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&t.items, items)
+	t.dataBytes = end
+	return nil
+}
+
+// offset returns the offset in the data file the given number of items end at.
+func (t *freezerTable) offset(items uint64) (uint64, error) {
+	if items == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, indexEntrySize)
+	if _, err := t.index.ReadAt(buf, int64((items-1)*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// Items returns the number of items stored in the table.
+func (t *freezerTable) Items() uint64 {
+	return atomic.LoadUint64(&t.items)
+}
+
+// Append injects a binary blob at the end of the freezer table. The item number
+// must be the number of items already stored.
+func (t *freezerTable) Append(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil || t.data == nil {
+		return errClosed
+	}
+	if atomic.LoadUint64(&t.items) != item {
+		return errOutOrderInsertion
+	}
+	if !t.noCompression {
+		blob = snappy.Encode(nil, blob)
+	}
+	if _, err := t.data.WriteAt(blob, int64(t.dataBytes)); err != nil {
+		return err
+	}
+	entry := make([]byte, indexEntrySize)
+	binary.BigEndian.PutUint64(entry, t.dataBytes+uint64(len(blob)))
+	if _, err := t.index.WriteAt(entry, int64(item*indexEntrySize)); err != nil {
+		return err
+	}
+	t.dataBytes += uint64(len(blob))
+	atomic.AddUint64(&t.items, 1)
+	return nil
+}
+
+// Retrieve looks up the data offset of an item with the given number and
+// retrieves the raw binary blob from the data file.
+func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil || t.data == nil {
+		return nil, errClosed
+	}
+	if atomic.LoadUint64(&t.items) <= item {
+		return nil, errOutOfBounds
+	}
+	start, err := t.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	end, err := t.offset(item + 1)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil {
+		return nil, err
+	}
+	if t.noCompression {
+		return blob, nil
+	}
+	return snappy.Decode(nil, blob)
+}
+
+// truncate discards any recent data above the provided threshold number.
+func (t *freezerTable) truncate(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if atomic.LoadUint64(&t.items) <= items {
+		return nil
+	}
+	end, err := t.offset(items)
+	if err != nil {
+		return err
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(end)); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&t.items, items)
+	t.dataBytes = end
+	return nil
+}
+
+// Sync pushes any pending data from memory out to disk.
+func (t *freezerTable) Sync() error {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil || t.data == nil {
+		return errClosed
+	}
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	return t.data.Sync()
+}
+
+// Close closes all opened files.
+func (t *freezerTable) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var errs []error
+	for _, f := range []*os.File{t.index, t.data} {
+		if f == nil {
+			continue
+		}
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.index, t.data = nil, nil
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
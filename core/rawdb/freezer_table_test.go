@@ -0,0 +1,130 @@
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests that blobs appended to a freezer table can be retrieved, also after
+// reopening it, and that out of order or out of bounds accesses are rejected.
+func TestFreezerTableAppendRetrieve(t *testing.T) {
+	for _, noSnappy := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "freezer")
+		if err != nil {
+			t.Fatalf("failed to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		tab, err := newTable(dir, "test", noSnappy)
+		if err != nil {
+			t.Fatalf("failed to open table: %v", err)
+		}
+		for i := uint64(0); i < 100; i++ {
+			if err := tab.Append(i, bytes.Repeat([]byte{byte(i)}, int(i))); err != nil {
+				t.Fatalf("failed to append item %d: %v", i, err)
+			}
+		}
+		if err := tab.Append(101, []byte{1}); err != errOutOrderInsertion {
+			t.Fatalf("out of order append error mismatch: have %v, want %v", err, errOutOrderInsertion)
+		}
+		if _, err := tab.Retrieve(100); err != errOutOfBounds {
+			t.Fatalf("out of bounds retrieval error mismatch: have %v, want %v", err, errOutOfBounds)
+		}
+		tab.Close()
+
+		if tab, err = newTable(dir, "test", noSnappy); err != nil {
+			t.Fatalf("failed to reopen table: %v", err)
+		}
+		if items := tab.Items(); items != 100 {
+			t.Fatalf("item count mismatch: have %d, want %d", items, 100)
+		}
+		for i := uint64(0); i < 100; i++ {
+			blob, err := tab.Retrieve(i)
+			if err != nil {
+				t.Fatalf("failed to retrieve item %d: %v", i, err)
+			}
+			if want := bytes.Repeat([]byte{byte(i)}, int(i)); !bytes.Equal(blob, want) {
+				t.Fatalf("item %d mismatch: have %x, want %x", i, blob, want)
+			}
+		}
+		tab.Close()
+	}
+}
+
+// Tests that truncating a table drops the items above the threshold and that
+// appends continue from there.
+func TestFreezerTableTruncate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tab, err := newTable(dir, "test", false)
+	if err != nil {
+		t.Fatalf("failed to open table: %v", err)
+	}
+	defer tab.Close()
+
+	for i := uint64(0); i < 10; i++ {
+		if err := tab.Append(i, []byte(fmt.Sprintf("item %d", i))); err != nil {
+			t.Fatalf("failed to append item %d: %v", i, err)
+		}
+	}
+	if err := tab.truncate(5); err != nil {
+		t.Fatalf("failed to truncate table: %v", err)
+	}
+	if items := tab.Items(); items != 5 {
+		t.Fatalf("item count mismatch: have %d, want %d", items, 5)
+	}
+	if err := tab.Append(5, []byte("replaced")); err != nil {
+		t.Fatalf("failed to append after truncation: %v", err)
+	}
+	if blob, _ := tab.Retrieve(5); string(blob) != "replaced" {
+		t.Fatalf("item mismatch after truncation: have %q, want %q", blob, "replaced")
+	}
+	if blob, _ := tab.Retrieve(4); string(blob) != "item 4" {
+		t.Fatalf("item mismatch below truncation: have %q, want %q", blob, "item 4")
+	}
+}
+
+// Tests that a table whose data file lost the tail of the last write is repaired
+// to the last complete item on open.
+func TestFreezerTableRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tab, err := newTable(dir, "test", true)
+	if err != nil {
+		t.Fatalf("failed to open table: %v", err)
+	}
+	for i := uint64(0); i < 3; i++ {
+		if err := tab.Append(i, bytes.Repeat([]byte{0xff}, 10)); err != nil {
+			t.Fatalf("failed to append item %d: %v", i, err)
+		}
+	}
+	tab.Close()
+
+	// Chop off part of the last item, as an interrupted write would
+	if err := os.Truncate(filepath.Join(dir, "test.rdat"), 25); err != nil {
+		t.Fatalf("failed to truncate data file: %v", err)
+	}
+	if tab, err = newTable(dir, "test", true); err != nil {
+		t.Fatalf("failed to reopen table: %v", err)
+	}
+	defer tab.Close()
+
+	if items := tab.Items(); items != 2 {
+		t.Fatalf("item count mismatch: have %d, want %d", items, 2)
+	}
+	if err := tab.Append(2, []byte{1}); err != nil {
+		t.Fatalf("failed to append after repair: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package rawdb
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+)
+
+// Tests that the freezer moves blocks past the recent window out of the
+// key-value store, and that the accessors keep serving them from the ancient
+// store.
+func TestFreezerMovesAncientBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open leveldb: %v", err)
+	}
+	var (
+		blocks = freezerRecentBlocks + 6
+		hashes = make([]common.Hash, blocks)
+	)
+	for i := 0; i < blocks; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte("test header")}
+		hashes[i] = header.Hash()
+
+		WriteHeader(kvdb, header)
+		WriteBody(kvdb, hashes[i], uint64(i), &types.Body{})
+		WriteCanonicalHash(kvdb, hashes[i], uint64(i))
+	}
+	WriteHeadHeaderHash(kvdb, hashes[blocks-1])
+	WriteHeadBlockHash(kvdb, hashes[blocks-1])
+
+	frdb, err := newFreezer(filepath.Join(dir, "ancient"))
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	db := &freezerdb{LDBDatabase: kvdb, freezer: frdb}
+	defer db.Close()
+
+	if frdb.freezeBatch(kvdb) {
+		t.Fatalf("freezer reported pending blocks after a partial batch")
+	}
+	if frozen, _ := db.Ancients(); frozen != 6 {
+		t.Fatalf("frozen block count mismatch: have %d, want %d", frozen, 6)
+	}
+	for i := uint64(0); i < 6; i++ {
+		if has, _ := kvdb.Has(headerKey(i, hashes[i])); has != (i == 0) {
+			t.Errorf("block %d: header in key-value store mismatch: have %v, want %v", i, has, i == 0)
+		}
+		if hash := ReadCanonicalHash(db, i); hash != hashes[i] {
+			t.Errorf("block %d: canonical hash mismatch: have %x, want %x", i, hash, hashes[i])
+		}
+		if header := ReadHeader(db, hashes[i], i); header == nil || header.Hash() != hashes[i] {
+			t.Errorf("block %d: frozen header unavailable", i)
+		}
+		if body := ReadBody(db, hashes[i], i); body == nil {
+			t.Errorf("block %d: frozen body unavailable", i)
+		}
+	}
+	if header := ReadHeader(db, hashes[6], 6); header == nil {
+		t.Errorf("recent header unavailable")
+	}
+	// Rewinding the ancient store drops the frozen blocks above the new head
+	if err := db.TruncateAncients(3); err != nil {
+		t.Fatalf("failed to truncate ancient store: %v", err)
+	}
+	if header := ReadHeader(db, hashes[4], 4); header != nil {
+		t.Errorf("truncated header still available")
+	}
+}
+
+// Tests that an ancient store is refused if the key-value store does not
+// continue where it ends.
+func TestFreezerValidation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open leveldb: %v", err)
+	}
+	defer kvdb.Close()
+
+	genesis := &types.Header{Number: big.NewInt(0)}
+	head := &types.Header{Number: big.NewInt(2), Extra: []byte("test header")}
+	WriteCanonicalHash(kvdb, genesis.Hash(), 0)
+	WriteHeader(kvdb, head)
+	WriteHeadHeaderHash(kvdb, head.Hash())
+
+	// Block 1 is neither in the key-value nor in the (empty) ancient store
+	if _, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "ancient")); err == nil {
+		t.Fatalf("freezer with a gap to the key-value store accepted")
+	}
+}
+
+// Tests that the history of frozen blocks reads back as pruned below the oldest
+// retained block, whether the blocks were frozen before or after the history
+// pruner reached them.
+func TestFreezerPrunedHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open leveldb: %v", err)
+	}
+	var (
+		blocks   = freezerRecentBlocks + 10
+		hashes   = make([]common.Hash, blocks)
+		receipts = types.Receipts{&types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}}}
+	)
+	for i := 0; i < blocks; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte("test header")}
+		hashes[i] = header.Hash()
+
+		WriteHeader(kvdb, header)
+		WriteBody(kvdb, hashes[i], uint64(i), &types.Body{})
+		WriteReceipts(kvdb, hashes[i], uint64(i), receipts)
+		WriteCanonicalHash(kvdb, hashes[i], uint64(i))
+	}
+	WriteHeadHeaderHash(kvdb, hashes[blocks-6])
+	WriteHeadBlockHash(kvdb, hashes[blocks-6])
+
+	frdb, err := newFreezer(filepath.Join(dir, "ancient"))
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	db := &freezerdb{LDBDatabase: kvdb, freezer: frdb}
+	defer db.Close()
+
+	// Blocks 0-4 are frozen with their history, which is pruned afterwards
+	frdb.freezeBatch(kvdb)
+	WriteOldestRetainedBlock(kvdb, 3)
+	for i := uint64(1); i < 5; i++ {
+		body, receipts := ReadBody(db, hashes[i], i), ReadReceipts(db, hashes[i], i)
+		if pruned := i < 3; (body == nil) != pruned || (receipts == nil) != pruned {
+			t.Errorf("block %d: history mismatch: have body %v, receipts %v, want pruned %v", i, body != nil, receipts != nil, pruned)
+		}
+		if header := ReadHeader(db, hashes[i], i); header == nil {
+			t.Errorf("block %d: frozen header unavailable", i)
+		}
+	}
+	// Blocks 5-9 are frozen empty below the oldest retained block
+	WriteOldestRetainedBlock(kvdb, 8)
+	WriteHeadBlockHash(kvdb, hashes[blocks-1])
+	frdb.freezeBatch(kvdb)
+	for i := uint64(5); i < 10; i++ {
+		pruned := i < 8
+		if blob, _ := db.Ancient(freezerBodiesTable, i); (len(blob) == 0) != pruned {
+			t.Errorf("block %d: frozen body mismatch: have %d bytes, want pruned %v", i, len(blob), pruned)
+		}
+		if blob, _ := db.Ancient(freezerReceiptTable, i); (len(blob) == 0) != pruned {
+			t.Errorf("block %d: frozen receipts mismatch: have %d bytes, want pruned %v", i, len(blob), pruned)
+		}
+		if body := ReadBody(db, hashes[i], i); (body == nil) != pruned {
+			t.Errorf("block %d: body mismatch: have %v, want pruned %v", i, body != nil, pruned)
+		}
+	}
+}
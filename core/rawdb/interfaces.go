@@ -31,3 +31,23 @@ type DatabaseWriter interface {
 type DatabaseDeleter interface {
 	Delete(key []byte) error
 }
+
+// AncientReader wraps the read methods of the ancient store holding the frozen,
+// immutable chain segment.
+type AncientReader interface {
+	// HasAncient returns an indicator whether the specified data exists in the
+	// ancient store.
+	HasAncient(kind string, number uint64) (bool, error)
+
+	// Ancient retrieves an ancient binary blob from the append-only immutable files.
+	Ancient(kind string, number uint64) ([]byte, error)
+
+	// Ancients returns the number of blocks frozen in the ancient store.
+	Ancients() (uint64, error)
+}
+
+// AncientWriter wraps the write methods of the ancient store.
+type AncientWriter interface {
+	// TruncateAncients discards all but the first n ancient blocks.
+	TruncateAncients(n uint64) error
+}
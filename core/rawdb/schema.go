@@ -41,6 +41,10 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// oldestRetainedBlockKey tracks the oldest block whose body and receipts were
+	// not removed by history expiry.
+	oldestRetainedBlockKey = []byte("OldestRetainedBlock")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerHashSuffix   = []byte("n") // headerPrefix + num (uint64 big endian) + headerHashSuffix -> hash
@@ -52,6 +56,7 @@ var (
 
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	addressTxPrefix = []byte("a") // addressTxPrefix + address + section (uint64 big endian) + hash -> address transaction entries
 
 	preimagePrefix      = []byte("secure-key-")        // preimagePrefix + hash -> preimage
 	configPrefix        = []byte("ethereum-config-")   // config prefix for the db
@@ -59,11 +64,19 @@ var (
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	AddressTxIndexPrefix = []byte("iA") // AddressTxIndexPrefix is the data table of the address transaction indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
 
+// AddressTxEntry is the position in the canonical chain of a transaction an
+// address took part in.
+type AddressTxEntry struct {
+	BlockNumber uint64
+	Index       uint64
+}
+
 // TxLookupEntry is a positional metadata to help looking up the data content of
 // a transaction or receipt given only its hash.
 type TxLookupEntry struct {
@@ -124,6 +137,14 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// addressTxKey = addressTxPrefix + address + section (uint64 big endian) + hash
+func addressTxKey(address common.Address, section uint64, hash common.Hash) []byte {
+	key := append(append(addressTxPrefix, address.Bytes()...), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], section)
+
+	return append(key, hash.Bytes()...)
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -113,6 +113,14 @@ type (
 		valueKey common.Hash
 		preValue []byte
 	}
+	storageCopyChange struct {
+		account            *common.Address
+		prevTrie           Trie
+		originStorage      Storage
+		originValueStorage ValueStorage
+		dirtyStorage       Storage
+		dirtyValueStorage  ValueStorage
+	}
 	codeChange struct {
 		account            *common.Address
 		prevcode, prevhash []byte
@@ -220,6 +228,19 @@ func (ch storageChange) dirtied() *common.Address {
 	return ch.account
 }
 
+func (ch storageCopyChange) revert(s *StateDB) {
+	obj := s.getStateObject(*ch.account)
+	obj.trie = ch.prevTrie
+	obj.originStorage = ch.originStorage
+	obj.originValueStorage = ch.originValueStorage
+	obj.dirtyStorage = ch.dirtyStorage
+	obj.dirtyValueStorage = ch.dirtyValueStorage
+}
+
+func (ch storageCopyChange) dirtied() *common.Address {
+	return ch.account
+}
+
 func (ch refundChange) revert(s *StateDB) {
 	s.refund = ch.prev
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/trie"
 )
 
 var emptyCodeHash = crypto.Keccak256(nil)
@@ -306,8 +307,8 @@ func (self *stateObject) GetCommittedState(db Database, key string) []byte {
 		}
 		valueKey.SetBytes(content)
 
-		//load value from db
-		value = self.db.trie.GetKey(valueKey.Bytes())
+		//load value from cache or db
+		value = self.getValue(valueKey)
 		if err != nil {
 			self.setError(err)
 		}
@@ -360,6 +361,67 @@ func (self *stateObject) setState(key string, valueKey common.Hash, value []byte
 	self.dirtyValueStorage[valueKey] = cpy
 }
 
+// getValue returns the value stored under valueKey. Values are addressed by
+// their hash, so any cached copy of the account, including the ones of the
+// uncommitted parent states, can provide it before the database is read.
+func (self *stateObject) getValue(valueKey common.Hash) []byte {
+	if value, ok := self.dirtyValueStorage[valueKey]; ok {
+		return value
+	}
+	if value, ok := self.originValueStorage[valueKey]; ok {
+		return value
+	}
+
+	self.db.refLock.Lock()
+	parentDB := self.db.parent
+	refLock := &self.db.refLock
+	for parentDB != nil {
+		if value := parentDB.getStateObjectValue(self.address, valueKey); value != nil {
+			refLock.Unlock()
+			return value
+		}
+		refLock.Unlock()
+		parentDB.refLock.Lock()
+		refLock = &parentDB.refLock
+		parentDB = parentDB.parent
+	}
+	refLock.Unlock()
+
+	return self.db.trie.GetKey(valueKey.Bytes())
+}
+
+// copyStorage replaces the storage of the object with a copy of the storage
+// trie of src, or with an empty storage if src is nil. The values of the
+// entries are cached so that the ones not committed yet stay readable.
+func (self *stateObject) copyStorage(db Database, src *stateObject) {
+	self.originStorage = make(Storage)
+	self.originValueStorage = make(map[common.Hash][]byte)
+	self.dirtyStorage = make(Storage)
+	self.dirtyValueStorage = make(map[common.Hash][]byte)
+
+	if src == nil {
+		tr, err := db.OpenStorageTrie(self.addrHash, common.Hash{})
+		self.setError(err)
+		self.trie = tr
+		return
+	}
+	tr := src.updateTrie(db)
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			self.setError(err)
+			continue
+		}
+		valueKey := common.BytesToHash(content)
+		if value := src.getValue(valueKey); value != nil {
+			self.originValueStorage[valueKey] = value
+		}
+	}
+	self.setError(it.Err)
+	self.trie = db.CopyTrie(tr)
+}
+
 // updateTrie writes cached storage modifications into the object's storage trie.
 func (self *stateObject) updateTrie(db Database) Trie {
 	tr := self.getTrie(db)
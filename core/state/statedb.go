@@ -581,6 +581,19 @@ func (self *StateDB) getStateObjectSnapshot(addr common.Address, key string) (co
 	return common.Hash{}, nil
 }
 
+// Find stateObject storage value in cache
+func (self *StateDB) getStateObjectValue(addr common.Address, valueKey common.Hash) []byte {
+	if obj := self.stateObjects[addr]; obj != nil {
+		if value, ok := obj.dirtyValueStorage[valueKey]; ok {
+			return value
+		}
+		if value, ok := obj.originValueStorage[valueKey]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
 // Add childrent statedb reference
 func (self *StateDB) AddReferenceFunc(fn func()) {
 	self.refLock.Lock()
@@ -706,6 +719,44 @@ func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value common
 	}
 }
 
+// StorageEntries returns the number of storage entries of an account with a
+// non-empty value. The entries are counted on the storage trie, so the keys
+// they were written under are not needed.
+func (self *StateDB) StorageEntries(addr common.Address) uint64 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	so := self.getStateObject(addr)
+	if so == nil {
+		return 0
+	}
+	var entries uint64
+	it := trie.NewIterator(so.updateTrie(self.db).NodeIterator(nil))
+	for it.Next() {
+		entries++
+	}
+	self.setError(it.Err)
+	return entries
+}
+
+// CopyStorage replaces the storage of the account to with a copy of the
+// storage of the account from, discarding the entries to had before.
+func (self *StateDB) CopyStorage(from, to common.Address) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	dst := self.GetOrNewStateObject(to)
+	self.journal.append(storageCopyChange{
+		account:            &to,
+		prevTrie:           dst.trie,
+		originStorage:      dst.originStorage,
+		originValueStorage: dst.originValueStorage,
+		dirtyStorage:       dst.dirtyStorage,
+		dirtyValueStorage:  dst.dirtyValueStorage,
+	})
+	dst.copyStorage(self.db, self.getStateObject(from))
+}
+
 // Copy creates a deep, independent copy of the state.
 // Snapshots of the copied state cannot be applied to the copy.
 func (self *StateDB) Copy() *StateDB {
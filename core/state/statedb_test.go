@@ -758,3 +758,72 @@ func TestGetAfterDelete(t *testing.T) {
 	assert.Equal(t, buf, []byte("value"))
 	assert.Equal(t, buf1, []byte("value1"))
 }
+
+func TestStorageEntries(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	addr := common.Address{1}
+
+	s1, _ := New(common.Hash{}, NewDatabase(db))
+	s1.SetNonce(addr, 1)
+	s1.SetState(addr, []byte("b"), []byte("2"))
+	s1.SetState(addr, []byte("a"), []byte("1"))
+	root, err := s1.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.db.TrieDB().Commit(root, true, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries are counted on the trie, the dirty ones are flushed first
+	s2, _ := New(root, NewDatabase(db))
+	s2.SetState(addr, []byte("c"), []byte("3"))
+	s2.IntermediateRoot(true)
+	s3 := s2.NewStateDB()
+	s3.SetState(addr, []byte("0"), []byte("4"))
+	s3.SetState(addr, []byte("a"), nil)
+
+	assert.Equal(t, uint64(3), s3.StorageEntries(addr))
+	assert.Equal(t, uint64(0), s3.StorageEntries(common.Address{2}))
+}
+
+func TestCopyStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	from, to := common.Address{1}, common.Address{2}
+
+	s1, _ := New(common.Hash{}, NewDatabase(db))
+	s1.SetNonce(from, 1)
+	s1.SetState(from, []byte("a"), []byte("1"))
+	root, err := s1.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.db.TrieDB().Commit(root, true, true); err != nil {
+		t.Fatal(err)
+	}
+
+	s2, _ := New(root, NewDatabase(db))
+	s2.SetState(from, []byte("b"), []byte("2"))
+	s2.IntermediateRoot(true)
+	s3 := s2.NewStateDB()
+	s3.SetState(from, []byte("c"), []byte("3"))
+	s3.SetNonce(to, 1)
+	s3.SetState(to, []byte("d"), []byte("4"))
+
+	snapshot := s3.Snapshot()
+	s3.CopyStorage(from, to)
+	for key, value := range map[string]string{"a": "1", "b": "2", "c": "3", "d": ""} {
+		assert.Equal(t, []byte(value), s3.GetState(to, []byte(key)), "key %s", key)
+	}
+	s3.RevertToSnapshot(snapshot)
+	assert.Equal(t, []byte{}, s3.GetState(to, []byte("a")))
+	assert.Equal(t, []byte("4"), s3.GetState(to, []byte("d")))
+
+	// The copy is read back by the next state before any of them is committed
+	s3.CopyStorage(from, to)
+	s3.IntermediateRoot(true)
+	s4 := s3.NewStateDB()
+	assert.Equal(t, []byte("2"), s4.GetState(to, []byte("b")))
+	assert.Equal(t, []byte("3"), s4.GetState(to, []byte("c")))
+	assert.Equal(t, s3.StorageEntries(from), s4.StorageEntries(to))
+}
@@ -18,11 +18,11 @@ package core
 
 import (
 	"errors"
-	"fmt"
 	"math"
 	"math/big"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/params"
@@ -56,6 +56,8 @@ type StateTransition struct {
 	data       []byte
 	state      vm.StateDB
 	evm        *vm.EVM
+
+	callReceipts []*types.CallReceipt // Outcome of the calls of a batch
 }
 
 // Message represents a message sent to a contract.
@@ -71,6 +73,18 @@ type Message interface {
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+
+	// Payer is the account paying for the gas, the sender unless sponsored.
+	Payer() common.Address
+	// Calls are the calls of a batch, executed instead of To, Value and Data.
+	// They are nil for single calls.
+	Calls() []types.BatchCall
+}
+
+// logKeeper is implemented by the state databases keeping the logs of the
+// current transaction, allowing a batch to attribute them to its calls.
+type logKeeper interface {
+	GetLogs(hash common.Hash) []*types.Log
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
@@ -83,6 +97,37 @@ func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
 		gas = params.TxGas
 	}
 	// Bump the required gas by the amount of transactional data
+	return addDataGas(gas, data)
+}
+
+// BatchIntrinsicGas computes the 'intrinsic gas' for a batch with the given calls.
+func BatchIntrinsicGas(calls []types.BatchCall) (uint64, error) {
+	if len(calls) == 0 || len(calls) > params.MaxBatchCalls {
+		return 0, ErrInvalidBatch
+	}
+	gas := params.TxGas
+	for _, call := range calls {
+		var err error
+		if gas, err = addDataGas(gas+params.TxBatchCallGas, call.Data); err != nil {
+			return 0, err
+		}
+	}
+	return gas, nil
+}
+
+// checkBatchCalls makes sure no call of a batch targets a PPOS contract. Those
+// keep their data in the snapshot db, out of reach of a state revert.
+func checkBatchCalls(calls []types.BatchCall) error {
+	for _, call := range calls {
+		if _, ok := vm.PlatONPrecompiledContracts[call.To]; ok {
+			return ErrBatchPPOSCall
+		}
+	}
+	return nil
+}
+
+// addDataGas bumps gas by the cost of the given transactional data.
+func addDataGas(gas uint64, data []byte) (uint64, error) {
 	if len(data) > 0 {
 		// Zero and non-zero bytes are priced differently
 		var nz uint64
@@ -149,7 +194,7 @@ func (st *StateTransition) useGas(amount uint64) error {
 
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if st.state.GetBalance(st.msg.From()).Cmp(mgval) < 0 {
+	if st.state.GetBalance(st.msg.Payer()).Cmp(mgval) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -158,7 +203,7 @@ func (st *StateTransition) buyGas() error {
 	st.gas += st.msg.Gas()
 
 	st.initialGas = st.msg.Gas()
-	st.state.SubBalance(st.msg.From(), mgval)
+	st.state.SubBalance(st.msg.Payer(), mgval)
 	return nil
 }
 
@@ -184,6 +229,9 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		return
 	}
 	msg := st.msg
+	if calls := msg.Calls(); calls != nil {
+		return st.transitionBatch(calls)
+	}
 	sender := vm.AccountRef(msg.From())
 	contractCreation := msg.To() == nil
 
@@ -206,7 +254,7 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 
 	// todo: shield contract to created in temporary
 	if contractCreation {
-		return nil, params.TxGasContractCreation, false, fmt.Errorf("contract creation is not allowed")
+		return nil, params.TxGasContractCreation, false, ErrContractCreation
 	}
 
 	if contractCreation {
@@ -233,6 +281,82 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	return ret, st.gasUsed(), vmerr != nil, err
 }
 
+// transitionBatch executes the calls of a batch in order. As soon as one fails
+// the state changes of all of them are reverted, so the batch fails as a whole.
+// The outcome of each call is kept in st.callReceipts, all of them failed along
+// with the batch. Batches calling PPOS contracts are invalid, calls to them
+// made by contracts of the batch fail.
+func (st *StateTransition) transitionBatch(calls []types.BatchCall) (ret []byte, usedGas uint64, failed bool, err error) {
+	if err := checkBatchCalls(calls); err != nil {
+		return nil, 0, false, err
+	}
+	// Pay intrinsic gas
+	gas, err := BatchIntrinsicGas(calls)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if err = st.useGas(gas); err != nil {
+		return nil, 0, false, err
+	}
+	sender := vm.AccountRef(st.msg.From())
+
+	// Increment the nonce for the next transaction
+	st.state.SetNonce(sender.Address(), st.state.GetNonce(sender.Address())+1)
+
+	// Calls made by the contracts of the batch can't reach PPOS either
+	st.evm.SetBatch(true)
+	defer st.evm.SetBatch(false)
+
+	var (
+		snapshot = st.state.Snapshot()
+		logged   = len(st.txLogs())
+	)
+	st.callReceipts = make([]*types.CallReceipt, len(calls))
+	for i, call := range calls {
+		// Calls following a failed one are not executed
+		receipt := &types.CallReceipt{Status: types.ReceiptStatusFailed, Logs: []*types.Log{}}
+		st.callReceipts[i] = receipt
+		if failed {
+			continue
+		}
+		var (
+			gas   = st.gas
+			vmerr error
+		)
+		ret, st.gas, vmerr = st.evm.Call(sender, call.To, call.Data, st.gas, call.Value)
+		receipt.GasUsed = gas - st.gas
+		if vmerr != nil {
+			log.Debug("Batch call returned with error", "index", i, "err", vmerr)
+			// a failed execution only returns data when the contract reverted
+			receipt.RevertData, failed = ret, true
+			continue
+		}
+		receipt.Status = types.ReceiptStatusSuccessful
+		logs := st.txLogs()
+		receipt.Logs, logged = logs[logged:], len(logs)
+	}
+	if failed {
+		st.state.RevertToSnapshot(snapshot)
+		for _, receipt := range st.callReceipts {
+			receipt.Status, receipt.Logs = types.ReceiptStatusFailed, []*types.Log{}
+		}
+	}
+	st.refundGas()
+
+	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+
+	return ret, st.gasUsed(), failed, nil
+}
+
+// txLogs returns the logs emitted so far by the transaction, if the state
+// database keeps them.
+func (st *StateTransition) txLogs() []*types.Log {
+	if db, ok := st.state.(logKeeper); ok {
+		return db.GetLogs(st.state.TxHash())
+	}
+	return nil
+}
+
 func (st *StateTransition) refundGas() {
 	// Apply refund counter, capped to half of the used gas.
 	refund := st.gasUsed() / 2
@@ -243,7 +367,7 @@ func (st *StateTransition) refundGas() {
 
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(st.msg.From(), remaining)
+	st.state.AddBalance(st.msg.Payer(), remaining)
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+// Tests that a batch is applied as a whole: a failing call reverts the calls
+// before it and fails all their receipts, and PPOS contracts can't be called,
+// neither directly nor by the contracts of the batch.
+func TestBatchTransition(t *testing.T) {
+	chainConfig := *params.TestChainConfig
+	chainConfig.SponsorBlock = big.NewInt(0)
+	chainConfig.BatchBlock = big.NewInt(0)
+	chainConfig.VMInterpreter = "evm"
+
+	var (
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		signer    = types.NewBatchSigner(chainConfig.ChainID)
+		recipient = common.Address{1}
+		logger    = common.Address{2} // LOG0 of empty memory, STOP
+		reverter  = common.Address{3} // REVERT with empty data
+		nested    = common.Address{4} // stores whether a call to the staking contract succeeded
+		header    = &types.Header{Number: big.NewInt(1), Time: big.NewInt(0), GasLimit: 10000000, Extra: make([]byte, 97)}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.SetBalance(sender, big.NewInt(params.LAT))
+	statedb.SetCode(logger, common.Hex2Bytes("60006000a000"))
+	statedb.SetCode(reverter, common.Hex2Bytes("60006000fd"))
+	statedb.SetCode(nested, common.Hex2Bytes("6000600060006000600073"+common.Bytes2Hex(cvm.StakingContractAddr.Bytes())+"620186a0f160005500"))
+
+	apply := func(calls ...types.BatchCall) (*types.Receipt, error) {
+		tx, _ := types.SignTx(types.NewBatchTransaction(statedb.GetNonce(sender), calls, 1000000, big.NewInt(1)), signer, key)
+		statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+		usedGas := uint64(0)
+		receipt, _, err := ApplyTransaction(&chainConfig, nil, new(GasPool).AddGas(header.GasLimit), statedb, header, tx, &usedGas, vm.Config{})
+		return receipt, err
+	}
+	var (
+		transfer = types.BatchCall{To: recipient, Value: big.NewInt(5)}
+		log      = types.BatchCall{To: logger, Value: new(big.Int)}
+		revert   = types.BatchCall{To: reverter, Value: new(big.Int)}
+		stake    = types.BatchCall{To: nested, Value: new(big.Int)}
+	)
+
+	receipt, err := apply(transfer, log, revert)
+	if err != nil {
+		t.Fatalf("failed to apply failing batch: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed || len(receipt.Logs) != 0 {
+		t.Errorf("failing batch receipt mismatch: status %d, %d logs", receipt.Status, len(receipt.Logs))
+	}
+	for i, call := range receipt.Calls {
+		if call.Status != types.ReceiptStatusFailed || len(call.Logs) != 0 {
+			t.Errorf("call %d of failing batch: status %d, %d logs", i, call.Status, len(call.Logs))
+		}
+	}
+	if balance := statedb.GetBalance(recipient); balance.Sign() != 0 {
+		t.Errorf("transfer of failing batch not reverted: balance %v", balance)
+	}
+	if nonce := statedb.GetNonce(sender); nonce != 1 {
+		t.Errorf("nonce mismatch after failing batch: have %d, want 1", nonce)
+	}
+
+	receipt, err = apply(transfer, log)
+	if err != nil {
+		t.Fatalf("failed to apply batch: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful || len(receipt.Logs) != 1 {
+		t.Errorf("batch receipt mismatch: status %d, %d logs", receipt.Status, len(receipt.Logs))
+	}
+	for i, call := range receipt.Calls {
+		if call.Status != types.ReceiptStatusSuccessful || len(call.Logs) != i {
+			t.Errorf("call %d of batch: status %d, %d logs", i, call.Status, len(call.Logs))
+		}
+	}
+	if balance := statedb.GetBalance(recipient); balance.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("balance mismatch after batch: have %v, want 5", balance)
+	}
+
+	// Contracts of a batch can't reach PPOS contracts either
+	receipt, err = apply(stake, revert)
+	if err != nil {
+		t.Fatalf("failed to apply batch calling ppos through a contract: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Errorf("batch calling ppos through a contract: status %d", receipt.Status)
+	}
+	receipt, err = apply(stake)
+	if err != nil {
+		t.Fatalf("failed to apply batch calling ppos through a contract: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("batch calling ppos through a contract: status %d", receipt.Status)
+	}
+	if called := statedb.GetState(nested, common.Hash{}.Bytes()); common.BytesToHash(called) != (common.Hash{}) {
+		t.Errorf("ppos contract called in batch")
+	}
+
+	for _, to := range []common.Address{cvm.StakingContractAddr, cvm.RestrictingContractAddr, cvm.GovContractAddr, cvm.SlashingContractAddr} {
+		if _, err := apply(transfer, types.BatchCall{To: to, Value: new(big.Int)}); err != ErrBatchPPOSCall {
+			t.Errorf("batch calling %x: error mismatch: have %v, want %v", to, err, ErrBatchPPOSCall)
+		}
+	}
+}
+
+// Tests that the receipt of a reverted transaction carries the revert payload,
+// and that other failures don't.
+func TestRevertData(t *testing.T) {
+	chainConfig := *params.TestChainConfig
+	chainConfig.VMInterpreter = "evm"
+
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		signer   = types.NewEIP155Signer(chainConfig.ChainID)
+		reverter = common.Address{1} // REVERT with "msg"
+		invalid  = common.Address{2} // INVALID
+		header   = &types.Header{Number: big.NewInt(1), Time: big.NewInt(0), GasLimit: 10000000, Extra: make([]byte, 97)}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.SetBalance(sender, big.NewInt(params.LAT))
+	statedb.SetCode(reverter, common.Hex2Bytes("626d73676000526003601dfd"))
+	statedb.SetCode(invalid, common.Hex2Bytes("fe"))
+
+	for _, test := range []struct {
+		to   common.Address
+		data []byte
+	}{
+		{reverter, []byte("msg")},
+		{invalid, nil},
+	} {
+		tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(sender), test.to, new(big.Int), 100000, big.NewInt(1), nil), signer, key)
+		statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+		usedGas := uint64(0)
+		receipt, _, err := ApplyTransaction(&chainConfig, nil, new(GasPool).AddGas(header.GasLimit), statedb, header, tx, &usedGas, vm.Config{})
+		if err != nil {
+			t.Fatalf("failed to apply transaction to %x: %v", test.to, err)
+		}
+		if receipt.Status != types.ReceiptStatusFailed || string(receipt.RevertData) != string(test.data) {
+			t.Errorf("receipt of %x mismatch: status %d, revert data %q, want %q", test.to, receipt.Status, receipt.RevertData, test.data)
+		}
+	}
+}
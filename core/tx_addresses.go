@@ -0,0 +1,97 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+// The function types of the staking contract that target a candidate with a
+// delegation.
+const (
+	stakingDelegateFn         = 1004
+	stakingWithdrewDelegateFn = 1005
+)
+
+// TxAddresses returns the addresses a transaction is indexed under in the
+// address transaction index, each once: its sender, its recipient or the
+// recipients of its batch calls, and the candidates it delegates to or
+// withdraws delegations from. Candidates are named by their node address,
+// the address the staking plugin keys them by.
+func TxAddresses(signer types.Signer, tx *types.Transaction) ([]common.Address, error) {
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		addrs = []common.Address{from}
+		seen  = map[common.Address]bool{from: true}
+	)
+	add := func(addr common.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	addCall := func(to common.Address, data []byte) {
+		add(to)
+		if to == cvm.StakingContractAddr {
+			if target, ok := delegationTarget(data); ok {
+				add(target)
+			}
+		}
+	}
+	if calls := tx.Calls(); calls != nil {
+		for _, call := range calls {
+			addCall(call.To, call.Data)
+		}
+	} else if to := tx.To(); to != nil {
+		addCall(*to, tx.Data())
+	}
+	return addrs, nil
+}
+
+// delegationTarget returns the node address of the candidate a call to the
+// staking contract delegates to or withdraws a delegation from. Both calls
+// carry the node id as their third parameter.
+func delegationTarget(data []byte) (common.Address, bool) {
+	var params [][]byte
+	if err := rlp.DecodeBytes(data, &params); err != nil || len(params) < 3 {
+		return common.Address{}, false
+	}
+	var fn uint16
+	if err := rlp.DecodeBytes(params[0], &fn); err != nil {
+		return common.Address{}, false
+	}
+	if fn != stakingDelegateFn && fn != stakingWithdrewDelegateFn {
+		return common.Address{}, false
+	}
+	var nodeId discover.NodeID
+	if err := rlp.DecodeBytes(params[2], &nodeId); err != nil {
+		return common.Address{}, false
+	}
+	addr, err := xutil.NodeId2Addr(nodeId)
+	if err != nil {
+		return common.Address{}, false
+	}
+	return addr, true
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// stakingInput encodes a call to the staking contract the way clients do, as a
+// list of individually RLP encoded parameters.
+func stakingInput(t *testing.T, params ...interface{}) []byte {
+	var input [][]byte
+	for _, param := range params {
+		enc, err := rlp.EncodeToBytes(param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input = append(input, enc)
+	}
+	data, err := rlp.EncodeToBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// Tests that transactions are indexed under their sender, their recipients and
+// the candidates they delegate to, each once.
+func TestTxAddresses(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nodeKey, _ := crypto.GenerateKey()
+	node := crypto.PubkeyToAddress(nodeKey.PublicKey)
+	nodeId := discover.PubkeyID(&nodeKey.PublicKey)
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	signer := types.NewBatchSigner(big.NewInt(1))
+	tests := []struct {
+		tx   *types.Transaction
+		want []common.Address
+	}{
+		// Plain transfer
+		{types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), nil), []common.Address{from, to}},
+		// Transfer to self
+		{types.NewTransaction(0, from, big.NewInt(1), 21000, big.NewInt(1), nil), []common.Address{from}},
+		// Contract creation
+		{types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), []byte{0x60}), []common.Address{from}},
+		// Delegation
+		{
+			types.NewTransaction(0, cvm.StakingContractAddr, big.NewInt(0), 100000, big.NewInt(1),
+				stakingInput(t, uint16(stakingDelegateFn), uint16(0), nodeId, big.NewInt(10))),
+			[]common.Address{from, cvm.StakingContractAddr, node},
+		},
+		// Other staking calls do not name a delegation target
+		{
+			types.NewTransaction(0, cvm.StakingContractAddr, big.NewInt(0), 100000, big.NewInt(1),
+				stakingInput(t, uint16(1003), nodeId)),
+			[]common.Address{from, cvm.StakingContractAddr},
+		},
+		// Batch with a withdrawal and a transfer
+		{
+			types.NewBatchTransaction(0, []types.BatchCall{
+				{To: cvm.StakingContractAddr, Value: big.NewInt(0), Data: stakingInput(t, uint16(stakingWithdrewDelegateFn), uint64(1), nodeId, big.NewInt(10))},
+				{To: to, Value: big.NewInt(1)},
+			}, 100000, big.NewInt(1)),
+			[]common.Address{from, cvm.StakingContractAddr, node, to},
+		},
+	}
+	for i, tt := range tests {
+		tx, err := types.SignTx(tt.tx, signer, key)
+		if err != nil {
+			t.Fatalf("test %d: failed to sign: %v", i, err)
+		}
+		addrs, err := TxAddresses(signer, tx)
+		if err != nil {
+			t.Fatalf("test %d: failed to derive addresses: %v", i, err)
+		}
+		if !reflect.DeepEqual(addrs, tt.want) {
+			t.Errorf("test %d: addresses mismatch: have %x, want %x", i, addrs, tt.want)
+		}
+	}
+}
@@ -17,11 +17,16 @@
 package core
 
 import (
+	"math/big"
 	"math/rand"
 	"testing"
 
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
 // Tests that transactions can be added to strict lists and list contents and
@@ -49,3 +54,61 @@ func TestStrictTxListAdd(t *testing.T) {
 		}
 	}
 }
+
+// priorityData encodes a call of the given function of an inner contract.
+func priorityData(fnCode uint16) []byte {
+	code, _ := rlp.EncodeToBytes(fnCode)
+	data, _ := rlp.EncodeToBytes([][]byte{code})
+	return data
+}
+
+// Tests that votes, version declarations and duplicate sign reports are tracked
+// apart by the lookup, up to its priority slots, and are never discarded as
+// underpriced.
+func TestPricedListDiscardPriority(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.NewEIP155Signer(new(big.Int))
+	call := func(nonce uint64, to common.Address, fnCode uint16) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(0), 100000, big.NewInt(1), priorityData(fnCode)), signer, key)
+		return tx
+	}
+	var (
+		vote    = call(0, cvm.GovContractAddr, vm.Vote)
+		report  = call(1, cvm.SlashingContractAddr, vm.TxReportDuplicateSign)
+		query   = call(2, cvm.GovContractAddr, vm.GetProposal)
+		declare = call(3, cvm.GovContractAddr, vm.Declare)
+		plain   = pricedTransaction(4, 100000, big.NewInt(2), key)
+	)
+	for tx, want := range map[*types.Transaction]bool{vote: true, report: true, declare: true, query: false, plain: false} {
+		if have := IsPriorityTx(tx); have != want {
+			t.Errorf("transaction %x: priority mismatch: have %v, want %v", tx.Hash(), have, want)
+		}
+	}
+
+	// The declaration doesn't fit in the priority slots
+	all := newTxLookup(2)
+	priced := newTxPricedList(all)
+	for _, tx := range []*types.Transaction{vote, report, query, declare, plain} {
+		all.Add(tx)
+		priced.Put(tx)
+	}
+	if count := all.PriorityCount(); count != 2 {
+		t.Fatalf("priority count mismatch: have %d, want 2", count)
+	}
+	if all.IsPriority(declare.Hash()) {
+		t.Errorf("declaration beyond the priority slots tracked as priority")
+	}
+	drop := priced.Discard(5, newAccountSet(signer))
+	if len(drop) != 3 {
+		t.Fatalf("discarded transaction count mismatch: have %d, want 3", len(drop))
+	}
+	for _, tx := range drop {
+		if tx == vote || tx == report {
+			t.Errorf("priority transaction %x discarded", tx.Hash())
+		}
+	}
+	all.Remove(vote.Hash())
+	if count := all.PriorityCount(); count != 1 {
+		t.Errorf("priority count mismatch after removal: have %d, want 1", count)
+	}
+}
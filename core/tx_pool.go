@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -28,12 +29,22 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/prque"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// replacedCacheSize is the number of replaced transactions whose replacement
+	// is remembered by the pool.
+	replacedCacheSize = 4096
 )
 
 const (
@@ -59,6 +70,14 @@ var (
 	// with a different one without the required price bump.
 	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
 
+	// ErrInvalidPayer is returned if the payer signature of a sponsored
+	// transaction is invalid.
+	ErrInvalidPayer = errors.New("invalid payer")
+
+	// ErrInsufficientPayerFunds is returned if the payer of a sponsored
+	// transaction can't cover its gas.
+	ErrInsufficientPayerFunds = errors.New("insufficient funds for gas of the payer")
+
 	// ErrInsufficientFunds is returned if the total cost of executing a transaction
 	// is higher than the balance of the user's account.
 	ErrInsufficientFunds = errors.New("insufficient funds for gas * price + value")
@@ -109,6 +128,10 @@ var (
 	knowingTxCounter     = metrics.NewRegisteredCounter("txpool/knowing", nil)
 	invalidTxCounter     = metrics.NewRegisteredCounter("txpool/invalid", nil)
 	underpricedTxCounter = metrics.NewRegisteredCounter("txpool/underpriced", nil)
+
+	// Metrics for the pool snapshot
+	snapshotRestoredCounter = metrics.NewRegisteredCounter("txpool/snapshot/restored", nil)
+	snapshotDroppedCounter  = metrics.NewRegisteredCounter("txpool/snapshot/dropped", nil) // Failed revalidation on restore
 )
 
 // TxStatus is the current status of a transaction as seen by the pool.
@@ -161,7 +184,10 @@ type TxPoolConfig struct {
 	Locals    []common.Address // Addresses that should be treated by default as local
 	NoLocals  bool             // Whether local transaction handling should be disabled
 	Journal   string           // Journal of local transactions to survive node restarts
-	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
+	Rejournal time.Duration    // Time interval to regenerate the local transaction journal and the pool snapshot
+
+	Snapshot     string // Snapshot of the remote transactions to survive node restarts, disabled if empty
+	SnapshotSize uint64 // Maximum number of transactions stored in the snapshot
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
@@ -171,6 +197,7 @@ type TxPoolConfig struct {
 	AccountQueue  uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue   uint64 // Maximum number of non-executable transaction slots for all accounts
 	GlobalTxCount uint64 // Maximum number of transactions for package
+	PrioritySlots uint64 // Number of extra slots reserved for governance and slashing transactions
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 
@@ -184,6 +211,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	SnapshotSize: 8192,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -192,6 +221,7 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue:  64,
 	GlobalQueue:   1024,
 	GlobalTxCount: 3000,
+	PrioritySlots: 256,
 
 	Lifetime: 3 * time.Hour,
 
@@ -215,6 +245,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	if conf.SnapshotSize < 1 {
+		log.Warn("Sanitizing invalid txpool snapshot size", "provided", conf.SnapshotSize, "updated", DefaultTxPoolConfig.SnapshotSize)
+		conf.SnapshotSize = DefaultTxPoolConfig.SnapshotSize
+	}
 	return conf
 }
 
@@ -244,8 +278,9 @@ type TxPool struct {
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
 
-	locals  *accountSet // Set of local transaction to exempt from eviction rules
-	journal *txJournal  // Journal of local transaction to back up to disk
+	locals   *accountSet // Set of local transaction to exempt from eviction rules
+	journal  *txJournal  // Journal of local transaction to back up to disk
+	snapshot *txSnapshot // Snapshot of remote transactions to back up to disk
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
@@ -253,6 +288,8 @@ type TxPool struct {
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
 
+	replaced *lru.Cache // Hashes of recently replaced transactions, mapped to the hash of their replacement
+
 	wg sync.WaitGroup // for shutdown sync
 
 	txExtBuffer chan *txExt
@@ -281,11 +318,11 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain *Bloc
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       NewTxPoolBlockChain(chain),
-		signer:      types.NewEIP155Signer(chainconfig.ChainID),
+		signer:      types.MakeSigner(chainconfig, new(big.Int).Add(chain.currentBlock.Load().(*types.Block).Number(), big.NewInt(1))),
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
-		all:         newTxLookup(),
+		all:         newTxLookup(config.PrioritySlots),
 		// modified by PlatON
 		// chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		chainHeadCh: make(chan *types.Block, config.ChainHeadChanSize),
@@ -295,6 +332,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain *Bloc
 		resetHead:   chain.currentBlock.Load().(*types.Block),
 	}
 	pool.locals = newAccountSet(pool.signer)
+	pool.replaced, _ = lru.New(replacedCacheSize)
 	for _, addr := range config.Locals {
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
@@ -315,6 +353,19 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain *Bloc
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If the pool snapshot is enabled, restore the remote transactions from disk
+	if config.Snapshot != "" {
+		pool.snapshot = newTxSnapshot(config.Snapshot, config.SnapshotSize)
+
+		restored, dropped, err := pool.snapshot.load(func(txs []*types.Transaction) []error {
+			return pool.addTxs(txs, false)
+		})
+		if err != nil {
+			log.Warn("Failed to load transaction pool snapshot", "err", err)
+		}
+		snapshotRestoredCounter.Inc(int64(restored))
+		snapshotDroppedCounter.Inc(int64(dropped))
+	}
 	// Subscribe events from blockchain
 	// modified by PlatON
 	//pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
@@ -410,6 +461,15 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+			if pool.snapshot != nil {
+				pool.mu.Lock()
+				remotes := pool.remotes()
+				pool.mu.Unlock()
+
+				if err := pool.snapshot.save(remotes); err != nil {
+					log.Warn("Failed to save tx pool snapshot", "err", err)
+				}
+			}
 		}
 	}
 }
@@ -577,6 +637,12 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	// Switch signers if the next block crosses a fork
+	if signer := types.MakeSigner(pool.chainconfig, new(big.Int).Add(newHead.Number, big.NewInt(1))); !signer.Equal(pool.signer) {
+		pool.signer = signer
+		pool.locals.signer = signer
+	}
+
 	// Inject any transactions discarded due to reorgs
 	t := time.Now()
 	senderCacher.recover(pool.signer, reinject)
@@ -612,6 +678,15 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.snapshot != nil {
+		pool.mu.Lock()
+		remotes := pool.remotes()
+		pool.mu.Unlock()
+
+		if err := pool.snapshot.save(remotes); err != nil {
+			log.Warn("Failed to save tx pool snapshot", "err", err)
+		}
+	}
 	log.Info("Transaction pool stopped")
 }
 
@@ -714,12 +789,28 @@ func (pool *TxPool) PendingLimited() (map[common.Address]types.Transactions, err
 
 	txCount := 0
 	pending := make(map[common.Address]types.Transactions)
+	// Governance and slashing transactions go first, along with the transactions
+	// of their senders they depend on, so that the others don't cut them off.
+	for _, addr := range pool.prioritySenders().flatten() {
+		if txCount >= int(pool.config.GlobalTxCount) {
+			break
+		}
+		if list := pool.pending[addr]; list != nil {
+			if txs := pool.priorityPrefix(list.Flatten()); len(txs) > 0 {
+				pending[addr] = txs
+				txCount += len(txs)
+			}
+		}
+	}
 	for addr, list := range pool.pending {
-		pending[addr] = list.Flatten()
-		txCount += len(pending[addr])
 		if txCount >= int(pool.config.GlobalTxCount) {
 			break
 		}
+		if _, ok := pending[addr]; ok {
+			continue
+		}
+		pending[addr] = list.Flatten()
+		txCount += len(pending[addr])
 	}
 	log.Trace("Get pending", "duration", time.Since(now))
 	return pending, nil
@@ -754,8 +845,9 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 
 	// todo: shield contract to created in temporary
-	if tx.To() == nil {
-		return fmt.Errorf("contract creation is not allowed")
+	calls := tx.Calls()
+	if tx.To() == nil && calls == nil {
+		return ErrContractCreation
 	}
 
 	// Heuristic limit, reject transactions over 1MB to prevent DOS attacks
@@ -786,11 +878,31 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrNonceTooLow
 	}
 	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
-	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
+	// cost == V + GP * GL, or V alone if the gas is sponsored
+	if pool.currentState.GetBalance(from).Cmp(tx.SenderCost()) < 0 {
 		return ErrInsufficientFunds
 	}
-	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil)
+	// The payer of a sponsored transaction should cover its gas along with the
+	// gas of the other transactions it sponsors, except the one this replaces
+	if tx.Sponsored() {
+		payer, err := types.Payer(pool.signer, tx)
+		if err != nil {
+			return ErrInvalidPayer
+		}
+		cost := pool.all.SponsoredCost(payer)
+		if old := pool.overlapping(from, tx.Nonce()); old != nil && old.Sponsor() != nil && *old.Sponsor() == payer {
+			cost.Sub(cost, gasCost(old))
+		}
+		if pool.currentState.GetBalance(payer).Cmp(cost.Add(cost, gasCost(tx))) < 0 {
+			return ErrInsufficientPayerFunds
+		}
+	}
+	var intrGas uint64
+	if calls != nil {
+		intrGas, err = BatchIntrinsicGas(calls)
+	} else {
+		intrGas, err = IntrinsicGas(tx.Data(), tx.To() == nil)
+	}
 	if err != nil {
 		return err
 	}
@@ -804,6 +916,10 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 			return fmt.Errorf("%s: %s", ErrPlatONTxDataInvalid.Error(), err.Error())
 		}
 	}
+	// Batches can't call inner contracts
+	if err := checkBatchCalls(calls); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -829,8 +945,9 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		invalidTxCounter.Inc(1)
 		return false, err
 	}
-	// If the transaction pool is full, discard underpriced transactions
-	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+	// If the transaction pool is full, discard underpriced transactions. Governance
+	// and slashing transactions are admitted regardless while their reserve lasts.
+	if pool.full(tx) {
 		// If the new transaction is underpriced, don't accept it
 		if !local && pool.priced.Underpriced(tx, pool.locals) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
@@ -838,7 +955,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 			return false, ErrUnderpriced
 		}
 		// New transaction is better than our worse ones, make room for it
-		drop := pool.priced.Discard(pool.all.Count()-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
+		drop := pool.priced.Discard(pool.all.Count()-int(pool.capacity()-1), pool.locals)
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
@@ -858,6 +975,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		if old != nil {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed()
+			pool.replaced.Add(old.Hash(), hash)
 			pendingReplaceCounter.Inc(1)
 		}
 		pool.all.Add(tx)
@@ -908,6 +1026,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed()
+		pool.replaced.Add(old.Hash(), hash)
 		queuedReplaceCounter.Inc(1)
 	}
 	if pool.all.Get(hash) == nil {
@@ -953,6 +1072,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed()
+		pool.replaced.Add(old.Hash(), hash)
 
 		pendingReplaceCounter.Inc(1)
 	}
@@ -1091,9 +1211,9 @@ func (pool *TxPool) recoverTx(tx *types.Transaction, from common.Address, local
 	}
 
 	// If the transaction pool is full, discard underpriced transactions
-	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+	if pool.full(tx) {
 		// New transaction is better than our worse ones, make room for it
-		drop := pool.priced.Discard(pool.all.Count()-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
+		drop := pool.priced.Discard(pool.all.Count()-int(pool.capacity()-1), pool.locals)
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
@@ -1168,6 +1288,34 @@ func (pool *TxPool) addTxExt(txExt *txExt) interface{} {
 	return nil
 }
 
+// remotes retrieves the transactions of non-local accounts in the order the
+// pool snapshot keeps them when truncated: pending ones before queued ones, the
+// accounts by the price of their lowest nonce transaction and each account's by
+// nonce. Cutting the list short only drops whole accounts or the highest nonces
+// of one account, never leaving a nonce gap.
+func (pool *TxPool) remotes() types.Transactions {
+	var txs types.Transactions
+	for _, lists := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		var accounts []types.Transactions
+		for addr, list := range lists {
+			if !pool.locals.contains(addr) && !list.Empty() {
+				accounts = append(accounts, list.Flatten())
+			}
+		}
+		sort.Slice(accounts, func(i, j int) bool {
+			if cmp := accounts[i][0].GasPrice().Cmp(accounts[j][0].GasPrice()); cmp != 0 {
+				return cmp > 0
+			}
+			hi, hj := accounts[i][0].Hash(), accounts[j][0].Hash()
+			return bytes.Compare(hi[:], hj[:]) < 0
+		})
+		for _, account := range accounts {
+			txs = append(txs, account...)
+		}
+	}
+	return txs
+}
+
 // addTxs attempts to queue a batch of transactions if they are valid.
 func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 	pool.mu.Lock()
@@ -1221,6 +1369,22 @@ func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 	return status
 }
 
+// Replacements returns the hashes of the transactions that successively replaced
+// the one identified by hash, oldest first. The last one is the transaction
+// currently standing for it in the pool.
+func (pool *TxPool) Replacements(hash common.Hash) []common.Hash {
+	var chain []common.Hash
+	for len(chain) < replacedCacheSize {
+		next, ok := pool.replaced.Get(hash)
+		if !ok {
+			break
+		}
+		hash = next.(common.Hash)
+		chain = append(chain, hash)
+	}
+	return chain
+}
+
 // Get returns a transaction if it is contained in the pool
 // and nil otherwise.
 func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
@@ -1338,7 +1502,8 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 	for _, list := range pool.pending {
 		pending += uint64(list.Len())
 	}
-	if pending > pool.config.GlobalSlots {
+	globalSlots := pool.config.GlobalSlots + pool.reserved()
+	if pending > globalSlots {
 		pendingBeforeCap := pending
 		// Assemble a spam order to penalize large transactors first
 		spammers := prque.New(nil)
@@ -1350,7 +1515,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		}
 		// Gradually drop transactions from offenders
 		offenders := []common.Address{}
-		for pending > pool.config.GlobalSlots && !spammers.Empty() {
+		for pending > globalSlots && !spammers.Empty() {
 			// Retrieve the next offender if not local address
 			offender, _ := spammers.Pop()
 			offenders = append(offenders, offender.(common.Address))
@@ -1361,7 +1526,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 				threshold := pool.pending[offender.(common.Address)].Len()
 
 				// Iteratively reduce all offenders until below limit or threshold reached
-				for pending > pool.config.GlobalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
+				for pending > globalSlots && pool.pending[offenders[len(offenders)-2]].Len() > threshold {
 					for i := 0; i < len(offenders)-1; i++ {
 						list := pool.pending[offenders[i]]
 						for _, tx := range list.Cap(list.Len() - 1) {
@@ -1382,8 +1547,8 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			}
 		}
 		// If still above threshold, reduce to limit or min allowance
-		if pending > pool.config.GlobalSlots && len(offenders) > 0 {
-			for pending > pool.config.GlobalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
+		if pending > globalSlots && len(offenders) > 0 {
+			for pending > globalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
 				for _, addr := range offenders {
 					list := pool.pending[addr]
 					for _, tx := range list.Cap(list.Len() - 1) {
@@ -1446,6 +1611,104 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 	}
 }
 
+// priorityFnCodes are the functions of the governance and slashing contracts
+// whose transactions get reserved pool slots and are packed first.
+var priorityFnCodes = map[common.Address]map[uint16]bool{
+	cvm.GovContractAddr:      {vm.Vote: true, vm.Declare: true},
+	cvm.SlashingContractAddr: {vm.TxReportDuplicateSign: true},
+}
+
+// overlapping returns the pending or queued transaction of an account with the
+// given nonce, if any.
+func (pool *TxPool) overlapping(addr common.Address, nonce uint64) *types.Transaction {
+	for _, lists := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		if list := lists[addr]; list != nil {
+			if tx := list.txs.Get(nonce); tx != nil {
+				return tx
+			}
+		}
+	}
+	return nil
+}
+
+// IsPriorityTx reports whether the transaction votes on a proposal, declares
+// the version of a node or reports a duplicate signature. The pool only treats
+// the first PrioritySlots of them as priority transactions.
+func IsPriorityTx(tx *types.Transaction) bool {
+	to := tx.To()
+	if to == nil {
+		return false
+	}
+	fnCodes, ok := priorityFnCodes[*to]
+	if !ok {
+		return false
+	}
+	args, _, err := rlp.SplitList(tx.Data())
+	if err != nil {
+		return false
+	}
+	code, _, err := rlp.SplitString(args)
+	if err != nil {
+		return false
+	}
+	var fnCode uint16
+	if err := rlp.DecodeBytes(code, &fnCode); err != nil {
+		return false
+	}
+	return fnCodes[fnCode]
+}
+
+// IsPriority reports whether the pool treats tx as a priority transaction.
+func (pool *TxPool) IsPriority(tx *types.Transaction) bool {
+	return pool.all.IsPriority(tx.Hash())
+}
+
+// reserved returns the number of pool slots taken by governance and slashing
+// transactions out of their reserve.
+func (pool *TxPool) reserved() uint64 {
+	if count := uint64(pool.all.PriorityCount()); count < pool.config.PrioritySlots {
+		return count
+	}
+	return pool.config.PrioritySlots
+}
+
+// capacity returns the total number of transactions the pool may hold.
+func (pool *TxPool) capacity() uint64 {
+	return pool.config.GlobalSlots + pool.config.GlobalQueue + pool.reserved()
+}
+
+// full reports whether the pool has to make room before accepting tx.
+func (pool *TxPool) full(tx *types.Transaction) bool {
+	if IsPriorityTx(tx) && uint64(pool.all.PriorityCount()) < pool.config.PrioritySlots {
+		return false
+	}
+	return uint64(pool.all.Count()) >= pool.capacity()
+}
+
+// prioritySenders retrieves the accounts that have priority transactions in
+// the pool.
+func (pool *TxPool) prioritySenders() *accountSet {
+	senders := newAccountSet(pool.signer)
+	pool.all.RangePriority(func(hash common.Hash, tx *types.Transaction) bool {
+		if from, err := types.Sender(pool.signer, tx); err == nil {
+			senders.add(from)
+		}
+		return true
+	})
+	return senders
+}
+
+// priorityPrefix returns the transactions of an account, sorted by nonce, up
+// to its last priority transaction.
+func (pool *TxPool) priorityPrefix(txs types.Transactions) types.Transactions {
+	for i := len(txs) - 1; i >= 0; i-- {
+		if pool.all.IsPriority(txs[i].Hash()) {
+			return txs[:i+1]
+		}
+	}
+	return nil
+}
+
 // demoteUnexecutables removes invalid and processed transactions from the pools
 // executable/pending queue and any subsequent transactions that become unexecutable
 // are moved back into the future queue.
@@ -1489,6 +1752,42 @@ func (pool *TxPool) demoteUnexecutables() {
 			delete(pool.beats, addr)
 		}
 	}
+	pool.dropUnpayable()
+}
+
+// dropUnpayable removes the sponsored transactions whose payer doesn't cover
+// the gas of every transaction it sponsors anymore, the check validateTx runs
+// on admission. Queued transactions go before pending ones and the latest
+// nonces of a sender before the earlier ones, until the payer covers the rest.
+func (pool *TxPool) dropUnpayable() {
+	for _, payer := range pool.all.Payers() {
+		excess := pool.all.SponsoredCost(payer)
+		if excess.Sub(excess, pool.currentState.GetBalance(payer)).Sign() <= 0 {
+			continue
+		}
+		excess = pool.dropSponsored(pool.queue, payer, excess, queuedNofundsCounter)
+		pool.dropSponsored(pool.pending, payer, excess, pendingNofundsCounter)
+	}
+}
+
+// dropSponsored removes the transactions of the lists sponsored by payer, the
+// latest nonces first, until their gas covers excess. It returns the part of
+// excess left to cover.
+func (pool *TxPool) dropSponsored(lists map[common.Address]*txList, payer common.Address, excess *big.Int, counter metrics.Counter) *big.Int {
+	for _, list := range lists {
+		txs := list.Flatten()
+		for i := len(txs) - 1; i >= 0 && excess.Sign() > 0; i-- {
+			tx := txs[i]
+			if tx.Sponsor() == nil || *tx.Sponsor() != payer {
+				continue
+			}
+			log.Trace("Removed unpayable sponsored transaction", "hash", tx.Hash(), "payer", payer)
+			excess.Sub(excess, gasCost(tx))
+			pool.removeTx(tx.Hash(), true)
+			counter.Inc(1)
+		}
+	}
+	return excess
 }
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
@@ -1564,14 +1863,21 @@ func (as *accountSet) flatten() []common.Address {
 // peeking into the pool in TxPool.Get without having to acquire the widely scoped
 // TxPool.mu mutex.
 type txLookup struct {
-	all  map[common.Hash]*types.Transaction
-	lock sync.RWMutex
+	all           map[common.Hash]*types.Transaction
+	priority      map[common.Hash]*types.Transaction // governance and slashing transactions
+	prioritySlots int                                // maximum number of priority transactions
+	sponsored     map[common.Address]*big.Int        // gas cost of the sponsored transactions by payer
+	lock          sync.RWMutex
 }
 
-// newTxLookup returns a new txLookup structure.
-func newTxLookup() *txLookup {
+// newTxLookup returns a new txLookup structure tracking at most prioritySlots
+// priority transactions.
+func newTxLookup(prioritySlots uint64) *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:           make(map[common.Hash]*types.Transaction),
+		priority:      make(map[common.Hash]*types.Transaction),
+		prioritySlots: int(prioritySlots),
+		sponsored:     make(map[common.Address]*big.Int),
 	}
 }
 
@@ -1587,6 +1893,18 @@ func (t *txLookup) Range(f func(hash common.Hash, tx *types.Transaction) bool) {
 	}
 }
 
+// RangePriority calls f on each governance and slashing transaction in the map.
+func (t *txLookup) RangePriority(f func(hash common.Hash, tx *types.Transaction) bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for key, value := range t.priority {
+		if !f(key, value) {
+			break
+		}
+	}
+}
+
 // Get returns a transaction if it exists in the lookup, or nil if not found.
 func (t *txLookup) Get(hash common.Hash) *types.Transaction {
 	t.lock.RLock()
@@ -1603,12 +1921,66 @@ func (t *txLookup) Count() int {
 	return len(t.all)
 }
 
+// IsPriority reports whether the transaction is tracked as a priority one.
+func (t *txLookup) IsPriority(hash common.Hash) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	_, ok := t.priority[hash]
+	return ok
+}
+
+// PriorityCount returns the current number of governance and slashing
+// transactions in the lookup.
+func (t *txLookup) PriorityCount() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.priority)
+}
+
+// Payers returns the accounts paying for the gas of transactions in the lookup.
+func (t *txLookup) Payers() []common.Address {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	payers := make([]common.Address, 0, len(t.sponsored))
+	for payer := range t.sponsored {
+		payers = append(payers, payer)
+	}
+	return payers
+}
+
+// SponsoredCost returns the gas cost of the transactions in the lookup that the
+// given account pays for.
+func (t *txLookup) SponsoredCost(payer common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if cost := t.sponsored[payer]; cost != nil {
+		return new(big.Int).Set(cost)
+	}
+	return new(big.Int)
+}
+
 // Add adds a transaction to the lookup.
 func (t *txLookup) Add(tx *types.Transaction) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	if _, ok := t.all[tx.Hash()]; ok {
+		return
+	}
 	t.all[tx.Hash()] = tx
+	if len(t.priority) < t.prioritySlots && IsPriorityTx(tx) {
+		t.priority[tx.Hash()] = tx
+	}
+	if payer := tx.Sponsor(); payer != nil {
+		if t.sponsored[*payer] == nil {
+			t.sponsored[*payer] = new(big.Int)
+		}
+		t.sponsored[*payer].Add(t.sponsored[*payer], gasCost(tx))
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1616,5 +1988,18 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	if tx := t.all[hash]; tx != nil {
+		if payer := tx.Sponsor(); payer != nil {
+			if cost := t.sponsored[*payer].Sub(t.sponsored[*payer], gasCost(tx)); cost.Sign() <= 0 {
+				delete(t.sponsored, *payer)
+			}
+		}
+	}
 	delete(t.all, hash)
+	delete(t.priority, hash)
+}
+
+// gasCost returns the most the gas of a transaction can cost.
+func gasCost(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
 }
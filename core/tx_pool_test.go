@@ -23,11 +23,16 @@ import (
 	"testing"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	cvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/consensus"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 )
 
 // testTxPoolConfig is a transaction pool configuration without stateful disk
@@ -72,6 +77,81 @@ func pricedTransaction(nonce uint64, gaslimit uint64, gasprice *big.Int, key *ec
 	return tx
 }
 
+// newTestTxPool creates a transaction pool on a fresh chain whose genesis funds
+// the given accounts.
+func newTestTxPool(chainConfig *params.ChainConfig, config TxPoolConfig, funded ...common.Address) *TxPool {
+	var (
+		db    = ethdb.NewMemDatabase()
+		gspec = Genesis{Config: chainConfig, Alloc: GenesisAlloc{}}
+	)
+	for _, addr := range funded {
+		gspec.Alloc[addr] = GenesisAccount{Balance: big.NewInt(params.LAT)}
+	}
+	genesis := gspec.MustCommit(db)
+
+	engine := new(consensus.BftMock)
+	engine.InsertChain(genesis)
+	chain, _ := NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil)
+	chainCache := NewBlockChainCache(chain)
+
+	statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+	chainCache.WriteStateDB(genesis.Header().SealHash(), statedb, 0)
+
+	return NewTxPool(config, gspec.Config, chainCache)
+}
+
+// Tests that only the transactions of an account up to its last priority one
+// are packed ahead of the others, and that they count towards the package
+// limit.
+func TestPendingLimitedPriority(t *testing.T) {
+	var (
+		signer         = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		plainKey, _    = crypto.GenerateKey()
+		senderKey, _   = crypto.GenerateKey()
+		reporterKey, _ = crypto.GenerateKey()
+	)
+	config := testTxPoolConfig
+	config.GlobalTxCount = 2
+	config.PrioritySlots = 1
+	pool := newTestTxPool(params.TestChainConfig, config, crypto.PubkeyToAddress(plainKey.PublicKey), crypto.PubkeyToAddress(senderKey.PublicKey), crypto.PubkeyToAddress(reporterKey.PublicKey))
+	defer pool.Stop()
+
+	report := func(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+		code, _ := rlp.EncodeToBytes(uint16(vm.TxReportDuplicateSign))
+		dupType, _ := rlp.EncodeToBytes(uint8(1))
+		evidence, _ := rlp.EncodeToBytes("{}")
+		data, _ := rlp.EncodeToBytes([][]byte{code, dupType, evidence})
+		tx, _ := types.SignTx(types.NewTransaction(nonce, cvm.SlashingContractAddr, big.NewInt(0), 100000, big.NewInt(1), data), signer, key)
+		return tx
+	}
+	plain := func(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), signer, key)
+		return tx
+	}
+	var (
+		first    = plain(0, senderKey)
+		reported = report(1, senderKey)
+		last     = plain(2, senderKey)
+		second   = report(0, reporterKey)
+	)
+	for _, tx := range []*types.Transaction{plain(0, plainKey), plain(1, plainKey), first, reported, last, second} {
+		if err := pool.addTx(tx, false); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if !pool.IsPriority(reported) || pool.IsPriority(second) || pool.IsPriority(first) {
+		t.Fatalf("priority mismatch: have %v %v %v, want true false false", pool.IsPriority(reported), pool.IsPriority(second), pool.IsPriority(first))
+	}
+	pending, _ := pool.PendingLimited()
+	if len(pending) != 1 {
+		t.Fatalf("pending account count mismatch: have %d, want 1", len(pending))
+	}
+	txs := pending[crypto.PubkeyToAddress(senderKey.PublicKey)]
+	if len(txs) != 2 || txs[0] != first || txs[1] != reported {
+		t.Errorf("pending transactions mismatch: have %v, want %x and %x", txs, first.Hash(), reported.Hash())
+	}
+}
+
 // TODO test
 /*func setupTxPool() (*TxPool, *ecdsa.PrivateKey) {
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()), big.NewInt(0), common.Hash{})
@@ -83,6 +163,162 @@ func pricedTransaction(nonce uint64, gaslimit uint64, gasprice *big.Int, key *ec
 	return pool, key
 }*/
 
+// Tests that the pool records which transactions replaced a pending or a queued
+// one, and refuses replacements that don't bump the gas price enough.
+func TestTransactionReplacements(t *testing.T) {
+	var (
+		signer = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		key, _ = crypto.GenerateKey()
+	)
+	pool := newTestTxPool(params.TestChainConfig, testTxPoolConfig, crypto.PubkeyToAddress(key.PublicKey))
+	defer pool.Stop()
+
+	priced := func(nonce uint64, gasPrice int64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100000, big.NewInt(gasPrice), nil), signer, key)
+		return tx
+	}
+	// Pending transactions, then queued ones behind a nonce gap
+	for _, nonce := range []uint64{0, 5} {
+		var (
+			original = priced(nonce, 100)
+			first    = priced(nonce, 110)
+			second   = priced(nonce, 121)
+		)
+		if err := pool.addTx(original, false); err != nil {
+			t.Fatalf("nonce %d: failed to add original transaction: %v", nonce, err)
+		}
+		if err := pool.addTx(priced(nonce, 109), false); err != ErrReplaceUnderpriced {
+			t.Fatalf("nonce %d: underpriced replacement error mismatch: have %v, want %v", nonce, err, ErrReplaceUnderpriced)
+		}
+		if chain := pool.Replacements(original.Hash()); len(chain) != 0 {
+			t.Fatalf("nonce %d: replacements recorded for a refused replacement: %v", nonce, chain)
+		}
+		for _, tx := range []*types.Transaction{first, second} {
+			if err := pool.addTx(tx, false); err != nil {
+				t.Fatalf("nonce %d: failed to replace transaction: %v", nonce, err)
+			}
+		}
+		if chain := pool.Replacements(original.Hash()); len(chain) != 2 || chain[0] != first.Hash() || chain[1] != second.Hash() {
+			t.Errorf("nonce %d: replacements mismatch: have %v, want [%x %x]", nonce, chain, first.Hash(), second.Hash())
+		}
+		if chain := pool.Replacements(first.Hash()); len(chain) != 1 || chain[0] != second.Hash() {
+			t.Errorf("nonce %d: replacements of the first replacement mismatch: have %v, want [%x]", nonce, chain, second.Hash())
+		}
+		if pool.Get(original.Hash()) != nil || pool.Get(first.Hash()) != nil || pool.Get(second.Hash()) == nil {
+			t.Errorf("nonce %d: only the last replacement should be pooled", nonce)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the payer of sponsored transactions has to cover the gas of all of
+// them, less the gas of the ones they replace.
+func TestSponsoredTransactionPayerFunds(t *testing.T) {
+	chainConfig := *params.TestChainConfig
+	chainConfig.SponsorBlock = big.NewInt(0)
+
+	var (
+		signer      = types.NewSponsorSigner(chainConfig.ChainID)
+		key, _      = crypto.GenerateKey()
+		otherKey, _ = crypto.GenerateKey()
+		payerKey, _ = crypto.GenerateKey()
+		payer       = crypto.PubkeyToAddress(payerKey.PublicKey)
+	)
+	pool := newTestTxPool(&chainConfig, testTxPoolConfig, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(otherKey.PublicKey))
+	defer pool.Stop()
+
+	// The payer covers the gas of three transactions priced at 1
+	pool.mu.Lock()
+	pool.currentState.SetBalance(payer, big.NewInt(3*21000))
+	pool.mu.Unlock()
+
+	sponsored := func(nonce uint64, gasPrice int64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewSponsoredTransaction(types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(gasPrice), nil), payer), signer, key)
+		tx, _ = types.SignPayer(tx, signer, payerKey)
+		return tx
+	}
+	for _, tx := range []*types.Transaction{sponsored(0, 1, key), sponsored(1, 1, key), sponsored(0, 1, otherKey)} {
+		if err := pool.addTx(tx, false); err != nil {
+			t.Fatalf("failed to add sponsored transaction: %v", err)
+		}
+	}
+	if cost := pool.all.SponsoredCost(payer); cost.Cmp(big.NewInt(3*21000)) != 0 {
+		t.Fatalf("sponsored cost mismatch: have %v, want %v", cost, 3*21000)
+	}
+	if err := pool.addTx(sponsored(2, 1, key), false); err != ErrInsufficientPayerFunds {
+		t.Fatalf("transaction beyond the payer funds error mismatch: have %v, want %v", err, ErrInsufficientPayerFunds)
+	}
+	// A replacement only has to cover the difference, which doesn't fit either
+	if err := pool.addTx(sponsored(1, 2, key), false); err != ErrInsufficientPayerFunds {
+		t.Fatalf("replacement beyond the payer funds error mismatch: have %v, want %v", err, ErrInsufficientPayerFunds)
+	}
+	// Funds are given back once sponsored transactions leave the pool
+	pool.mu.Lock()
+	pool.removeTx(pool.pending[crypto.PubkeyToAddress(otherKey.PublicKey)].Flatten()[0].Hash(), true)
+	pool.mu.Unlock()
+
+	if err := pool.addTx(sponsored(1, 2, key), false); err != nil {
+		t.Fatalf("failed to replace sponsored transaction: %v", err)
+	}
+	if cost := pool.all.SponsoredCost(payer); cost.Cmp(big.NewInt(3*21000)) != 0 {
+		t.Fatalf("sponsored cost mismatch after replacement: have %v, want %v", cost, 3*21000)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that sponsored transactions are dropped once their payer doesn't cover
+// their gas anymore, queued ones and later nonces first.
+func TestSponsoredTransactionPayerDrop(t *testing.T) {
+	chainConfig := *params.TestChainConfig
+	chainConfig.SponsorBlock = big.NewInt(0)
+
+	var (
+		signer      = types.NewSponsorSigner(chainConfig.ChainID)
+		key, _      = crypto.GenerateKey()
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+		payerKey, _ = crypto.GenerateKey()
+		payer       = crypto.PubkeyToAddress(payerKey.PublicKey)
+	)
+	pool := newTestTxPool(&chainConfig, testTxPoolConfig, from)
+	defer pool.Stop()
+
+	pool.mu.Lock()
+	pool.currentState.SetBalance(payer, big.NewInt(3*21000))
+	pool.mu.Unlock()
+
+	var txs []*types.Transaction
+	for _, nonce := range []uint64{0, 1, 3} {
+		tx, _ := types.SignTx(types.NewSponsoredTransaction(types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil), payer), signer, key)
+		tx, _ = types.SignPayer(tx, signer, payerKey)
+		if err := pool.addTx(tx, false); err != nil {
+			t.Fatalf("failed to add sponsored transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	// The payer spends funds elsewhere, covering a single transaction
+	pool.mu.Lock()
+	pool.currentState.SetBalance(payer, big.NewInt(21000))
+	pool.demoteUnexecutables()
+	pool.mu.Unlock()
+
+	if pool.Get(txs[0].Hash()) == nil || pool.Get(txs[1].Hash()) != nil || pool.Get(txs[2].Hash()) != nil {
+		t.Errorf("only the first sponsored transaction should be pooled")
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Errorf("pool size mismatch: have %d pending and %d queued, want 1 and 0", pending, queued)
+	}
+	if cost := pool.all.SponsoredCost(payer); cost.Cmp(big.NewInt(21000)) != 0 {
+		t.Errorf("sponsored cost mismatch: have %v, want %v", cost, 21000)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // validateTxPoolInternals checks various consistency invariants within the pool.
 func validateTxPoolInternals(pool *TxPool) error {
 	pool.mu.RLock()
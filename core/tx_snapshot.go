@@ -0,0 +1,119 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"io"
+	"os"
+
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+// txSnapshot is a dump of the remote transactions of the pool, taken on shutdown
+// and periodically so that they survive node restarts as well as local ones do
+// through the journal.
+type txSnapshot struct {
+	path  string // Filesystem path to store the transactions at
+	limit int    // Maximum number of transactions to store
+}
+
+// newTxSnapshot creates a new pool snapshot storing up to limit transactions.
+func newTxSnapshot(path string, limit uint64) *txSnapshot {
+	return &txSnapshot{
+		path:  path,
+		limit: int(limit),
+	}
+}
+
+// load parses a pool snapshot from disk, re-adding its contents to the pool
+// through add, which validates them again. It returns the number of transactions
+// restored and dropped.
+func (snap *txSnapshot) load(add func([]*types.Transaction) []error) (int, int, error) {
+	// Skip the parsing if the snapshot file doesn't exist at all
+	if _, err := os.Stat(snap.path); os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	input, err := os.Open(snap.path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	restored, dropped := 0, 0
+
+	loadBatch := func(txs types.Transactions) {
+		for _, err := range add(txs) {
+			if err != nil {
+				log.Debug("Failed to add snapshot transaction", "err", err)
+				dropped++
+			} else {
+				restored++
+			}
+		}
+	}
+	var (
+		failure error
+		batch   types.Transactions
+	)
+	for {
+		tx := new(types.Transaction)
+		if err = stream.Decode(tx); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			if batch.Len() > 0 {
+				loadBatch(batch)
+			}
+			break
+		}
+		if batch = append(batch, tx); batch.Len() > 1024 {
+			loadBatch(batch)
+			batch = batch[:0]
+		}
+	}
+	log.Info("Loaded transaction pool snapshot", "restored", restored, "dropped", dropped)
+
+	return restored, dropped, failure
+}
+
+// save replaces the snapshot on disk with the given transactions, truncated to
+// the snapshot limit.
+func (snap *txSnapshot) save(txs types.Transactions) error {
+	if len(txs) > snap.limit {
+		txs = txs[:snap.limit]
+	}
+	output, err := os.OpenFile(snap.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err = rlp.Encode(output, tx); err != nil {
+			output.Close()
+			return err
+		}
+	}
+	output.Close()
+
+	if err = os.Rename(snap.path+".new", snap.path); err != nil {
+		return err
+	}
+	log.Info("Saved transaction pool snapshot", "transactions", len(txs))
+	return nil
+}
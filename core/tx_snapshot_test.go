@@ -0,0 +1,127 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+// Tests that a pool snapshot is truncated to its limit when saved and that its
+// transactions are handed back in order on load.
+func TestTxSnapshotSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txsnapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, _ := crypto.GenerateKey()
+	txs := make(types.Transactions, 5)
+	for i := range txs {
+		txs[i] = transaction(uint64(i), 100000, key)
+	}
+	snap := newTxSnapshot(filepath.Join(dir, "snapshot.rlp"), 4)
+	if err := snap.save(txs); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+	var loaded types.Transactions
+	restored, dropped, err := snap.load(func(batch []*types.Transaction) []error {
+		loaded = append(loaded, batch...)
+		errs := make([]error, len(batch))
+		errs[0] = ErrNonceTooLow
+		return errs
+	})
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if restored != 3 || dropped != 1 {
+		t.Errorf("counts mismatch: have %d restored and %d dropped, want 3 and 1", restored, dropped)
+	}
+	if len(loaded) != 4 {
+		t.Fatalf("loaded transactions mismatch: have %d, want 4", len(loaded))
+	}
+	for i, tx := range loaded {
+		if tx.Hash() != txs[i].Hash() {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, tx.Hash(), txs[i].Hash())
+		}
+	}
+}
+
+// Tests that the remote transactions are snapshotted pending first, by account
+// price and nonce, so that truncation only drops the highest nonces.
+func TestTxPoolRemotesOrder(t *testing.T) {
+	var (
+		signer    = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		cheap, _  = crypto.GenerateKey()
+		dear, _   = crypto.GenerateKey()
+		queued, _ = crypto.GenerateKey()
+		txs       = make(map[*ecdsa.PrivateKey]types.Transactions)
+		addrs     []common.Address
+		pricing   = map[*ecdsa.PrivateKey]int64{cheap: 1, dear: 2, queued: 3}
+	)
+	for _, key := range []*ecdsa.PrivateKey{cheap, dear, queued} {
+		addrs = append(addrs, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	pool := newTestTxPool(params.TestChainConfig, testTxPoolConfig, addrs...)
+	defer pool.Stop()
+
+	add := func(key *ecdsa.PrivateKey, nonce uint64) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100000, big.NewInt(pricing[key]), nil), signer, key)
+		if err := pool.addTx(tx, false); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+		txs[key] = append(txs[key], tx)
+	}
+	add(queued, 5)
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		add(cheap, nonce)
+	}
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		add(dear, nonce)
+	}
+	want := append(append(append(types.Transactions{}, txs[dear]...), txs[cheap]...), txs[queued]...)
+
+	pool.mu.Lock()
+	remotes := pool.remotes()
+	pool.mu.Unlock()
+	if len(remotes) != len(want) {
+		t.Fatalf("remote transactions mismatch: have %d, want %d", len(remotes), len(want))
+	}
+	for i, tx := range remotes {
+		if tx.Hash() != want[i].Hash() {
+			t.Errorf("remote transaction %d mismatch: have %x, want %x", i, tx.Hash(), want[i].Hash())
+		}
+	}
+	// Truncation keeps the lowest nonces of the cheaper account
+	dir, err := ioutil.TempDir("", "txsnapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	snap := newTxSnapshot(filepath.Join(dir, "snapshot.rlp"), 4)
+	if err := snap.save(remotes); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+	var loaded types.Transactions
+	snap.load(func(batch []*types.Transaction) []error {
+		loaded = append(loaded, batch...)
+		return make([]error, len(batch))
+	})
+	if len(loaded) != 4 {
+		t.Fatalf("loaded transactions mismatch: have %d, want 4", len(loaded))
+	}
+	for i, tx := range loaded {
+		if tx.Hash() != want[i].Hash() {
+			t.Errorf("loaded transaction %d mismatch: have %x, want %x", i, tx.Hash(), want[i].Hash())
+		}
+	}
+}
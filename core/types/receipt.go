@@ -28,6 +28,7 @@ import (
 )
 
 //go:generate gencodec -type Receipt -field-override receiptMarshaling -out gen_receipt_json.go
+//go:generate gencodec -type CallReceipt -field-override callReceiptMarshaling -out gen_call_receipt_json.go
 
 var (
 	receiptStatusFailedRLP     = []byte{}
@@ -55,6 +56,24 @@ type Receipt struct {
 	TxHash          common.Hash    `json:"transactionHash" gencodec:"required"`
 	ContractAddress common.Address `json:"contractAddress"`
 	GasUsed         uint64         `json:"gasUsed" gencodec:"required"`
+	RevertData      []byte         `json:"revertData,omitempty"`
+	Calls           []*CallReceipt `json:"calls,omitempty"`
+}
+
+// CallReceipt represents the results of one of the calls of a batch transaction.
+// Its logs are part of the logs of the transaction receipt. They are dropped
+// with the rest of the state changes if any call of the batch fails.
+type CallReceipt struct {
+	Status     uint64 `json:"status"`
+	GasUsed    uint64 `json:"gasUsed"    gencodec:"required"`
+	Logs       []*Log `json:"logs"       gencodec:"required"`
+	RevertData []byte `json:"revertData,omitempty"`
+}
+
+type callReceiptMarshaling struct {
+	Status     hexutil.Uint64
+	GasUsed    hexutil.Uint64
+	RevertData hexutil.Bytes
 }
 
 type receiptMarshaling struct {
@@ -62,6 +81,7 @@ type receiptMarshaling struct {
 	Status            hexutil.Uint64
 	CumulativeGasUsed hexutil.Uint64
 	GasUsed           hexutil.Uint64
+	RevertData        hexutil.Bytes
 }
 
 // receiptRLP is the consensus encoding of a receipt.
@@ -80,6 +100,29 @@ type receiptStorageRLP struct {
 	ContractAddress   common.Address
 	Logs              []*LogForStorage
 	GasUsed           uint64
+	RevertData        []byte
+	Calls             []callReceiptStorageRLP `rlp:"tail"`
+}
+
+// callReceiptStorageRLP is the storage encoding of a call receipt. Its logs are
+// stored with the transaction receipt, so only their number is kept.
+type callReceiptStorageRLP struct {
+	Status     uint64
+	GasUsed    uint64
+	Logs       uint64
+	RevertData []byte
+}
+
+// legacyReceiptStorageRLP is the storage encoding of receipts written
+// before the revert data was recorded.
+type legacyReceiptStorageRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             Bloom
+	TxHash            common.Hash
+	ContractAddress   common.Address
+	Logs              []*LogForStorage
+	GasUsed           uint64
 }
 
 // NewReceipt creates a barebone transaction receipt, copying the init fields.
@@ -164,20 +207,45 @@ func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
 		ContractAddress:   r.ContractAddress,
 		Logs:              make([]*LogForStorage, len(r.Logs)),
 		GasUsed:           r.GasUsed,
+		RevertData:        r.RevertData,
 	}
 	for i, log := range r.Logs {
 		enc.Logs[i] = (*LogForStorage)(log)
 	}
+	for _, call := range r.Calls {
+		enc.Calls = append(enc.Calls, callReceiptStorageRLP{
+			Status:     call.Status,
+			GasUsed:    call.GasUsed,
+			Logs:       uint64(len(call.Logs)),
+			RevertData: call.RevertData,
+		})
+	}
 	return rlp.Encode(w, enc)
 }
 
 // DecodeRLP implements rlp.Decoder, and loads both consensus and implementation
 // fields of a receipt from an RLP stream.
 func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
-	var dec receiptStorageRLP
-	if err := s.Decode(&dec); err != nil {
+	blob, err := s.Raw()
+	if err != nil {
 		return err
 	}
+	var dec receiptStorageRLP
+	if err := rlp.DecodeBytes(blob, &dec); err != nil {
+		var legacy legacyReceiptStorageRLP
+		if rlp.DecodeBytes(blob, &legacy) != nil {
+			return err
+		}
+		dec = receiptStorageRLP{
+			PostStateOrStatus: legacy.PostStateOrStatus,
+			CumulativeGasUsed: legacy.CumulativeGasUsed,
+			Bloom:             legacy.Bloom,
+			TxHash:            legacy.TxHash,
+			ContractAddress:   legacy.ContractAddress,
+			Logs:              legacy.Logs,
+			GasUsed:           legacy.GasUsed,
+		}
+	}
 	if err := (*Receipt)(r).setStatus(dec.PostStateOrStatus); err != nil {
 		return err
 	}
@@ -189,6 +257,23 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	}
 	// Assign the implementation fields
 	r.TxHash, r.ContractAddress, r.GasUsed = dec.TxHash, dec.ContractAddress, dec.GasUsed
+	r.RevertData = dec.RevertData
+
+	// Hand the logs of the transaction out to the calls of a batch
+	r.Calls = nil
+	logs := r.Logs
+	for _, call := range dec.Calls {
+		if call.Logs > uint64(len(logs)) {
+			return fmt.Errorf("call receipt logs %d exceed the remaining %d", call.Logs, len(logs))
+		}
+		r.Calls = append(r.Calls, &CallReceipt{
+			Status:     call.Status,
+			GasUsed:    call.GasUsed,
+			Logs:       logs[:call.Logs:call.Logs],
+			RevertData: call.RevertData,
+		})
+		logs = logs[call.Logs:]
+	}
 	return nil
 }
 
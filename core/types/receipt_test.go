@@ -0,0 +1,112 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+func TestReceiptStorageRevertData(t *testing.T) {
+	receipt := &Receipt{
+		Status:            ReceiptStatusFailed,
+		CumulativeGasUsed: 1,
+		Logs:              []*Log{},
+		TxHash:            common.BytesToHash([]byte{0x11, 0x11}),
+		GasUsed:           111111,
+		RevertData:        []byte("insufficient allowance"),
+	}
+	enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+	if err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	var dec ReceiptForStorage
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode receipt: %v", err)
+	}
+	if !bytes.Equal(dec.RevertData, receipt.RevertData) {
+		t.Errorf("revert data mismatch, want %x, have %x", receipt.RevertData, dec.RevertData)
+	}
+	if dec.GasUsed != receipt.GasUsed || dec.TxHash != receipt.TxHash {
+		t.Errorf("implementation fields mismatch")
+	}
+}
+
+func TestReceiptStorageLegacyDecode(t *testing.T) {
+	legacy := &legacyReceiptStorageRLP{
+		PostStateOrStatus: receiptStatusSuccessfulRLP,
+		CumulativeGasUsed: 1,
+		TxHash:            common.BytesToHash([]byte{0x22, 0x22}),
+		Logs:              []*LogForStorage{},
+		GasUsed:           222222,
+	}
+	enc, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	var dec ReceiptForStorage
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode legacy receipt: %v", err)
+	}
+	if dec.Status != ReceiptStatusSuccessful || dec.GasUsed != legacy.GasUsed || dec.TxHash != legacy.TxHash {
+		t.Errorf("legacy receipt decoded incorrectly: %+v", dec)
+	}
+	if len(dec.RevertData) != 0 {
+		t.Errorf("unexpected revert data %x", dec.RevertData)
+	}
+}
+
+func TestReceiptStorageCalls(t *testing.T) {
+	logs := []*Log{
+		{Address: common.BytesToAddress([]byte{0x11}), Data: []byte{0x01}},
+		{Address: common.BytesToAddress([]byte{0x22}), Data: []byte{0x02}},
+		{Address: common.BytesToAddress([]byte{0x22}), Data: []byte{0x03}},
+	}
+	receipt := &Receipt{
+		Status:            ReceiptStatusSuccessful,
+		CumulativeGasUsed: 1,
+		Logs:              logs,
+		TxHash:            common.BytesToHash([]byte{0x33, 0x33}),
+		GasUsed:           333333,
+		Calls: []*CallReceipt{
+			{Status: ReceiptStatusSuccessful, GasUsed: 100, Logs: logs[:1]},
+			{Status: ReceiptStatusSuccessful, GasUsed: 200, Logs: []*Log{}},
+			{Status: ReceiptStatusSuccessful, GasUsed: 300, Logs: logs[1:]},
+		},
+	}
+	enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+	if err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	var dec ReceiptForStorage
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode receipt: %v", err)
+	}
+	if len(dec.Calls) != len(receipt.Calls) {
+		t.Fatalf("call receipts mismatch, want %d, have %d", len(receipt.Calls), len(dec.Calls))
+	}
+	for i, call := range dec.Calls {
+		want := receipt.Calls[i]
+		if call.Status != want.Status || call.GasUsed != want.GasUsed || len(call.Logs) != len(want.Logs) {
+			t.Errorf("call %d mismatch, want %+v, have %+v", i, want, call)
+			continue
+		}
+		for j, log := range call.Logs {
+			if !bytes.Equal(log.Data, want.Logs[j].Data) {
+				t.Errorf("call %d log %d mismatch, want %x, have %x", i, j, want.Logs[j].Data, log.Data)
+			}
+		}
+	}
+	// Receipts of ordinary transactions keep decoding without calls
+	receipt.Calls = nil
+	if enc, err = rlp.EncodeToBytes((*ReceiptForStorage)(receipt)); err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode receipt: %v", err)
+	}
+	if dec.Calls != nil {
+		t.Errorf("unexpected call receipts %v", dec.Calls)
+	}
+}
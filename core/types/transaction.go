@@ -23,6 +23,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"container/heap"
+	"encoding/json"
 	"errors"
 	"io"
 	"math/big"
@@ -30,17 +31,30 @@ import (
 )
 
 //go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
+//go:generate gencodec -type BatchCall -field-override batchCallMarshaling -out gen_batch_call_json.go
 
 var (
-	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+	ErrInvalidSig         = errors.New("invalid transaction v, r, s values")
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	ErrInvalidBatch       = errors.New("invalid batch transaction")
+)
+
+// The type bytes prefixing the encoding of typed transactions.
+const (
+	LegacyTxType    = 0x00
+	SponsoredTxType = 0x01
+	BatchTxType     = 0x02
 )
 
 type Transaction struct {
-	data txdata
+	data    txdata
+	sponsor *sponsordata // Gas payer of sponsored transactions, nil for ordinary ones
+	calls   []BatchCall  // Calls of batch transactions, nil for ordinary ones
 	// caches
-	hash atomic.Value
-	size atomic.Value
-	from atomic.Value
+	hash  atomic.Value
+	size  atomic.Value
+	from  atomic.Value
+	payer atomic.Value
 }
 
 type txdata struct {
@@ -60,6 +74,51 @@ type txdata struct {
 	Hash *common.Hash `json:"hash" rlp:"-"`
 }
 
+// sponsordata is the signature of the account paying for the gas of a sponsored
+// transaction, made over the transaction signed by its sender.
+type sponsordata struct {
+	Payer common.Address
+	V     *big.Int
+	R     *big.Int
+	S     *big.Int
+}
+
+// sponsoredtx is the encoding of a sponsored transaction, following its type byte.
+type sponsoredtx struct {
+	Tx      txdata
+	Sponsor sponsordata
+}
+
+// sponsorMarshaling is the JSON encoding of sponsordata, merged into the fields
+// of the transaction.
+type sponsorMarshaling struct {
+	Payer *common.Address `json:"payer"`
+	V     *hexutil.Big    `json:"payerV"`
+	R     *hexutil.Big    `json:"payerR"`
+	S     *hexutil.Big    `json:"payerS"`
+}
+
+// BatchCall is one of the calls of a batch transaction. The calls of a batch
+// are executed in order and either all of them succeed or none does.
+type BatchCall struct {
+	To    common.Address `json:"to"    gencodec:"required"`
+	Value *big.Int       `json:"value" gencodec:"required"`
+	Data  []byte         `json:"input" gencodec:"required"`
+}
+
+type batchCallMarshaling struct {
+	Value *hexutil.Big
+	Data  hexutil.Bytes
+}
+
+// batchtx is the encoding of a batch transaction, following its type byte. The
+// recipient and payload of Tx are empty and its amount is the total value of
+// the calls.
+type batchtx struct {
+	Tx    txdata
+	Calls []BatchCall
+}
+
 type txdataMarshaling struct {
 	AccountNonce hexutil.Uint64
 	Price        *hexutil.Big
@@ -104,25 +163,134 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	return &Transaction{data: d}
 }
 
+// NewSponsoredTransaction returns a copy of the unsigned transaction tx whose gas
+// is paid by payer, tx being an ordinary transaction. It has to be signed by its
+// sender with a SponsorSigner first, then by the payer with SignPayer.
+func NewSponsoredTransaction(tx *Transaction, payer common.Address) *Transaction {
+	return &Transaction{
+		data: tx.data,
+		sponsor: &sponsordata{
+			Payer: payer,
+			V:     new(big.Int),
+			R:     new(big.Int),
+			S:     new(big.Int),
+		},
+	}
+}
+
+// NewBatchTransaction returns an unsigned transaction executing the calls in
+// order, all or none of them, with a single nonce and signature. It has to be
+// signed with a BatchSigner.
+func NewBatchTransaction(nonce uint64, calls []BatchCall, gasLimit uint64, gasPrice *big.Int) *Transaction {
+	tx := newTransaction(nonce, nil, nil, gasLimit, gasPrice, nil)
+	tx.calls = make([]BatchCall, len(calls))
+	for i, call := range calls {
+		tx.calls[i] = BatchCall{To: call.To, Value: new(big.Int), Data: common.CopyBytes(call.Data)}
+		if call.Value != nil {
+			tx.calls[i].Value.Set(call.Value)
+		}
+		tx.data.Amount.Add(tx.data.Amount, tx.calls[i].Value)
+	}
+	return tx
+}
+
+// validateBatch checks that the envelope of a decoded batch transaction matches
+// its calls.
+func validateBatch(data *txdata, calls []BatchCall) error {
+	if len(calls) == 0 || data.Recipient != nil || len(data.Payload) != 0 {
+		return ErrInvalidBatch
+	}
+	total := new(big.Int)
+	for _, call := range calls {
+		if call.Value == nil {
+			return ErrInvalidBatch
+		}
+		total.Add(total, call.Value)
+	}
+	if data.Amount == nil || data.Amount.Cmp(total) != 0 {
+		return ErrInvalidBatch
+	}
+	return nil
+}
+
 // ChainId returns which chain id this transaction was signed for (if at all)
 func (tx *Transaction) ChainId() *big.Int {
 	return deriveChainId(tx.data.V)
 }
 
-// EncodeRLP implements rlp.Encoder
+// EncodeRLP implements rlp.Encoder. Typed transactions are encoded as an RLP
+// string holding their type byte and payload.
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, &tx.data)
+	}
+	enc, err := tx.encodeTyped()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, enc)
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
-	if err == nil {
-		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+	kind, size, _ := s.Kind()
+	if kind != rlp.String {
+		err := s.Decode(&tx.data)
+		if err == nil {
+			tx.sponsor, tx.calls = nil, nil
+			tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+		}
+		return err
+	}
+	enc, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	if len(enc) == 0 {
+		return ErrTxTypeNotSupported
+	}
+	switch enc[0] {
+	case SponsoredTxType:
+		var dec sponsoredtx
+		if err := rlp.DecodeBytes(enc[1:], &dec); err != nil {
+			return err
+		}
+		tx.data, tx.sponsor, tx.calls = dec.Tx, &dec.Sponsor, nil
+	case BatchTxType:
+		var dec batchtx
+		if err := rlp.DecodeBytes(enc[1:], &dec); err != nil {
+			return err
+		}
+		if err := validateBatch(&dec.Tx, dec.Calls); err != nil {
+			return err
+		}
+		tx.data, tx.sponsor, tx.calls = dec.Tx, nil, dec.Calls
+	default:
+		return ErrTxTypeNotSupported
 	}
+	tx.size.Store(common.StorageSize(rlp.ListSize(size)))
+	return nil
+}
 
-	return err
+// encodeTyped returns the type byte and payload of a typed transaction.
+func (tx *Transaction) encodeTyped() ([]byte, error) {
+	var (
+		typ     = tx.Type()
+		payload []byte
+		err     error
+	)
+	switch typ {
+	case SponsoredTxType:
+		payload, err = rlp.EncodeToBytes(&sponsoredtx{Tx: tx.data, Sponsor: *tx.sponsor})
+	case BatchTxType:
+		payload, err = rlp.EncodeToBytes(&batchtx{Tx: tx.data, Calls: tx.calls})
+	default:
+		return nil, ErrTxTypeNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{typ}, payload...), nil
 }
 
 // MarshalJSON encodes the web3 RPC transaction format.
@@ -130,7 +298,33 @@ func (tx *Transaction) MarshalJSON() ([]byte, error) {
 	hash := tx.Hash()
 	data := tx.data
 	data.Hash = &hash
-	return data.MarshalJSON()
+	enc, err := data.MarshalJSON()
+	if err != nil || tx.Type() == LegacyTxType {
+		return enc, err
+	}
+	// Merge the payer's signature or the calls into the transaction fields
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		return nil, err
+	}
+	var extra []byte
+	if tx.sponsor != nil {
+		extra, err = json.Marshal(&sponsorMarshaling{
+			Payer: &tx.sponsor.Payer,
+			V:     (*hexutil.Big)(tx.sponsor.V),
+			R:     (*hexutil.Big)(tx.sponsor.R),
+			S:     (*hexutil.Big)(tx.sponsor.S),
+		})
+	} else {
+		extra, err = json.Marshal(map[string][]BatchCall{"calls": tx.calls})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(extra, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
 }
 
 // UnmarshalJSON decodes the web3 RPC transaction format.
@@ -146,7 +340,40 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
 		return ErrInvalidSig
 	}
-	*tx = Transaction{data: dec}
+	var typed struct {
+		sponsorMarshaling
+		Calls []BatchCall `json:"calls"`
+	}
+	if err := json.Unmarshal(input, &typed); err != nil {
+		return err
+	}
+	sponsor := typed.sponsorMarshaling
+	if typed.Calls != nil {
+		if sponsor.Payer != nil {
+			return ErrTxTypeNotSupported
+		}
+		if err := validateBatch(&dec, typed.Calls); err != nil {
+			return err
+		}
+		*tx = Transaction{data: dec, calls: typed.Calls}
+		return nil
+	}
+	if sponsor.Payer == nil {
+		*tx = Transaction{data: dec}
+		return nil
+	}
+	if sponsor.V == nil || sponsor.R == nil || sponsor.S == nil {
+		return errors.New("missing payer signature in sponsored transaction")
+	}
+	*tx = Transaction{
+		data: dec,
+		sponsor: &sponsordata{
+			Payer: *sponsor.Payer,
+			V:     (*big.Int)(sponsor.V),
+			R:     (*big.Int)(sponsor.R),
+			S:     (*big.Int)(sponsor.S),
+		},
+	}
 	return nil
 }
 
@@ -157,6 +384,45 @@ func (tx *Transaction) Value() *big.Int    { return new(big.Int).Set(tx.data.Amo
 func (tx *Transaction) Nonce() uint64      { return tx.data.AccountNonce }
 func (tx *Transaction) CheckNonce() bool   { return true }
 
+// Type returns the type byte of the transaction, LegacyTxType for ordinary ones.
+func (tx *Transaction) Type() uint8 {
+	switch {
+	case tx.sponsor != nil:
+		return SponsoredTxType
+	case tx.calls != nil:
+		return BatchTxType
+	default:
+		return LegacyTxType
+	}
+}
+
+// Sponsored reports whether the gas of the transaction is paid by another
+// account than its sender.
+func (tx *Transaction) Sponsored() bool { return tx.sponsor != nil }
+
+// Calls returns a copy of the calls of a batch transaction. It returns nil for
+// other transactions.
+func (tx *Transaction) Calls() []BatchCall {
+	if tx.calls == nil {
+		return nil
+	}
+	calls := make([]BatchCall, len(tx.calls))
+	for i, call := range tx.calls {
+		calls[i] = BatchCall{To: call.To, Value: new(big.Int).Set(call.Value), Data: common.CopyBytes(call.Data)}
+	}
+	return calls
+}
+
+// Sponsor returns the account named to pay the gas of a sponsored transaction.
+// It returns nil for ordinary transactions.
+func (tx *Transaction) Sponsor() *common.Address {
+	if tx.sponsor == nil {
+		return nil
+	}
+	payer := tx.sponsor.Payer
+	return &payer
+}
+
 // To returns the recipient address of the transaction.
 // It returns nil if the transaction is a contract creation.
 func (tx *Transaction) To() *common.Address {
@@ -173,7 +439,13 @@ func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.Type() == LegacyTxType {
+		v = rlpHash(tx)
+	} else {
+		enc, _ := tx.encodeTyped()
+		v = crypto.Keccak256Hash(enc)
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -185,7 +457,7 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	rlp.Encode(&c, tx)
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
@@ -203,11 +475,17 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 		to:         tx.data.Recipient,
 		amount:     tx.data.Amount,
 		data:       tx.data.Payload,
+		calls:      tx.calls,
 		checkNonce: true,
 	}
 
 	var err error
 	msg.from, err = Sender(s, tx)
+	if err != nil || tx.sponsor == nil {
+		return msg, err
+	}
+	payer, err := Payer(s, tx)
+	msg.payer = &payer
 	return msg, err
 }
 
@@ -218,11 +496,25 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	if err != nil {
 		return nil, err
 	}
-	cpy := &Transaction{data: tx.data}
+	cpy := &Transaction{data: tx.data, sponsor: tx.sponsor, calls: tx.calls}
 	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
 	return cpy, nil
 }
 
+// WithPayerSignature returns a new sponsored transaction with the given signature
+// of its payer, formatted as for WithSignature.
+func (tx *Transaction) WithPayerSignature(signer Signer, sig []byte) (*Transaction, error) {
+	if tx.sponsor == nil {
+		return nil, ErrTxTypeNotSupported
+	}
+	r, s, v, err := signer.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, err
+	}
+	cpy := &Transaction{data: tx.data, sponsor: &sponsordata{Payer: tx.sponsor.Payer, V: v, R: r, S: s}}
+	return cpy, nil
+}
+
 // Cost returns amount + gasprice * gaslimit.
 func (tx *Transaction) Cost() *big.Int {
 	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
@@ -230,10 +522,28 @@ func (tx *Transaction) Cost() *big.Int {
 	return total
 }
 
+// SenderCost returns the part of Cost charged to the sender: all of it, or just
+// the amount if the gas is sponsored.
+func (tx *Transaction) SenderCost() *big.Int {
+	if tx.sponsor != nil {
+		return tx.Value()
+	}
+	return tx.Cost()
+}
+
 func (tx *Transaction) RawSignatureValues() (*big.Int, *big.Int, *big.Int) {
 	return tx.data.V, tx.data.R, tx.data.S
 }
 
+// RawPayerSignatureValues returns the signature values of the payer of a
+// sponsored transaction, or nils for ordinary ones.
+func (tx *Transaction) RawPayerSignatureValues() (*big.Int, *big.Int, *big.Int) {
+	if tx.sponsor == nil {
+		return nil, nil, nil
+	}
+	return tx.sponsor.V, tx.sponsor.R, tx.sponsor.S
+}
+
 // Transactions is a Transaction slice type for basic sorting.
 type Transactions []*Transaction
 
@@ -365,6 +675,8 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 type Message struct {
 	to         *common.Address
 	from       common.Address
+	payer      *common.Address // Gas payer if it isn't the sender
+	calls      []BatchCall     // Calls of a batch, nil for single calls
 	nonce      uint64
 	amount     *big.Int
 	gasLimit   uint64
@@ -394,3 +706,14 @@ func (m Message) Gas() uint64          { return m.gasLimit }
 func (m Message) Nonce() uint64        { return m.nonce }
 func (m Message) Data() []byte         { return m.data }
 func (m Message) CheckNonce() bool     { return m.checkNonce }
+
+// Calls returns the calls of a batch message, nil for single calls.
+func (m Message) Calls() []BatchCall { return m.calls }
+
+// Payer returns the account paying for the gas of the message.
+func (m Message) Payer() common.Address {
+	if m.payer != nil {
+		return *m.payer
+	}
+	return m.from
+}
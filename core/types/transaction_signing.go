@@ -24,12 +24,25 @@ import (
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 )
 
 var (
 	ErrInvalidChainId = errors.New("invalid chain id for signer")
+	ErrInvalidPayer   = errors.New("payer signature does not match the sponsor")
 )
 
+// MakeSigner returns a Signer based on the given chain config and block number.
+func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
+	if config.IsBatch(blockNumber) {
+		return NewBatchSigner(config.ChainID)
+	}
+	if config.IsSponsor(blockNumber) {
+		return NewSponsorSigner(config.ChainID)
+	}
+	return NewEIP155Signer(config.ChainID)
+}
+
 // sigCache is used to cache the derived sender and contains
 // the signer used to derive it.
 type sigCache struct {
@@ -47,6 +60,17 @@ func SignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, err
 	return tx.WithSignature(s, sig)
 }
 
+// SignPayer signs the sponsored transaction on behalf of its payer, once the
+// sender signed it.
+func SignPayer(tx *Transaction, s SponsorSigner, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h := s.PayerHash(tx)
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(s, sig)
+}
+
 // Sender returns the address derived from the signature (V, R, S) using secp256k1
 // elliptic curve and an error if it failed deriving or upon an incorrect
 // signature.
@@ -73,6 +97,36 @@ func Sender(signer Signer, tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// Payer returns the account paying for the gas of the transaction: its sender,
+// or for sponsored transactions the payer derived from their second signature.
+// Only the signers embedding SponsorSigner can derive the payer of sponsored
+// transactions.
+//
+// Payer may cache the address, the same way Sender does.
+func Payer(signer Signer, tx *Transaction) (common.Address, error) {
+	if tx.sponsor == nil {
+		return Sender(signer, tx)
+	}
+	if sc := tx.payer.Load(); sc != nil {
+		sigCache := sc.(sigCache)
+		if sigCache.signer.Equal(signer) {
+			return sigCache.from, nil
+		}
+	}
+	sponsorSigner, ok := signer.(interface {
+		Payer(tx *Transaction) (common.Address, error)
+	})
+	if !ok {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	addr, err := sponsorSigner.Payer(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.payer.Store(sigCache{signer: signer, from: addr})
+	return addr, nil
+}
+
 // Signer encapsulates transaction signature handling. Note that this interface is not a
 // stable API and may change at any time to accommodate new protocol rules.
 type Signer interface {
@@ -112,6 +166,9 @@ func (s EIP155Signer) Equal(s2 Signer) bool {
 var big8 = big.NewInt(8)
 
 func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 
 	if tx.ChainId().Cmp(s.chainId) != 0 {
 		return common.Address{}, ErrInvalidChainId
@@ -149,6 +206,133 @@ func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
 	})
 }
 
+// SponsorSigner implements Signer for sponsored transactions, whose gas is paid
+// by a second signer, and handles ordinary ones the way EIP155Signer does.
+type SponsorSigner struct {
+	EIP155Signer
+}
+
+func NewSponsorSigner(chainId *big.Int) SponsorSigner {
+	return SponsorSigner{NewEIP155Signer(chainId)}
+}
+
+func (s SponsorSigner) Equal(s2 Signer) bool {
+	sponsor, ok := s2.(SponsorSigner)
+	return ok && sponsor.chainId.Cmp(s.chainId) == 0
+}
+
+func (s SponsorSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.sponsor == nil {
+		return s.EIP155Signer.Sender(tx)
+	}
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, ErrInvalidChainId
+	}
+	V := new(big.Int).Sub(tx.data.V, s.chainIdMul)
+	V.Sub(V, big8)
+	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+}
+
+// Payer returns the payer of the sponsored transaction, checking that it is the
+// one the sender named.
+func (s SponsorSigner) Payer(tx *Transaction) (common.Address, error) {
+	if tx.sponsor == nil {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	if deriveChainId(tx.sponsor.V).Cmp(s.chainId) != 0 {
+		return common.Address{}, ErrInvalidChainId
+	}
+	V := new(big.Int).Sub(tx.sponsor.V, s.chainIdMul)
+	V.Sub(V, big8)
+	payer, err := recoverPlain(s.PayerHash(tx), tx.sponsor.R, tx.sponsor.S, V, true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if payer != tx.sponsor.Payer {
+		return common.Address{}, ErrInvalidPayer
+	}
+	return payer, nil
+}
+
+// Hash returns the hash to be signed by the sender. For sponsored transactions
+// it covers the payer as well, so that the sender agrees on who pays.
+func (s SponsorSigner) Hash(tx *Transaction) common.Hash {
+	if tx.sponsor == nil {
+		return s.EIP155Signer.Hash(tx)
+	}
+	return rlpHash([]interface{}{
+		uint(SponsoredTxType),
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		tx.sponsor.Payer,
+		s.chainId, uint(0), uint(0),
+	})
+}
+
+// PayerHash returns the hash to be signed by the payer of a sponsored transaction,
+// covering the transaction signed by its sender.
+func (s SponsorSigner) PayerHash(tx *Transaction) common.Hash {
+	return rlpHash([]interface{}{
+		uint(SponsoredTxType),
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		tx.sponsor.Payer,
+		tx.data.V, tx.data.R, tx.data.S,
+		s.chainId,
+	})
+}
+
+// BatchSigner implements Signer for batch transactions, and handles the other
+// ones the way SponsorSigner does.
+type BatchSigner struct {
+	SponsorSigner
+}
+
+func NewBatchSigner(chainId *big.Int) BatchSigner {
+	return BatchSigner{NewSponsorSigner(chainId)}
+}
+
+func (s BatchSigner) Equal(s2 Signer) bool {
+	batch, ok := s2.(BatchSigner)
+	return ok && batch.chainId.Cmp(s.chainId) == 0
+}
+
+func (s BatchSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.calls == nil {
+		return s.SponsorSigner.Sender(tx)
+	}
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, ErrInvalidChainId
+	}
+	V := new(big.Int).Sub(tx.data.V, s.chainIdMul)
+	V.Sub(V, big8)
+	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+}
+
+// Hash returns the hash to be signed by the sender. For batch transactions it
+// covers every call.
+func (s BatchSigner) Hash(tx *Transaction) common.Hash {
+	if tx.calls == nil {
+		return s.SponsorSigner.Hash(tx)
+	}
+	return rlpHash([]interface{}{
+		uint(BatchTxType),
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.calls,
+		s.chainId, uint(0), uint(0),
+	})
+}
+
 func (s EIP155Signer) SignatureAndSender(tx *Transaction) (common.Address, []byte, error) {
 
 	if tx.ChainId().Cmp(s.chainId) != 0 {
@@ -130,3 +130,109 @@ func TestChainId(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+func TestSponsorSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	payerKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+
+	signer := NewSponsorSigner(big.NewInt(18))
+	tx, err := SignTx(NewSponsoredTransaction(NewTransaction(0, addr, big.NewInt(10), 21000, big.NewInt(1), nil), payer), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEIP155Signer(big.NewInt(18)).Sender(tx); err != ErrTxTypeNotSupported {
+		t.Errorf("EIP155 signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	forged, err := SignPayer(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Payer(signer, forged); err != ErrInvalidPayer {
+		t.Errorf("forged payer error mismatch: have %v, want %v", err, ErrInvalidPayer)
+	}
+	tx, err = SignPayer(tx, signer, payerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := new(Transaction)
+	if err := rlp.DecodeBytes(enc, dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch after rlp round trip: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	from, err := Sender(signer, dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("sender mismatch: have %x, want %x", from, addr)
+	}
+	have, err := Payer(signer, dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have != payer {
+		t.Errorf("payer mismatch: have %x, want %x", have, payer)
+	}
+	if dec.SenderCost().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("sender cost mismatch: have %v, want 10", dec.SenderCost())
+	}
+}
+
+func TestBatchSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	calls := []BatchCall{
+		{To: common.BytesToAddress([]byte{0x01}), Value: big.NewInt(10), Data: []byte{0x01, 0x02}},
+		{To: common.BytesToAddress([]byte{0x02}), Value: big.NewInt(20)},
+	}
+	signer := NewBatchSigner(big.NewInt(18))
+	tx, err := SignTx(NewBatchTransaction(0, calls, 100000, big.NewInt(1)), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != BatchTxType || tx.To() != nil || tx.Value().Cmp(big.NewInt(30)) != 0 {
+		t.Errorf("batch envelope mismatch: type %d, to %v, value %v", tx.Type(), tx.To(), tx.Value())
+	}
+	if _, err := NewSponsorSigner(big.NewInt(18)).Sender(tx); err != ErrTxTypeNotSupported {
+		t.Errorf("sponsor signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := new(Transaction)
+	if err := rlp.DecodeBytes(enc, dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch after rlp round trip: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	from, err := Sender(signer, dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("sender mismatch: have %x, want %x", from, addr)
+	}
+	if have := dec.Calls(); len(have) != len(calls) || have[1].Value.Cmp(calls[1].Value) != 0 {
+		t.Errorf("calls mismatch: have %v, want %v", have, calls)
+	}
+
+	// A batch whose value doesn't add up its calls is refused
+	payload, _ := rlp.EncodeToBytes(&batchtx{Tx: tx.data, Calls: calls[:1]})
+	enc, _ = rlp.EncodeToBytes(append([]byte{BatchTxType}, payload...))
+	if err := rlp.DecodeBytes(enc, new(Transaction)); err != ErrInvalidBatch {
+		t.Errorf("invalid batch error mismatch: have %v, want %v", err, ErrInvalidBatch)
+	}
+}
@@ -17,7 +17,9 @@
 package vm
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"testing"
 
@@ -348,9 +350,45 @@ func testPrecompiled(addr string, test PrecompiledTest, t *testing.T) {
 		} else if common.Bytes2Hex(res) != test.Expected {
 			t.Errorf("Expected %v, got %v", test.Expected, common.Bytes2Hex(res))
 		}
+		if gas := p.RequiredGas(in); test.Gas != 0 && gas != test.Gas {
+			t.Errorf("Expected %v gas, got %v", test.Gas, gas)
+		}
 	})
 }
 
+// loadPrecompiledTests reads the input/output pairs of a precompiled contract
+// from testdata/precompiles. The vectors are shared with the WASM host
+// functions implementing the same operations in life/resolver.
+func loadPrecompiledTests(name string) ([]PrecompiledTest, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("testdata/precompiles/%s.json", name))
+	if err != nil {
+		return nil, err
+	}
+	var tests []PrecompiledTest
+	err = json.Unmarshal(data, &tests)
+	return tests, err
+}
+
+func testPrecompiledJson(addr, name string, t *testing.T) {
+	tests, err := loadPrecompiledTests(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		testPrecompiled(addr, test, t)
+	}
+}
+
+func benchmarkPrecompiledJson(addr, name string, bench *testing.B) {
+	tests, err := loadPrecompiledTests(name)
+	if err != nil {
+		bench.Fatal(err)
+	}
+	for _, test := range tests {
+		benchmarkPrecompiled(addr, test, bench)
+	}
+}
+
 func benchmarkPrecompiled(addr string, test PrecompiledTest, bench *testing.B) {
 	if test.NoBenchmark {
 		return
@@ -387,34 +425,34 @@ func benchmarkPrecompiled(addr string, test PrecompiledTest, bench *testing.B) {
 	})
 }
 
+// Tests the sample inputs from the ECRECOVER precompile.
+func TestPrecompiledEcrecover(t *testing.T) {
+	testPrecompiledJson("01", "ecRecover", t)
+}
+
 // Benchmarks the sample inputs from the ECRECOVER precompile.
 func BenchmarkPrecompiledEcrecover(bench *testing.B) {
-	t := PrecompiledTest{
-		Input:    "38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e000000000000000000000000000000000000000000000000000000000000001b38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e789d1dd423d25f0772d2748d60f7e4b81bb14d086eba8e8e8efb6dcff8a4ae02",
-		Expected: "000000000000000000000000ceaccac640adf55b2028469bd36ba501f28b699d",
-		Name:     "",
-	}
-	benchmarkPrecompiled("01", t, bench)
+	benchmarkPrecompiledJson("01", "ecRecover", bench)
+}
+
+// Tests the sample inputs from the SHA256 precompile.
+func TestPrecompiledSha256(t *testing.T) {
+	testPrecompiledJson("02", "sha256", t)
 }
 
 // Benchmarks the sample inputs from the SHA256 precompile.
 func BenchmarkPrecompiledSha256(bench *testing.B) {
-	t := PrecompiledTest{
-		Input:    "38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e000000000000000000000000000000000000000000000000000000000000001b38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e789d1dd423d25f0772d2748d60f7e4b81bb14d086eba8e8e8efb6dcff8a4ae02",
-		Expected: "811c7003375852fabd0d362e40e68607a12bdabae61a7d068fe5fdd1dbbf2a5d",
-		Name:     "128",
-	}
-	benchmarkPrecompiled("02", t, bench)
+	benchmarkPrecompiledJson("02", "sha256", bench)
+}
+
+// Tests the sample inputs from the RIPEMD precompile.
+func TestPrecompiledRipeMD(t *testing.T) {
+	testPrecompiledJson("03", "ripemd160", t)
 }
 
 // Benchmarks the sample inputs from the RIPEMD precompile.
 func BenchmarkPrecompiledRipeMD(bench *testing.B) {
-	t := PrecompiledTest{
-		Input:    "38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e000000000000000000000000000000000000000000000000000000000000001b38d18acb67d25c8bb9942764b62f18e17054f66a817bd4295423adf9ed98873e789d1dd423d25f0772d2748d60f7e4b81bb14d086eba8e8e8efb6dcff8a4ae02",
-		Expected: "0000000000000000000000009215b8d9882ff46f0dfde6684d78e831467f65e6",
-		Name:     "128",
-	}
-	benchmarkPrecompiled("03", t, bench)
+	benchmarkPrecompiledJson("03", "ripemd160", bench)
 }
 
 // Benchmarks the sample inputs from the identiy precompile.
@@ -53,6 +53,11 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 		}
 
 		if p := PlatONPrecompiledContracts[*contract.CodeAddr]; p != nil {
+			// PPOS contracts keep their data in the snapshot db, which
+			// a failing batch can't revert
+			if evm.batch {
+				return nil, ErrPPOSCallInBatch
+			}
 			switch p.(type) {
 
 			case *validatorInnerContract:
@@ -171,6 +176,8 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// batch is set while the calls of a batch transaction are executed
+	batch bool
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -203,6 +210,12 @@ func (evm *EVM) Cancel() {
 	atomic.StoreInt32(&evm.abort, 1)
 }
 
+// SetBatch marks whether the EVM is executing the calls of a batch
+// transaction. PPOS contracts can't be called, directly or not, in a batch.
+func (evm *EVM) SetBatch(batch bool) {
+	evm.batch = batch
+}
+
 // Interpreter returns the current interpreter
 func (evm *EVM) Interpreter() Interpreter {
 	return evm.interpreter
@@ -72,6 +72,11 @@ type StateDB interface {
 	AddPreimage(common.Hash, []byte)
 
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool)
+	// StorageEntries returns the number of storage entries of an account.
+	StorageEntries(common.Address) uint64
+	// CopyStorage replaces the storage of an account with a copy of the
+	// storage of another one.
+	CopyStorage(from, to common.Address)
 
 	//ppos add
 	TxHash() common.Hash
@@ -8,14 +8,17 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/math"
 	"github.com/PlatONnetwork/PlatON-Go/core/lru"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/life/utils"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"math/big"
 	"reflect"
 	"runtime"
 	"strings"
 
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
 	"github.com/PlatONnetwork/PlatON-Go/life/exec"
 	"github.com/PlatONnetwork/PlatON-Go/life/resolver"
 )
@@ -36,6 +39,14 @@ var DEFAULT_VM_CONFIG = exec.VMConfig{
 	DynamicMemoryPages: exec.DynamicMemoryPages,
 }
 
+// DEFAULT_VALIDATION_CONFIG holds the limits of the modules being deployed.
+// Modules already deployed keep running with the limits of DEFAULT_VM_CONFIG.
+var DEFAULT_VALIDATION_CONFIG = exec.ValidationConfig{
+	MaxCodeSize:    params.MaxCodeSize,
+	MaxMemoryPages: exec.DefaultMaxMemoryPages,
+	MaxTableSize:   exec.DefaultMaxTableSize,
+}
+
 // WASMInterpreter represents an WASM interpreter
 type WASMInterpreter struct {
 	evm         *EVM
@@ -44,6 +55,8 @@ type WASMInterpreter struct {
 	WasmLogger  log.Logger
 	resolver    exec.ImportResolver
 	returnData  []byte
+
+	preHostForkResolver exec.ImportResolver // resolver of the blocks before the wasm host fork
 }
 
 // NewWASMInterpreter returns a new instance of the Interpreter
@@ -60,6 +73,8 @@ func NewWASMInterpreter(evm *EVM, cfg Config) *WASMInterpreter {
 		WasmLogger:  NewWasmLogger(cfg, log.WasmRoot()),
 		wasmStateDB: wasmStateDB,
 		resolver:    resolver.NewResolver(0x01),
+
+		preHostForkResolver: resolver.NewPreHostForkResolver(0x01),
 	}
 }
 
@@ -70,8 +85,24 @@ func NewWASMInterpreter(evm *EVM, cfg Config) *WASMInterpreter {
 // considered a revert-and-consume-all-gas operations except for
 // errExecutionReverted which means revert-and-keep-gas-lfet.
 func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (ret []byte, err error) {
+	var tracer WasmTracer
+	if in.cfg.Debug {
+		tracer, _ = in.cfg.Tracer.(WasmTracer)
+	}
+	if tracer != nil {
+		gas := contract.Gas
+		tracer.CaptureEnter(wasmCallType(contract, input), contract.Caller(), contract.Address(), input, gas, contract.value)
+		defer func() {
+			tracer.CaptureExit(ret, gas-contract.Gas, err)
+		}()
+	}
+	var context *exec.VMContext
 	defer func() {
 		if er := recover(); er != nil {
+			if rev, ok := er.(*exec.RevertError); ok && context != nil {
+				ret, err = revert(contract, context, rev)
+				return
+			}
 			ret, err = nil, fmt.Errorf("VM execute fail：%v", er)
 		}
 	}()
@@ -89,33 +120,65 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	if len(contract.Code) == 0 {
 		return nil, nil
 	}
+	codeAddr := contract.Address()
+	if contract.CodeAddr != nil {
+		codeAddr = *contract.CodeAddr
+	}
+	// Contracts can only be migrated from the wasm host fork on, there is
+	// nothing to look up before it.
+	if in.evm.chainConfig.IsWasmHost(in.evm.BlockNumber) {
+		if to, ok := MigratedTo(in.evm.StateDB, codeAddr); ok {
+			return nil, fmt.Errorf("contract %x has been migrated to %x", codeAddr, to)
+		}
+	}
 	_, abi, code, er := parseRlpData(contract.Code)
 	if er != nil {
 		return nil, er
 	}
 
-	context := &exec.VMContext{
+	context = &exec.VMContext{
 		Config:   DEFAULT_VM_CONFIG,
 		Addr:     contract.Address(),
 		GasLimit: contract.Gas,
 		StateDB:  NewWasmStateDB(in.wasmStateDB, contract),
 		Log:      in.WasmLogger,
 	}
+	if tracer != nil {
+		context.Tracer = tracer
+	}
 
 	var lvm *exec.VirtualMachine
 	var module *lru.WasmModule
-	module, ok := lru.WasmCache().Get(contract.Address())
+	codeHash := contract.CodeHash
+	if codeHash == (common.Hash{}) {
+		codeHash = crypto.Keccak256Hash(contract.Code)
+	}
+	module, ok := lru.WasmCache().Get(codeHash)
 
-	if !ok {
-		module = &lru.WasmModule{}
-		module.Module, module.FunctionCode, err = exec.ParseModuleAndFunc(code, nil)
+	// Gas is charged per basic block from the wasm gas fork on, before it
+	// every executed instruction is charged on its own. Cached modules
+	// compiled the other way are compiled again, as the block crossing the
+	// fork may find them in the cache.
+	var gasPolicy compiler.GasPolicy
+	if in.evm.chainConfig.IsWasmGas(in.evm.BlockNumber) {
+		gasPolicy = compiler.DefaultGasPolicy
+	}
+	if !ok || compiler.GasMetered(module.FunctionCode) != (gasPolicy != nil) {
+		module = &lru.WasmModule{Code: code}
+		module.Module, module.FunctionCode, err = exec.ParseModuleAndFunc(code, gasPolicy)
 		if err != nil {
 			return nil, err
 		}
-		lru.WasmCache().Add(contract.Address(), module)
+		lru.WasmCache().Add(codeHash, module)
+	}
+
+	if input == nil {
+		if err = in.validateModule(code, module.Module); err != nil {
+			return nil, err
+		}
 	}
 
-	lvm, err = exec.NewVirtualMachineWithModule(module.Module, module.FunctionCode, context, in.resolver, nil)
+	lvm, err = exec.NewVirtualMachineWithModule(module.Module, module.FunctionCode, context, in.importResolver(), gasPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +198,7 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		funcName = "init" // init function.
 	} else {
 		// parse input.
-		txType, funcName, params, returnType, err = parseInputFromAbi(lvm, input, abi)
+		txType, funcName, params, returnType, err = parseInputFromAbi(lvm, input, abi, in.evm.chainConfig.IsWasmAbi(in.evm.BlockNumber))
 		if err != nil {
 			if err == errReturnInsufficientParams && txType == 0 { // transfer to contract address.
 				return nil, nil
@@ -152,6 +215,9 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	}
 	res, err := lvm.RunWithGasLimit(entryID, int(context.GasLimit), params...)
 	if err != nil {
+		if rev, ok := err.(*exec.RevertError); ok {
+			return revert(contract, context, rev)
+		}
 		fmt.Println("throw exception:", err.Error())
 		return nil, err
 	}
@@ -165,9 +231,14 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		return contract.Code, nil
 	}
 
+	// Results of the types added by the wasm abi fork were dropped before it.
+	if !in.evm.chainConfig.IsWasmAbi(in.evm.BlockNumber) && (returnType == "int16" || returnType == "bool") {
+		return nil, nil
+	}
+
 	// todo: more type need to be completed
 	switch returnType {
-	case "void", "int8", "int", "int32", "int64":
+	case "void", "int8", "int16", "int", "int32", "int64":
 		if txType == CALL_CANTRACT_FLAG {
 			return utils.Int64ToBytes(res), nil
 		}
@@ -175,7 +246,7 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 		bigRes.SetInt64(res)
 		finalRes := utils.Align32Bytes(math.U256(bigRes).Bytes())
 		return finalRes, nil
-	case "uint8", "uint16", "uint32", "uint64":
+	case "uint8", "uint16", "uint32", "uint64", "bool":
 		if txType == CALL_CANTRACT_FLAG {
 			return utils.Uint64ToBytes(uint64(res)), nil
 		}
@@ -213,14 +284,56 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	return nil, nil
 }
 
+// revert charges the gas used until platonRevert was called and hands the
+// revert payload back, leaving the remaining gas to the caller.
+func revert(contract *Contract, context *exec.VMContext, rev *exec.RevertError) ([]byte, error) {
+	if !contract.UseGas(context.GasUsed) {
+		return nil, ErrOutOfGas
+	}
+	return rev.Data, errExecutionReverted
+}
+
+// validateModule checks a module being deployed. Modules are validated once,
+// when deployed, so that code which can't be run deterministically is
+// rejected before it reaches the state. Blocks before the wasm validate fork
+// deployed such modules and keep being replayed without the checks.
+func (in *WASMInterpreter) validateModule(code []byte, m *compiler.Module) error {
+	if !in.evm.chainConfig.IsWasmValidate(in.evm.BlockNumber) {
+		return nil
+	}
+	return exec.ValidateModule(code, m, DEFAULT_VALIDATION_CONFIG, in.importResolver())
+}
+
+// importResolver returns the resolver of the host functions available at the
+// current block. Before the wasm host fork the host functions it added trap as
+// unknown ones.
+func (in *WASMInterpreter) importResolver() exec.ImportResolver {
+	if !in.evm.chainConfig.IsWasmHost(in.evm.BlockNumber) {
+		return in.preHostForkResolver
+	}
+	return in.resolver
+}
+
 // CanRun tells if the contract, passed as an argument, can be run
 // by the current interpreter
 func (in *WASMInterpreter) CanRun(code []byte) bool {
 	return true
 }
 
+// wasmCallType names the kind of invocation running contract for tracers.
+func wasmCallType(contract *Contract, input []byte) string {
+	switch {
+	case input == nil:
+		return "CREATE"
+	case contract.DelegateCall:
+		return "DELEGATECALL"
+	default:
+		return "CALL"
+	}
+}
+
 // parse input(payload)
-func parseInputFromAbi(vm *exec.VirtualMachine, input []byte, abi []byte) (txType int, funcName string, params []int64, returnType string, err error) {
+func parseInputFromAbi(vm *exec.VirtualMachine, input []byte, abi []byte, abiFork bool) (txType int, funcName string, params []int64, returnType string, err error) {
 	if input == nil || len(input) <= 1 {
 		return -1, "", nil, "", fmt.Errorf("invalid input.")
 	}
@@ -296,6 +409,11 @@ func parseInputFromAbi(vm *exec.VirtualMachine, input []byte, abi []byte) (txTyp
 			params = append(params, int64(binary.BigEndian.Uint64(bts)))
 		case "uint8":
 			params = append(params, int64(bts[0]))
+		case "uint16":
+			// Skipped like any unknown type before the wasm abi fork.
+			if abiFork {
+				params = append(params, int64(binary.BigEndian.Uint16(bts)))
+			}
 		case "uint32", "uint":
 			params = append(params, int64(binary.BigEndian.Uint32(bts)))
 		case "uint64":
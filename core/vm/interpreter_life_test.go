@@ -3,10 +3,17 @@ package vm
 
 import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/mock"
+	commonvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/life/utils"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"fmt"
 	"io/ioutil"
 	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -117,4 +124,194 @@ func (stateDB) ForEachStorage(common.Address, func(common.Hash, common.Hash) boo
 
 func (stateDB) AddLog(*types.Log) {
 	fmt.Println("add log")
-}
\ No newline at end of file
+}
+func TestWasmStateDBCloneEmptyCode(t *testing.T) {
+	evm := &EVM{StateDB: stateDB{}}
+	contract := &Contract{
+		caller: ContractRefCaller{},
+		self:   ContractRefSelf{},
+	}
+	db := NewWasmStateDB(&WasmStateDB{StateDB: evm.StateDB, evm: evm}, contract)
+
+	addr, leftOverGas, err := db.Clone(common.BigToAddress(big.NewInt(1)), big.NewInt(0), 1000)
+	if err != errCloneEmptyCode {
+		t.Fatalf("clone error mismatch, want %v, have %v", errCloneEmptyCode, err)
+	}
+	if leftOverGas != 1000 {
+		t.Fatalf("gas should be returned on failure, have %d", leftOverGas)
+	}
+	if addr != (common.Address{}) {
+		t.Fatalf("unexpected address %s", addr.Hex())
+	}
+}
+
+func TestWasmStateDBMigrateOutOfGas(t *testing.T) {
+	state := mock.NewChain().StateDB
+	evm := &EVM{StateDB: state}
+	contract := &Contract{
+		caller: ContractRefCaller{},
+		self:   ContractRefSelf{},
+	}
+	from := contract.Address()
+	state.SetState(from, []byte("a"), []byte("1"))
+	state.SetState(from, []byte("b"), []byte("2"))
+	db := NewWasmStateDB(&WasmStateDB{StateDB: evm.StateDB, evm: evm}, contract)
+
+	// The entries are charged before the new contract is created
+	addr, leftOverGas, err := db.Migrate(nil, nil, big.NewInt(0), 2*params.MigrateEntryGas-1)
+	if err != ErrOutOfGas {
+		t.Fatalf("migrate error mismatch, want %v, have %v", ErrOutOfGas, err)
+	}
+	if leftOverGas != 0 || addr != (common.Address{}) {
+		t.Fatalf("unexpected result %s, gas %d", addr.Hex(), leftOverGas)
+	}
+	if len(state.State) != 1 {
+		t.Fatalf("state changed on failure: %v", state.State)
+	}
+}
+
+func TestMigratedTo(t *testing.T) {
+	state := mock.NewChain().StateDB
+	from, to := common.Address{1}, common.Address{2}
+
+	// The contract can not write its own forwarding record
+	state.SetState(from, from.Bytes(), to.Bytes())
+	if _, ok := MigratedTo(state, from); ok {
+		t.Fatal("forwarding record read from the contract storage")
+	}
+	state.SetState(commonvm.MigrationRecordAddr, from.Bytes(), to.Bytes())
+	if have, ok := MigratedTo(state, from); !ok || have != to {
+		t.Fatalf("forwarding record mismatch, want %s, have %s (%v)", to.Hex(), have.Hex(), ok)
+	}
+}
+
+func TestParseInputFromAbiFork(t *testing.T) {
+	abi := []byte(`[{"name": "set", "inputs": [{"name": "a", "type": "uint16"}, {"name": "b", "type": "int64"}], "outputs": [{"name": "", "type": "bool"}], "type": "function"}]`)
+	input, err := rlp.EncodeToBytes([][]byte{utils.Int64ToBytes(1), []byte("set"), {0x01, 0x02}, utils.Int64ToBytes(7)})
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	// uint16 arguments are dropped before the wasm abi fork
+	_, _, params, returnType, err := parseInputFromAbi(nil, input, abi, false)
+	if err != nil || len(params) != 1 || params[0] != 7 || returnType != "bool" {
+		t.Errorf("pre fork input mismatch: have %v %s (%v)", params, returnType, err)
+	}
+	_, _, params, _, err = parseInputFromAbi(nil, input, abi, true)
+	if err != nil || len(params) != 2 || params[0] != 0x0102 || params[1] != 7 {
+		t.Errorf("post fork input mismatch: have %v (%v)", params, err)
+	}
+}
+
+func TestValidateModuleFork(t *testing.T) {
+	// (func (result f32) (f32.add (f32.const 1) (f32.const 1)))
+	code := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7d,
+		0x03, 0x02, 0x01, 0x00,
+		0x0a, 0x0f, 0x01, 0x0d, 0x00, 0x43, 0x00, 0x00, 0x80, 0x3f, 0x43, 0x00, 0x00, 0x80, 0x3f, 0x92, 0x0b,
+	}
+	m, _, err := exec.ParseModuleAndFunc(code, nil)
+	if err != nil {
+		t.Fatalf("failed to load module: %v", err)
+	}
+	evm := &EVM{
+		StateDB:     stateDB{},
+		chainConfig: &params.ChainConfig{WasmValidateBlock: big.NewInt(10)},
+	}
+	in := NewWASMInterpreter(evm, Config{})
+
+	// Creates accepted before the fork are replayed without validation
+	evm.BlockNumber = big.NewInt(9)
+	if err := in.validateModule(code, m); err != nil {
+		t.Fatalf("pre fork create failed: %v", err)
+	}
+	evm.BlockNumber = big.NewInt(10)
+	if verr, ok := in.validateModule(code, m).(*exec.ValidationError); !ok || verr.Err != exec.ErrFloatInstruction {
+		t.Fatalf("post fork create error mismatch: have %v, want %v", verr, exec.ErrFloatInstruction)
+	}
+}
+
+// revertStateDB is a mock state holding contract code and snapshots of the
+// contract storage.
+type revertStateDB struct {
+	*mock.MockStateDB
+	code      map[common.Address][]byte
+	snapshots []map[common.Address]map[string][]byte
+}
+
+func (s *revertStateDB) Exist(addr common.Address) bool     { return s.code[addr] != nil }
+func (s *revertStateDB) GetCode(addr common.Address) []byte { return s.code[addr] }
+
+func (s *revertStateDB) Snapshot() int {
+	state := make(map[common.Address]map[string][]byte)
+	for addr, storage := range s.State {
+		state[addr] = make(map[string][]byte)
+		for k, v := range storage {
+			state[addr][k] = v
+		}
+	}
+	s.snapshots = append(s.snapshots, state)
+	return len(s.snapshots) - 1
+}
+
+func (s *revertStateDB) RevertToSnapshot(id int) {
+	s.State = s.snapshots[id]
+	s.snapshots = s.snapshots[:id]
+}
+
+func TestWasmPlatonRevert(t *testing.T) {
+	// (func (export "f") (call $setState (i32.const 0) (i32.const 1) (i32.const 1) (i32.const 1))
+	//	(call $platonRevert (i32.const 2) (i32.const 3)))
+	// (data (i32.const 0) "kvmsg")
+	module := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x10, 0x03, 0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x00, 0x60, 0x02, 0x7f, 0x7f, 0x00, 0x60, 0x00, 0x00,
+		0x02, 0x23, 0x02, 0x03, 0x65, 0x6e, 0x76, 0x08, 0x73, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x00, 0x00,
+		0x03, 0x65, 0x6e, 0x76, 0x0c, 0x70, 0x6c, 0x61, 0x74, 0x6f, 0x6e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x00, 0x01,
+		0x03, 0x02, 0x01, 0x02,
+		0x05, 0x03, 0x01, 0x00, 0x01,
+		0x07, 0x05, 0x01, 0x01, 0x66, 0x00, 0x02,
+		0x0a, 0x14, 0x01, 0x12, 0x00, 0x41, 0x00, 0x41, 0x01, 0x41, 0x01, 0x41, 0x01, 0x10, 0x00, 0x41, 0x02, 0x41, 0x03, 0x10, 0x01, 0x0b,
+		0x0b, 0x0b, 0x01, 0x00, 0x41, 0x00, 0x0b, 0x05, 0x6b, 0x76, 0x6d, 0x73, 0x67,
+	}
+	abi := []byte(`[{"name": "f", "inputs": [], "outputs": [], "type": "function"}]`)
+	code, err := rlp.EncodeToBytes([][]byte{utils.Int64ToBytes(2), module, abi})
+	if err != nil {
+		t.Fatalf("failed to encode code: %v", err)
+	}
+	input, err := rlp.EncodeToBytes([][]byte{utils.Int64ToBytes(2), []byte("f")})
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	addr := common.Address{0xaa}
+	state := &revertStateDB{MockStateDB: mock.NewChain().StateDB, code: map[common.Address][]byte{addr: code}}
+	ctx := Context{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		BlockNumber: big.NewInt(1),
+	}
+	config := &params.ChainConfig{WasmHostBlock: big.NewInt(1)}
+	evm := NewEVM(ctx, state, config, Config{})
+
+	ret, leftOverGas, err := evm.Call(AccountRef(common.Address{0x01}), addr, input, 100000, big.NewInt(0))
+	if err != errExecutionReverted {
+		t.Fatalf("call error mismatch: have %v, want %v", err, errExecutionReverted)
+	}
+	if string(ret) != "msg" {
+		t.Errorf("revert data mismatch: have %q, want %q", ret, "msg")
+	}
+	// The gas used until the revert is charged, the rest is left to the caller
+	if leftOverGas == 0 || leftOverGas >= 100000 {
+		t.Errorf("unexpected gas left: %d", leftOverGas)
+	}
+	if val := state.GetState(addr, []byte("k")); val != nil {
+		t.Errorf("state not rolled back: have %q", val)
+	}
+
+	// Before the wasm host fork platonRevert traps like any unknown import
+	ctx.BlockNumber = big.NewInt(0)
+	evm = NewEVM(ctx, state, config, Config{})
+	if _, _, err := evm.Call(AccountRef(common.Address{0x01}), addr, input, 100000, big.NewInt(0)); err == nil || !strings.Contains(err.Error(), "module:env field:platonRevert") {
+		t.Fatalf("pre fork call error mismatch: have %v", err)
+	}
+}
@@ -1040,3 +1040,20 @@ func setVerifierList(blockHash common.Hash, valArr *staking.ValidatorArray) erro
 
 	return nil
 }
+
+func TestPPOSCallInBatch(t *testing.T) {
+	evm := &EVM{StateDB: mock.NewChain().StateDB}
+	evm.SetBatch(true)
+
+	for addr := range PlatONPrecompiledContracts {
+		addr := addr
+		contract := newContract(common.Big0, sender)
+		contract.SetCallCode(&addr, common.Hash{}, nil)
+		if _, err := run(evm, contract, nil, false); err != ErrPPOSCallInBatch {
+			t.Errorf("call to %x in batch: error mismatch: have %v, want %v", addr, err, ErrPPOSCallInBatch)
+		}
+		if contract.Gas != initGas {
+			t.Errorf("call to %x in batch: gas used %d", addr, initGas-contract.Gas)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler/opcodes"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+)
+
+// WasmTracer is a Tracer that also follows WASM executions. Besides the steps
+// of the life interpreter it is told about every contract invocation the WASM
+// interpreter runs, including the nested ones made through platonCall and
+// platonDelegateCall.
+type WasmTracer interface {
+	Tracer
+	exec.Tracer
+
+	// CaptureEnter is called when the WASM interpreter starts running the
+	// code of a contract.
+	CaptureEnter(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
+
+	// CaptureExit is called when the invocation of the matching CaptureEnter
+	// returns.
+	CaptureExit(output []byte, gasUsed uint64, err error)
+}
+
+// WasmHostCall describes the invocation of a host function by a contract.
+type WasmHostCall struct {
+	Module string  `json:"module"`
+	Field  string  `json:"field"`
+	Args   []int64 `json:"args"`
+	Return *int64  `json:"return,omitempty"`
+}
+
+// WasmStructLog is emitted for every instruction executed by the WASM
+// interpreter and lists the state of the current frame prior to its execution.
+type WasmStructLog struct {
+	Depth    int           `json:"depth"`              // Depth of the contract invocation
+	Frame    int           `json:"frame"`              // Depth of the call stack within the contract
+	Function int           `json:"function"`           // Index of the executing function
+	Pc       int           `json:"pc"`                 // Offset of the instruction in the compiled function
+	Op       string        `json:"op"`                 // Name of the instruction
+	Gas      uint64        `json:"gas"`                // Gas left after charging the instruction
+	Stack    []int64       `json:"stack,omitempty"`    // Registers of the frame
+	Locals   []int64       `json:"locals,omitempty"`   // Locals of the frame
+	HostCall *WasmHostCall `json:"hostCall,omitempty"` // Host function run by an import stub
+}
+
+// WasmStructLogger is a WASM step logger and implements WasmTracer. The logs it
+// collects marshal to JSON as they are.
+type WasmStructLogger struct {
+	cfg LogConfig
+
+	logs      []WasmStructLog
+	depth     int
+	hostCalls []*WasmHostCall
+	output    []byte
+	err       error
+}
+
+// NewWasmStructLogger returns a new WASM step logger.
+func NewWasmStructLogger(cfg *LogConfig) *WasmStructLogger {
+	logger := new(WasmStructLogger)
+	if cfg != nil {
+		logger.cfg = *cfg
+	}
+	return logger
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (l *WasmStructLogger) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the Tracer interface, EVM steps are not recorded.
+func (l *WasmStructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface, EVM faults are not recorded.
+func (l *WasmStructLogger) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (l *WasmStructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	l.output = output
+	l.err = err
+	return nil
+}
+
+// CaptureEnter implements the WasmTracer interface to track the invocation depth.
+func (l *WasmStructLogger) CaptureEnter(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	l.depth++
+}
+
+// CaptureExit implements the WasmTracer interface to track the invocation depth.
+func (l *WasmStructLogger) CaptureExit(output []byte, gasUsed uint64, err error) {
+	l.depth--
+}
+
+// CaptureStep records the state of the frame prior to executing an instruction.
+func (l *WasmStructLogger) CaptureStep(vm *exec.VirtualMachine, frame *exec.Frame, ip int, op opcodes.Opcode, gas uint64) {
+	// check if already accumulated the specified number of logs
+	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
+		return
+	}
+	log := WasmStructLog{
+		Depth:    l.depth,
+		Frame:    vm.CurrentFrame,
+		Function: frame.FunctionID,
+		Pc:       ip,
+		Op:       op.String(),
+		Gas:      gas,
+	}
+	if !l.cfg.DisableStack {
+		log.Stack = append([]int64{}, frame.Regs...)
+		log.Locals = append([]int64{}, frame.Locals...)
+	}
+	l.logs = append(l.logs, log)
+}
+
+// CaptureHostCall attaches the host function about to run to the log of the
+// import stub invoking it.
+func (l *WasmStructLogger) CaptureHostCall(vm *exec.VirtualMachine, module, field string, args []int64) {
+	call := &WasmHostCall{Module: module, Field: field, Args: append([]int64{}, args...)}
+	l.hostCalls = append(l.hostCalls, call)
+	if len(l.logs) > 0 && l.logs[len(l.logs)-1].Op == opcodes.InvokeImport.String() {
+		l.logs[len(l.logs)-1].HostCall = call
+	}
+}
+
+// CaptureHostReturn records the value returned by the last host function.
+func (l *WasmStructLogger) CaptureHostReturn(vm *exec.VirtualMachine, module, field string, ret int64) {
+	if len(l.hostCalls) == 0 {
+		return
+	}
+	l.hostCalls[len(l.hostCalls)-1].Return = &ret
+	l.hostCalls = l.hostCalls[:len(l.hostCalls)-1]
+}
+
+// StructLogs returns the captured log entries.
+func (l *WasmStructLogger) StructLogs() []WasmStructLog { return l.logs }
+
+// Error returns the VM error captured by the trace.
+func (l *WasmStructLogger) Error() error { return l.err }
+
+// Output returns the VM return value captured by the trace.
+func (l *WasmStructLogger) Output() []byte { return l.output }
+
+// WasmCallFrame is a single contract invocation of the call tree built by
+// WasmCallTracer.
+type WasmCallFrame struct {
+	Type    string           `json:"type"`
+	From    common.Address   `json:"from"`
+	To      common.Address   `json:"to"`
+	Value   *hexutil.Big     `json:"value,omitempty"`
+	Gas     hexutil.Uint64   `json:"gas"`
+	GasUsed hexutil.Uint64   `json:"gasUsed"`
+	Input   hexutil.Bytes    `json:"input"`
+	Output  hexutil.Bytes    `json:"output,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Calls   []*WasmCallFrame `json:"calls,omitempty"`
+}
+
+// WasmCallTracer builds the tree of contract invocations made by a transaction
+// and implements WasmTracer.
+type WasmCallTracer struct {
+	root    *WasmCallFrame
+	entered bool // Whether the interpreter entered the outermost call
+	stack   []*WasmCallFrame
+}
+
+// NewWasmCallTracer returns a new call tree tracer.
+func NewWasmCallTracer() *WasmCallTracer {
+	return new(WasmCallTracer)
+}
+
+// CaptureStart implements the Tracer interface to record the outermost call.
+func (t *WasmCallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := "CALL"
+	if create {
+		typ = "CREATE"
+	}
+	t.root = newWasmCallFrame(typ, from, to, input, gas, value)
+	return nil
+}
+
+// CaptureState implements the Tracer interface, EVM steps are not recorded.
+func (t *WasmCallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface, EVM faults are not recorded.
+func (t *WasmCallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface to finalize the outermost call.
+func (t *WasmCallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if t.root == nil {
+		return nil
+	}
+	t.root.finish(output, gasUsed, err)
+	return nil
+}
+
+// CaptureEnter implements the WasmTracer interface to open a new call frame.
+// The outermost invocation is the one already recorded by CaptureStart.
+func (t *WasmCallTracer) CaptureEnter(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.root != nil && !t.entered {
+		t.entered = true
+		t.stack = append(t.stack, t.root)
+		return
+	}
+	frame := newWasmCallFrame(typ, from, to, input, gas, value)
+	if len(t.stack) == 0 {
+		if t.root != nil {
+			return
+		}
+		t.root, t.entered = frame, true
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+// CaptureExit implements the WasmTracer interface to close the current call frame.
+func (t *WasmCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	t.stack[len(t.stack)-1].finish(output, gasUsed, err)
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// CaptureStep implements the exec.Tracer interface, steps are not recorded.
+func (t *WasmCallTracer) CaptureStep(vm *exec.VirtualMachine, frame *exec.Frame, ip int, op opcodes.Opcode, gas uint64) {
+}
+
+// CaptureHostCall implements the exec.Tracer interface, host calls are not
+// recorded beyond the invocations they open.
+func (t *WasmCallTracer) CaptureHostCall(vm *exec.VirtualMachine, module, field string, args []int64) {
+}
+
+// CaptureHostReturn implements the exec.Tracer interface.
+func (t *WasmCallTracer) CaptureHostReturn(vm *exec.VirtualMachine, module, field string, ret int64) {
+}
+
+// Result returns the root of the call tree.
+func (t *WasmCallTracer) Result() *WasmCallFrame { return t.root }
+
+func newWasmCallFrame(typ string, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) *WasmCallFrame {
+	frame := &WasmCallFrame{
+		Type:  typ,
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return frame
+}
+
+func (f *WasmCallFrame) finish(output []byte, gasUsed uint64, err error) {
+	f.Output = common.CopyBytes(output)
+	f.GasUsed = hexutil.Uint64(gasUsed)
+	if err != nil {
+		f.Error = err.Error()
+	}
+}
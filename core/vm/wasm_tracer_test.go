@@ -0,0 +1,39 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+)
+
+func TestWasmCallTracer(t *testing.T) {
+	var (
+		caller = common.HexToAddress("0x01")
+		a      = common.HexToAddress("0x0a")
+		b      = common.HexToAddress("0x0b")
+		c      = common.HexToAddress("0x0c")
+	)
+	tracer := NewWasmCallTracer()
+	tracer.CaptureStart(caller, a, false, []byte{1}, 1000, big.NewInt(0))
+	tracer.CaptureEnter("CALL", caller, a, []byte{1}, 1000, big.NewInt(0))
+	tracer.CaptureEnter("CALL", a, b, []byte{2}, 900, big.NewInt(0))
+	tracer.CaptureEnter("DELEGATECALL", b, c, []byte{3}, 800, big.NewInt(0))
+	tracer.CaptureExit([]byte{4}, 10, nil)
+	tracer.CaptureExit(nil, 100, errors.New("failed"))
+	tracer.CaptureExit([]byte{5}, 300, nil)
+	tracer.CaptureEnd([]byte{5}, 300, 0, nil)
+
+	root := tracer.Result()
+	if root == nil || root.To != a || uint64(root.GasUsed) != 300 || len(root.Calls) != 1 {
+		t.Fatalf("root frame mismatch: %+v", root)
+	}
+	nested := root.Calls[0]
+	if nested.Type != "CALL" || nested.To != b || nested.Error != "failed" || len(nested.Calls) != 1 {
+		t.Fatalf("nested frame mismatch: %+v", nested)
+	}
+	if inner := nested.Calls[0]; inner.Type != "DELEGATECALL" || inner.To != c || inner.Output[0] != 4 {
+		t.Fatalf("inner frame mismatch: %+v", inner)
+	}
+}
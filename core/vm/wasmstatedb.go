@@ -2,13 +2,35 @@ package vm
 
 import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/vm"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 )
 
+var (
+	errCloneEmptyCode   = errors.New("wasmstatedb: clone source has no code")
+	errContractMigrated = errors.New("wasmstatedb: contract has already been migrated")
+)
+
+// MigratedTo returns the address the contract at addr was migrated to, if it
+// was migrated. The forwarding records are kept in the storage of
+// vm.MigrationRecordAddr, keyed by the address of the migrated contract, out
+// of reach of the contract itself.
+func MigratedTo(db StateDB, addr common.Address) (common.Address, bool) {
+	to := db.GetState(vm.MigrationRecordAddr, addr.Bytes())
+	if len(to) != common.AddressLength {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(to), true
+}
+
 type WasmStateDB struct {
 	StateDB  StateDB
 	evm      *EVM
@@ -126,4 +148,67 @@ func (self *WasmStateDB) DelegateCall(addr, param []byte) ([]byte, error) {
 	return ret, err
 }
 
+// Create deploys code as a new contract created by the current contract.
+// The code is the same RLP([txType][code][abi]) payload accepted by a
+// contract-creation transaction. The nonce of the current contract is
+// used to derive the new address and is bumped by the EVM.
+func (self *WasmStateDB) Create(code []byte, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	_, addr, leftOverGas, err := self.evm.Create(self.contract, code, gas, value)
+	return addr, leftOverGas, err
+}
 
+// Clone deploys a new contract running the code already stored at addr.
+func (self *WasmStateDB) Clone(addr common.Address, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	code := self.evm.StateDB.GetCode(addr)
+	if len(code) == 0 {
+		return common.Address{}, gas, errCloneEmptyCode
+	}
+	return self.Create(code, value, gas)
+}
+
+// Migrate replaces the current contract with a new one deployed from code, the
+// same RLP([txType][code][abi]) payload accepted by Create. The storage and the
+// balance of the current contract are moved to the new contract, replacing
+// whatever its constructor stored, and a forwarding record is left for the
+// current contract after which it can no longer be called. If args is not
+// empty, it is the input of a call made to the new contract once the storage
+// is copied, e.g. to upgrade its layout. Every storage entry copied costs
+// MigrateEntryGas, charged before anything is copied, and a Migrated event is
+// emitted by the current contract.
+func (self *WasmStateDB) Migrate(code, args []byte, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	db := self.evm.StateDB
+	from := self.contract.Address()
+	if _, ok := MigratedTo(db, from); ok {
+		return common.Address{}, gas, errContractMigrated
+	}
+	entries := db.StorageEntries(from)
+	if entries > math.MaxUint64/params.MigrateEntryGas || gas < entries*params.MigrateEntryGas {
+		return common.Address{}, 0, ErrOutOfGas
+	}
+	gas -= entries * params.MigrateEntryGas
+
+	snapshot := db.Snapshot()
+	to, gas, err := self.Create(code, value, gas)
+	if err != nil {
+		return common.Address{}, gas, err
+	}
+	db.CopyStorage(from, to)
+	self.evm.Transfer(db, from, to, db.GetBalance(from))
+
+	if len(args) > 0 {
+		if _, gas, err = self.evm.Call(self.contract, to, args, gas, new(big.Int)); err != nil {
+			db.RevertToSnapshot(snapshot)
+			return common.Address{}, gas, err
+		}
+	}
+	// An account without nonce, balance and code is removed with its storage
+	if db.GetNonce(vm.MigrationRecordAddr) == 0 {
+		db.SetNonce(vm.MigrationRecordAddr, 1)
+	}
+	db.SetState(vm.MigrationRecordAddr, from.Bytes(), to.Bytes())
+
+	data, _ := rlp.EncodeToBytes([][]byte{common.Uint64ToBytes(entries)})
+	topics := []common.Hash{crypto.Keccak256Hash([]byte("Migrated")), common.BytesToHash(to.Bytes())}
+	self.AddLog(from, topics, data, self.evm.BlockNumber.Uint64())
+	return to, gas, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+)
+
+const (
+	// addrIndexThrottling is the time to wait between processing two consecutive
+	// address index sections, preventing disk overload while catching up.
+	addrIndexThrottling = 100 * time.Millisecond
+)
+
+// AddressIndexer implements a core.ChainIndexer, building up an index from
+// addresses to the positions of the canonical transactions they took part in
+// as sender, recipient or delegation target.
+//
+// Entries are stored per section and keyed by the section head, so the entries
+// of a section that was reorged away are never read back.
+type AddressIndexer struct {
+	db      ethdb.Database      // database instance to write index data and metadata into
+	config  *params.ChainConfig // chain config to derive the transaction signers from
+	size    uint64              // section size to generate the index for
+	section uint64              // Section is the section number being processed currently
+	head    common.Hash         // Head is the hash of the last header processed
+	entries map[common.Address][]rawdb.AddressTxEntry
+}
+
+// NewAddressIndexer returns a chain indexer that maintains the address
+// transaction index for the canonical chain.
+func NewAddressIndexer(db ethdb.Database, config *params.ChainConfig, size, confirms uint64) *core.ChainIndexer {
+	backend := &AddressIndexer{
+		db:     db,
+		config: config,
+		size:   size,
+	}
+	table := ethdb.NewTable(db, string(rawdb.AddressTxIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, size, confirms, addrIndexThrottling, "addrindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new address index
+// section.
+func (a *AddressIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	a.section, a.head = section, common.Hash{}
+	a.entries = make(map[common.Address][]rawdb.AddressTxEntry)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the transactions of a new
+// header's block into the index.
+func (a *AddressIndexer) Process(ctx context.Context, header *types.Header) error {
+	number, hash := header.Number.Uint64(), header.Hash()
+	body := rawdb.ReadBody(a.db, hash, number)
+	if body == nil && number < rawdb.ReadOldestRetainedBlock(a.db) {
+		// The history was pruned before the section got indexed
+		a.head = hash
+		return nil
+	}
+	if body == nil {
+		return fmt.Errorf("block body #%d [%x…] not found", number, hash[:4])
+	}
+	signer := types.MakeSigner(a.config, header.Number)
+	for i, tx := range body.Transactions {
+		addrs, err := core.TxAddresses(signer, tx)
+		if err != nil {
+			return err
+		}
+		entry := rawdb.AddressTxEntry{BlockNumber: number, Index: uint64(i)}
+		for _, addr := range addrs {
+			a.entries[addr] = append(a.entries[addr], entry)
+		}
+	}
+	a.head = hash
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the entries of the
+// section into the database.
+func (a *AddressIndexer) Commit() error {
+	batch := a.db.NewBatch()
+	for addr, entries := range a.entries {
+		rawdb.WriteAddressTxEntries(batch, addr, a.section, a.head, entries)
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -24,6 +25,7 @@ import (
 	"io"
 	"math/big"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/PlatONnetwork/PlatON-Go/common"
@@ -32,6 +34,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/state"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/internal/ethapi"
 	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
@@ -300,6 +303,161 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+// WasmStorageRangeResult is the result of a debug_wasmStorageRangeAt API call.
+type WasmStorageRangeResult struct {
+	Storage []WasmStorageEntry `json:"storage"`
+	NextKey *common.Hash       `json:"nextKey"` // nil if Storage includes the last key in the trie.
+}
+
+// WasmStorageEntry is a storage slot of a WASM contract. Unlike EVM slots,
+// keys and values are arbitrary byte strings.
+type WasmStorageEntry struct {
+	Hash  common.Hash   `json:"hash"`
+	Key   hexutil.Bytes `json:"key"` // nil if the preimage of the hash is unknown.
+	Value hexutil.Bytes `json:"value"`
+}
+
+// WasmStorageRangeAt returns the storage of a WASM contract at the given block
+// hash and transaction index, with keys and values left unpadded.
+func (api *PrivateDebugAPI) WasmStorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (WasmStorageRangeResult, error) {
+	_, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0)
+	if err != nil {
+		return WasmStorageRangeResult{}, err
+	}
+	st := statedb.StorageTrie(contractAddress)
+	if st == nil {
+		return WasmStorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
+	}
+	return wasmStorageRangeAt(st, keyStart, maxResult)
+}
+
+func wasmStorageRangeAt(st state.Trie, start []byte, maxResult int) (WasmStorageRangeResult, error) {
+	it := trie.NewIterator(st.NodeIterator(start))
+	result := WasmStorageRangeResult{Storage: []WasmStorageEntry{}}
+	for i := 0; i < maxResult && it.Next(); i++ {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return WasmStorageRangeResult{}, err
+		}
+		result.Storage = append(result.Storage, WasmStorageEntry{
+			Hash:  common.BytesToHash(it.Key),
+			Key:   st.GetKey(it.Key),
+			Value: storageValue(st, content),
+		})
+	}
+	// Add the 'next key' so clients can continue downloading.
+	if it.Next() {
+		next := common.BytesToHash(it.Key)
+		result.NextKey = &next
+	}
+	return result, nil
+}
+
+// storageValue resolves the value key stored in a storage trie leaf to the
+// value itself, which is kept as a blob beside the trie nodes.
+func storageValue(st state.Trie, valueKey []byte) []byte {
+	if value := st.GetKey(valueKey); value != nil {
+		return value
+	}
+	return valueKey
+}
+
+// StorageDiffEntry is a storage slot that differs between two blocks. Before
+// is nil for a slot created in between, After for a slot that was cleared.
+type StorageDiffEntry struct {
+	Key    hexutil.Bytes `json:"key"`
+	Before hexutil.Bytes `json:"before"`
+	After  hexutil.Bytes `json:"after"`
+}
+
+// StorageDiff returns the storage slots of a contract, either WASM or EVM, that
+// changed between the two blocks specified, sorted by key.
+func (api *PrivateDebugAPI) StorageDiff(contractAddress common.Address, startNum uint64, endNum uint64) ([]StorageDiffEntry, error) {
+	if startNum >= endNum {
+		return nil, fmt.Errorf("start block height (%d) must be less than end block height (%d)", startNum, endNum)
+	}
+	startBlock := api.eth.blockchain.GetBlockByNumber(startNum)
+	if startBlock == nil {
+		return nil, fmt.Errorf("start block %d not found", startNum)
+	}
+	endBlock := api.eth.blockchain.GetBlockByNumber(endNum)
+	if endBlock == nil {
+		return nil, fmt.Errorf("end block %d not found", endNum)
+	}
+	oldTrie, err := api.storageTrieAt(startBlock, contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	newTrie, err := api.storageTrieAt(endBlock, contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	return storageDiff(oldTrie, newTrie)
+}
+
+// storageTrieAt opens the storage trie of a contract at the given block, or an
+// empty trie if the contract doesn't exist yet.
+func (api *PrivateDebugAPI) storageTrieAt(block *types.Block, addr common.Address) (state.Trie, error) {
+	statedb, err := api.eth.blockchain.StateAt(block.Root())
+	if err != nil {
+		return nil, err
+	}
+	if st := statedb.StorageTrie(addr); st != nil {
+		return st, nil
+	}
+	return statedb.Database().OpenStorageTrie(crypto.Keccak256Hash(addr.Bytes()), common.Hash{})
+}
+
+func storageDiff(oldTrie, newTrie state.Trie) ([]StorageDiffEntry, error) {
+	// Walk the difference both ways: leaves only in the new trie are created or
+	// changed slots, leaves only in the old trie are changed or cleared ones.
+	seen := make(map[string]bool)
+	var keys [][]byte
+	for _, pair := range [][2]state.Trie{{oldTrie, newTrie}, {newTrie, oldTrie}} {
+		diff, _ := trie.NewDifferenceIterator(pair[0].NodeIterator(nil), pair[1].NodeIterator(nil))
+		iter := trie.NewIterator(diff)
+		for iter.Next() {
+			if seen[string(iter.Key)] {
+				continue
+			}
+			seen[string(iter.Key)] = true
+			key := pair[1].GetKey(iter.Key)
+			if key == nil {
+				return nil, fmt.Errorf("no preimage found for hash %x", iter.Key)
+			}
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	diffs := make([]StorageDiffEntry, 0, len(keys))
+	for _, key := range keys {
+		before, err := storageValueAt(oldTrie, key)
+		if err != nil {
+			return nil, err
+		}
+		after, err := storageValueAt(newTrie, key)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, StorageDiffEntry{Key: key, Before: before, After: after})
+	}
+	return diffs, nil
+}
+
+// storageValueAt returns the value stored under a key, nil if it is not set.
+func storageValueAt(st state.Trie, key []byte) ([]byte, error) {
+	enc, err := st.TryGet(key)
+	if err != nil || len(enc) == 0 {
+		return nil, err
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return nil, err
+	}
+	return storageValue(st, content), nil
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
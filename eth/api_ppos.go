@@ -0,0 +1,253 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rpc"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	"github.com/PlatONnetwork/PlatON-Go/x/plugin"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+	"github.com/PlatONnetwork/PlatON-Go/x/xutil"
+)
+
+var (
+	errPendingPPOS   = errors.New("ppos data of the pending block is not available")
+	errPrunedPPOS    = errors.New("ppos data of blocks before the last committed block is not available")
+	errBlockNotFound = errors.New("block not found")
+)
+
+// PublicPPOSAPI provides read access to the staking, governance and
+// restricting data kept by the PPOS plugins. The data kept in the state is
+// available as of any block, the data kept in the snapshot db only as of the
+// last block committed to it and the blocks after it.
+type PublicPPOSAPI struct {
+	b   *EthAPIBackend
+	sdb snapshotdb.DB
+}
+
+// NewPublicPPOSAPI creates a new PPOS API.
+func NewPublicPPOSAPI(b *EthAPIBackend) *PublicPPOSAPI {
+	return &PublicPPOSAPI{b: b, sdb: snapshotdb.Instance()}
+}
+
+// header resolves the block the query runs against. The pending block is
+// refused since the plugins only hold the data of sealed blocks.
+func (api *PublicPPOSAPI) header(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	if blockNr, ok := blockNrOrHash.Number(); ok && blockNr == rpc.PendingBlockNumber {
+		return nil, errPendingPPOS
+	}
+	header, err := api.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errBlockNotFound
+	}
+	return header, nil
+}
+
+// snapshotHeader resolves the block a query of the snapshot db runs against.
+// Once blocks are committed, the snapshot db only keeps the data of the last
+// one, whatever block hash it is read with, so the blocks before it are
+// refused instead of being answered with newer data.
+func (api *PublicPPOSAPI) snapshotHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header.Number.Cmp(api.sdb.GetCurrent().GetHighest(true).Num) < 0 {
+		return nil, errPrunedPPOS
+	}
+	return header, nil
+}
+
+// stateAndHeader resolves the block the query runs against along with its state.
+func (api *PublicPPOSAPI) stateAndHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	statedb, err := api.b.eth.BlockChain().StateAt(header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return statedb, header, nil
+}
+
+// GetCandidate returns the candidate of the node, or nil if it has none.
+func (api *PublicPPOSAPI) GetCandidate(ctx context.Context, nodeId discover.NodeID, blockNrOrHash rpc.BlockNumberOrHash) (*staking.CandidateHex, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := xutil.NodeId2Addr(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	can, err := plugin.StakingInstance().GetCandidateCompactInfo(header.Hash(), header.Number.Uint64(), addr)
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return nil, nil
+	}
+	return can, err
+}
+
+// GetCandidateList returns all the candidates.
+func (api *PublicPPOSAPI) GetCandidateList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (staking.CandidateHexQueue, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	list, err := plugin.StakingInstance().GetCandidateList(header.Hash(), header.Number.Uint64())
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return staking.CandidateHexQueue{}, nil
+	}
+	return list, err
+}
+
+// GetVerifierList returns the verifiers of the settlement epoch of the block.
+func (api *PublicPPOSAPI) GetVerifierList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (staking.ValidatorExQueue, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.StakingInstance().GetVerifierList(header.Hash(), header.Number.Uint64(), plugin.QueryStartIrr)
+}
+
+// GetValidatorList returns the validators of the consensus round of the block.
+func (api *PublicPPOSAPI) GetValidatorList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (staking.ValidatorExQueue, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.StakingInstance().GetValidatorList(header.Hash(), header.Number.Uint64(), plugin.CurrentRound, plugin.QueryStartIrr)
+}
+
+// GetDelegations returns every delegation made by the account.
+func (api *PublicPPOSAPI) GetDelegations(ctx context.Context, addr common.Address, blockNrOrHash rpc.BlockNumberOrHash) ([]*staking.DelegationEx, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		stk         = plugin.StakingInstance()
+		blockHash   = header.Hash()
+		blockNumber = header.Number.Uint64()
+	)
+	related, err := stk.GetRelatedListByDelAddr(blockHash, addr)
+	if err != nil && !snapshotdb.IsDbNotFoundErr(err) {
+		return nil, err
+	}
+	delegations := make([]*staking.DelegationEx, 0, len(related))
+	for _, rel := range related {
+		del, err := stk.GetDelegateExCompactInfo(blockHash, blockNumber, addr, rel.NodeId, rel.StakingBlockNum)
+		if snapshotdb.IsDbNotFoundErr(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, del)
+	}
+	return delegations, nil
+}
+
+// GetProposal returns the governance proposal, or nil if there is none with the id.
+func (api *PublicPPOSAPI) GetProposal(ctx context.Context, proposalID common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (gov.Proposal, error) {
+	statedb, _, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return gov.GetProposal(proposalID, statedb)
+}
+
+// ListProposal returns the proposals being voted on, the ended ones and the
+// one waiting for activation.
+func (api *PublicPPOSAPI) ListProposal(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]gov.Proposal, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.b.eth.BlockChain().StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	return gov.ListProposal(header.Hash(), statedb)
+}
+
+// GetTallyResult returns the tally of the proposal, or nil if its voting has
+// not ended yet.
+func (api *PublicPPOSAPI) GetTallyResult(ctx context.Context, proposalID common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*gov.TallyResult, error) {
+	statedb, _, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return gov.GetTallyResult(proposalID, statedb)
+}
+
+// GetActiveVersion returns the version active on the chain.
+func (api *PublicPPOSAPI) GetActiveVersion(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (uint32, error) {
+	statedb, _, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return 0, err
+	}
+	return gov.GetCurrentActiveVersion(statedb), nil
+}
+
+// GetGovernParam returns the value of the govern parameter.
+func (api *PublicPPOSAPI) GetGovernParam(ctx context.Context, module, name string, blockNrOrHash rpc.BlockNumberOrHash) (string, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return "", err
+	}
+	return gov.GetGovernParamValue(module, name, header.Number.Uint64(), header.Hash())
+}
+
+// ListGovernParam returns the govern parameters of the module, or all of
+// them if module is empty.
+func (api *PublicPPOSAPI) ListGovernParam(ctx context.Context, module string, blockNrOrHash rpc.BlockNumberOrHash) ([]*gov.GovernParam, error) {
+	header, err := api.snapshotHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return gov.ListGovernParam(module, header.Hash())
+}
+
+// GetRestrictingInfo returns the restricting plans of the account, or nil if
+// it has none.
+func (api *PublicPPOSAPI) GetRestrictingInfo(ctx context.Context, account common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*restricting.Result, error) {
+	statedb, _, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	result, bizErr := plugin.RestrictingInstance().GetRestrictingInfo(account, statedb)
+	if bizErr != nil {
+		if bizErr.Code == restricting.ErrAccountNotFound.Code {
+			return nil, nil
+		}
+		return nil, bizErr
+	}
+	return result, nil
+}
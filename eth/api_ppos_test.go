@@ -0,0 +1,93 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
+	"github.com/PlatONnetwork/PlatON-Go/eth/downloader"
+	"github.com/PlatONnetwork/PlatON-Go/rpc"
+)
+
+// newTestPPOSAPI creates a PPOS API on a chain of the given length whose
+// snapshot db has the blocks up to committed committed.
+func newTestPPOSAPI(t *testing.T, blocks, committed int) (*PublicPPOSAPI, func()) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, blocks, nil, nil)
+	sdb, err := snapshotdb.Open(path.Join(os.TempDir(), fmt.Sprint(rand.Int63())), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open snapshot db: %v", err)
+	}
+	for i := 1; i <= committed; i++ {
+		header := pm.blockchain.GetHeaderByNumber(uint64(i))
+		if err := sdb.NewBlock(header.Number, header.ParentHash, header.Hash()); err != nil {
+			t.Fatalf("failed to add block %d: %v", i, err)
+		}
+		if err := sdb.Commit(header.Hash()); err != nil {
+			t.Fatalf("failed to commit block %d: %v", i, err)
+		}
+	}
+	api := &PublicPPOSAPI{b: &EthAPIBackend{eth: &Ethereum{blockchain: pm.blockchain}}, sdb: sdb}
+	return api, func() {
+		pm.Stop()
+		sdb.Clear()
+	}
+}
+
+func TestPPOSAPIBlocks(t *testing.T) {
+	api, done := newTestPPOSAPI(t, 4, 2)
+	defer done()
+
+	var (
+		ctx     = context.Background()
+		pending = rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+		missing = rpc.BlockNumberOrHashWithNumber(10)
+		pruned  = rpc.BlockNumberOrHashWithNumber(1)
+		byHash  = rpc.BlockNumberOrHashWithHash(api.b.eth.blockchain.GetHeaderByNumber(1).Hash())
+	)
+	for _, test := range []struct {
+		at   rpc.BlockNumberOrHash
+		want error
+	}{
+		{pending, errPendingPPOS},
+		{missing, errBlockNotFound},
+		{pruned, errPrunedPPOS},
+		{byHash, errPrunedPPOS},
+	} {
+		if _, err := api.GetCandidateList(ctx, test.at); err != test.want {
+			t.Errorf("candidate list error mismatch: have %v, want %v", err, test.want)
+		}
+		if _, err := api.GetDelegations(ctx, common.Address{1}, test.at); err != test.want {
+			t.Errorf("delegations error mismatch: have %v, want %v", err, test.want)
+		}
+		if _, err := api.ListProposal(ctx, test.at); err != test.want {
+			t.Errorf("proposal list error mismatch: have %v, want %v", err, test.want)
+		}
+		if _, err := api.ListGovernParam(ctx, "", test.at); err != test.want {
+			t.Errorf("govern param list error mismatch: have %v, want %v", err, test.want)
+		}
+	}
+
+	// The data kept in the state is available as of any block
+	if _, err := api.GetActiveVersion(ctx, pruned); err != nil {
+		t.Errorf("active version of a pruned block failed: %v", err)
+	}
+
+	// The last committed block and the blocks after it are served
+	for _, at := range []rpc.BlockNumberOrHash{
+		rpc.BlockNumberOrHashWithNumber(2),
+		rpc.BlockNumberOrHashWithNumber(3),
+		rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber),
+	} {
+		if _, err := api.GetCandidateList(ctx, at); err != nil {
+			t.Errorf("candidate list of block %v failed: %v", at, err)
+		}
+		if _, err := api.GetDelegations(ctx, common.Address{1}, at); err != nil {
+			t.Errorf("delegations of block %v failed: %v", at, err)
+		}
+	}
+}
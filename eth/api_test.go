@@ -17,7 +17,14 @@
 package eth
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 )
 
 func TestStorageRangeAt(t *testing.T) {
@@ -81,3 +88,104 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}*/
 }
+
+// commitStorage writes storage to the account addr of the state at root and
+// returns the state reopened from the committed root.
+func commitStorage(t *testing.T, db ethdb.Database, root common.Hash, addr common.Address, storage map[string]string) *state.StateDB {
+	statedb, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb.SetNonce(addr, 1)
+	for key, value := range storage {
+		statedb.SetState(addr, []byte(key), []byte(value))
+	}
+	if root, err = statedb.Commit(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false, true); err != nil {
+		t.Fatal(err)
+	}
+	statedb, err = state.New(root, state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return statedb
+}
+
+func TestWasmStorageRangeAt(t *testing.T) {
+	var (
+		addr    = common.Address{0x01}
+		storage = map[string]string{"a": "1", "bb": "22", "ccc": "333"}
+		statedb = commitStorage(t, ethdb.NewMemDatabase(), common.Hash{}, addr, storage)
+	)
+	result, err := wasmStorageRangeAt(statedb.StorageTrie(addr), nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Storage) != len(storage) || result.NextKey != nil {
+		t.Fatalf("wrong result: %d entries, next key %v", len(result.Storage), result.NextKey)
+	}
+	for _, entry := range result.Storage {
+		if want := crypto.Keccak256Hash(entry.Key); entry.Hash != want {
+			t.Errorf("hash mismatch for key %q: have %x, want %x", entry.Key, entry.Hash, want)
+		}
+		if want := storage[string(entry.Key)]; string(entry.Value) != want {
+			t.Errorf("value mismatch for key %q: have %q, want %q", entry.Key, entry.Value, want)
+		}
+	}
+
+	// Pages continue at the next key
+	first, err := wasmStorageRangeAt(statedb.StorageTrie(addr), nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Storage) != 1 || first.NextKey == nil || *first.NextKey != result.Storage[1].Hash {
+		t.Fatalf("wrong first page: %d entries, next key %v", len(first.Storage), first.NextKey)
+	}
+	rest, err := wasmStorageRangeAt(statedb.StorageTrie(addr), first.NextKey.Bytes(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(append(first.Storage, rest.Storage...), result.Storage) {
+		t.Fatalf("pages mismatch: have %v and %v, want %v", first.Storage, rest.Storage, result.Storage)
+	}
+
+	// The EVM range keeps returning the value keys of the trie leaves
+	evm, err := storageRangeAt(statedb.StorageTrie(addr), nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range result.Storage {
+		if have, want := evm.Storage[entry.Hash].Value, crypto.Keccak256Hash(entry.Value); have != want {
+			t.Errorf("storage range value mismatch for key %q: have %x, want %x", entry.Key, have, want)
+		}
+	}
+}
+
+func TestStorageDiff(t *testing.T) {
+	var (
+		db   = ethdb.NewMemDatabase()
+		addr = common.Address{0x01}
+	)
+	before := commitStorage(t, db, common.Hash{}, addr, map[string]string{"a": "1", "b": "2", "d": "4"})
+	after := commitStorage(t, db, before.IntermediateRoot(true), addr, map[string]string{"b": "3", "c": "5", "d": ""})
+
+	diffs, err := storageDiff(before.StorageTrie(addr), after.StorageTrie(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StorageDiffEntry{
+		{Key: hexutil.Bytes("b"), Before: hexutil.Bytes("2"), After: hexutil.Bytes("3")},
+		{Key: hexutil.Bytes("c"), After: hexutil.Bytes("5")},
+		{Key: hexutil.Bytes("d"), Before: hexutil.Bytes("4")},
+	}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Fatalf("diff mismatch:\nhave %v\nwant %v", diffs, want)
+	}
+
+	// An unchanged storage has no diff
+	if diffs, err := storageDiff(after.StorageTrie(addr), after.StorageTrie(addr)); err != nil || len(diffs) != 0 {
+		t.Fatalf("unexpected diff %v (%v)", diffs, err)
+	}
+}
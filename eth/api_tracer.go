@@ -22,7 +22,9 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,6 +38,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/eth/tracers"
 	"github.com/PlatONnetwork/PlatON-Go/internal/ethapi"
 	"github.com/PlatONnetwork/PlatON-Go/log"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 	"github.com/PlatONnetwork/PlatON-Go/rlp"
 	"github.com/PlatONnetwork/PlatON-Go/rpc"
 	"github.com/PlatONnetwork/PlatON-Go/trie"
@@ -50,6 +53,10 @@ const (
 	// and reexecute to produce missing historical state necessary to run a specific
 	// trace.
 	defaultTraceReexec = uint64(128)
+
+	// wasmCallTracer is the name of the tracer building the call tree of WASM
+	// contract invocations.
+	wasmCallTracer = "wasmCallTracer"
 )
 
 // TraceConfig holds extra parameters to trace functions.
@@ -192,7 +199,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 			// Fetch and execute the next block trace tasks
 			for task := range tasks {
-				signer := types.NewEIP155Signer(api.config.ChainID)
+				signer := types.MakeSigner(api.config, task.block.Number())
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
 					msg, _ := tx.AsMessage(signer)
@@ -412,7 +419,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	}
 	// Execute all the transaction contained within the block concurrently
 	var (
-		signer  = types.NewEIP155Signer(api.config.ChainID)
+		signer  = types.MakeSigner(api.config, block.Number())
 		txs     = block.Transactions()
 		results = make([]*txTraceResult, len(txs))
 
@@ -559,6 +566,53 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
 
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of the call on top of the state of the given
+// block and returns them as a JSON object.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args ethapi.CallArgs, blockNr rpc.BlockNumber, config *TraceConfig) (interface{}, error) {
+	// Fetch the block and the state that we want to trace on
+	var (
+		block   *types.Block
+		statedb *state.StateDB
+		err     error
+	)
+	if blockNr == rpc.PendingBlockNumber {
+		block, statedb = api.eth.miner.Pending()
+	} else {
+		if blockNr == rpc.LatestBlockNumber {
+			block = api.eth.blockchain.CurrentBlock()
+		} else {
+			block = api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", blockNr)
+		}
+		reexec := defaultTraceReexec
+		if config != nil && config.Reexec != nil {
+			reexec = *config.Reexec
+		}
+		if statedb, err = api.computeStateDB(block, reexec); err != nil {
+			return nil, err
+		}
+	}
+	if block == nil || statedb == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	// Assemble the call message the same way eth_call does
+	gas := uint64(args.Gas)
+	if gas == 0 {
+		gas = block.GasLimit()
+	}
+	gasPrice := args.GasPrice.ToInt()
+	if gasPrice.Sign() == 0 {
+		gasPrice = new(big.Int).SetUint64(params.GVon)
+	}
+	msg := types.NewMessage(args.From, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+	vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
+
+	return api.traceTx(ctx, msg, vmctx, statedb, config)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
@@ -569,6 +623,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		err    error
 	)
 	switch {
+	case config != nil && config.Tracer != nil && *config.Tracer == wasmCallTracer:
+		tracer = vm.NewWasmCallTracer()
+
 	case config != nil && config.Tracer != nil:
 		// Define a meaningful timeout of a single transaction trace
 		timeout := defaultTraceTimeout
@@ -589,6 +646,14 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		}()
 		defer cancel()
 
+	case !strings.EqualFold(api.config.VMInterpreter, "evm"):
+		// Contracts run in the WASM interpreter, log its steps instead
+		var logConfig *vm.LogConfig
+		if config != nil {
+			logConfig = config.LogConfig
+		}
+		tracer = vm.NewWasmStructLogger(logConfig)
+
 	case config == nil:
 		tracer = vm.NewStructLogger(nil)
 
@@ -612,6 +677,17 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
+	case *vm.WasmStructLogger:
+		return &ethapi.WasmExecutionResult{
+			Gas:         gas,
+			Failed:      failed,
+			ReturnValue: fmt.Sprintf("%x", ret),
+			StructLogs:  tracer.StructLogs(),
+		}, nil
+
+	case *vm.WasmCallTracer:
+		return tracer.Result(), nil
+
 	case *tracers.Tracer:
 		return tracer.GetResult()
 
@@ -636,7 +712,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 		return nil, vm.Context{}, nil, err
 	}
 	// Recompute transactions up to the target index.
-	signer := types.NewEIP155Signer(api.config.ChainID)
+	signer := types.MakeSigner(api.config, block.Number())
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
 		msg, _ := tx.AsMessage(signer)
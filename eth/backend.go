@@ -40,6 +40,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/validator"
 	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/bloombits"
+	"github.com/PlatONnetwork/PlatON-Go/core/lru"
 	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/core/vm"
@@ -93,6 +94,7 @@ type Ethereum struct {
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
+	addrIndexer   *core.ChainIndexer             // Address transaction indexer, nil if disabled
 
 	APIBackend *EthAPIBackend
 
@@ -124,12 +126,20 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		config.MinerGasPrice = new(big.Int).Set(DefaultConfig.MinerGasPrice)
 	}
 	// Assemble the Ethereum object
-	chainDb, err := CreateDB(ctx, config, "chaindata")
+	chainDb, err := CreateDB(ctx, config, "chaindata", config.DatabaseFreezer)
 	if err != nil {
 		return nil, err
 	}
 	snapshotdb.SetDBOptions(config.DatabaseCache, config.DatabaseHandles)
 
+	// Keep compiled wasm modules across restarts, unless running in memory.
+	if dataDir := ctx.ResolvePath(""); dataDir != "" {
+		if err := lru.SetWasmDB(dataDir); err != nil {
+			return nil, err
+		}
+		lru.WasmCache().SetMaxDiskSize(config.WasmCacheDiskSize)
+	}
+
 	chainConfig, _, genesisErr := core.SetupGenesisBlock(chainDb, ctx.ResolvePath(snapshotdb.DBPath), config.Genesis)
 
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
@@ -156,6 +166,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 	}
+	if config.AddressIndex {
+		eth.addrIndexer = NewAddressIndexer(chainDb, chainConfig, params.AddressIndexBlocks, params.AddressIndexConfirms)
+	}
 
 	log.Info("Initialising PlatON protocol", "versions", ProtocolVersions, "network", config.NetworkId)
 
@@ -174,7 +187,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			BodyCacheLimit: config.BodyCacheLimit, BlockCacheLimit: config.BlockCacheLimit,
 			MaxFutureBlocks: config.MaxFutureBlocks, BadBlockLimit: config.BadBlockLimit,
 			TriesInMemory: config.TriesInMemory, DBGCInterval: config.DBGCInterval, DBGCTimeout: config.DBGCTimeout,
-			DBGCMpt: config.DBGCMpt, DBGCBlock: config.DBGCBlock,
+			DBGCMpt: config.DBGCMpt, DBGCBlock: config.DBGCBlock, HistoryBlocks: config.HistoryBlocks,
 		}
 
 		minningConfig = &core.MiningConfig{MiningLogAtDepth: config.MiningLogAtDepth, TxChanSize: config.TxChanSize,
@@ -203,10 +216,16 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		//rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
+	if eth.addrIndexer != nil {
+		eth.addrIndexer.Start(eth.blockchain)
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.Snapshot != "" {
+		config.TxPool.Snapshot = ctx.ResolvePath(config.TxPool.Snapshot)
+	}
 	//eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, eth.blockchain)
 	eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, blockChainCache)
 
@@ -326,14 +345,15 @@ func recoverSnapshotDB(blockChainCache *core.BlockChainCache) error {
 	return nil
 }
 
-// CreateDB creates the chain database.
-func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Database, error) {
-	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
+// CreateDB creates the chain database, with an ancient store in the freezer
+// directory unless it is empty.
+func CreateDB(ctx *node.ServiceContext, config *Config, name string, freezer string) (ethdb.Database, error) {
+	db, err := ctx.OpenDatabaseWithFreezer(name, config.DatabaseCache, config.DatabaseHandles, freezer)
 	if err != nil {
 		return nil, err
 	}
-	if db, ok := db.(*ethdb.LDBDatabase); ok {
-		db.Meter("eth/db/chaindata/")
+	if ldb := rawdb.LevelDB(db); ldb != nil {
+		ldb.Meter("eth/db/chaindata/")
 	}
 	return db, nil
 }
@@ -387,6 +407,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   NewPrivateDebugAPI(s.chainConfig, s),
+		}, {
+			Namespace: "ppos",
+			Version:   "1.0",
+			Service:   NewPublicPPOSAPI(s.APIBackend),
+			Public:    true,
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -548,6 +573,9 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	if s.addrIndexer != nil {
+		s.addrIndexer.Close()
+	}
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	s.engine.Close()
@@ -559,6 +587,7 @@ func (s *Ethereum) Stop() error {
 	s.eventMux.Stop()
 
 	core.GetReactorInstance().Close()
+	lru.WasmCache().Close()
 	s.chainDb.Close()
 	close(s.shutdownChan)
 	return nil
@@ -27,6 +27,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
 	"github.com/PlatONnetwork/PlatON-Go/consensus/cbft/types"
 	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/lru"
 	"github.com/PlatONnetwork/PlatON-Go/eth/downloader"
 	"github.com/PlatONnetwork/PlatON-Go/eth/gasprice"
 )
@@ -48,12 +49,13 @@ var DefaultConfig = Config{
 		Period:            20000,
 		Amount:            10,
 	},
-	NetworkId:     1,
-	LightPeers:    100,
-	DatabaseCache: 768,
-	TrieCache:     256,
-	TrieTimeout:   60 * time.Minute,
-	MinerGasFloor: params.GenesisGasLimit,
+	NetworkId:         1,
+	LightPeers:        100,
+	DatabaseCache:     768,
+	TrieCache:         256,
+	WasmCacheDiskSize: lru.DefaultWasmCacheDiskSize,
+	TrieTimeout:       60 * time.Minute,
+	MinerGasFloor:     params.GenesisGasLimit,
 	//MinerGasCeil:  4000 * 21000 * 1.2,
 	DBDisabledGC:  false,
 	DBGCInterval:  86400,
@@ -116,6 +118,10 @@ type Config struct {
 	SyncMode  downloader.SyncMode
 	NoPruning bool
 
+	// AddressIndex enables the index from addresses to the transactions they
+	// took part in, served by platon_getTransactionsByAddress.
+	AddressIndex bool
+
 	// Light client options
 	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
@@ -124,6 +130,7 @@ type Config struct {
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
+	DatabaseFreezer    string // Directory of the ancient store, relative to the chain database; empty disables it
 	TrieCache          int
 	TrieTimeout        time.Duration
 	DBDisabledGC       bool
@@ -132,6 +139,14 @@ type Config struct {
 	DBGCMpt            bool
 	DBGCBlock          uint64
 
+	// HistoryBlocks is the number of recent blocks whose bodies and receipts
+	// are retained, older history is pruned. 0 keeps all.
+	HistoryBlocks uint64
+
+	// WasmCacheDiskSize is the number of bytes of compiled wasm modules kept
+	// on disk across restarts.
+	WasmCacheDiskSize int64
+
 	// Mining-related options
 	MinerExtraData []byte `toml:",omitempty"`
 	MinerGasFloor  uint64
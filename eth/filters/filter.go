@@ -24,6 +24,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/core"
 	"github.com/PlatONnetwork/PlatON-Go/core/bloombits"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/types"
 	"github.com/PlatONnetwork/PlatON-Go/ethdb"
 	"github.com/PlatONnetwork/PlatON-Go/event"
@@ -126,6 +127,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		if header == nil {
 			return nil, errors.New("unknown block")
 		}
+		if f.pruned(header.Number.Uint64(), header.Number.Uint64()) && rawdb.ReadCanonicalHash(f.db, header.Number.Uint64()) == f.block {
+			return nil, core.ErrHistoryPruned
+		}
 		return f.blockLogs(ctx, header)
 	}
 	// Figure out the limits of the filter range
@@ -142,6 +146,10 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
+	// Limits other than the latest block are not resolved to canonical blocks
+	if f.begin >= 0 && f.pruned(uint64(f.begin), end) {
+		return nil, core.ErrHistoryPruned
+	}
 	// Gather all indexed logs, and finish with non indexed ones
 	var (
 		logs []*types.Log
@@ -163,6 +171,13 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	return logs, err
 }
 
+// pruned reports whether history expiry removed the receipts of any canonical
+// block in the given range. The genesis block is never pruned.
+func (f *Filter) pruned(begin, end uint64) bool {
+	oldest := rawdb.ReadOldestRetainedBlock(f.db)
+	return begin < oldest && end > 0 && oldest > 1
+}
+
 // indexedLogs returns the logs matching the filter criteria based on the bloom
 // bits indexed available locally or via the network.
 func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
@@ -237,3 +237,41 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+func TestFilterPrunedHistory(t *testing.T) {
+	var (
+		db         = ethdb.NewMemDatabase()
+		mux        = new(event.TypeMux)
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+	)
+	genesis := core.GenesisBlockForTesting(db, common.Address{1}, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, cbft.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	rawdb.WriteOldestRetainedBlock(db, 5)
+
+	for _, test := range []struct {
+		begin, end int64
+		want       error
+	}{
+		{0, -1, core.ErrHistoryPruned},
+		{2, 4, core.ErrHistoryPruned},
+		{4, 10, core.ErrHistoryPruned},
+		{5, -1, nil},
+		{-1, -1, nil},
+		{-1, 10, nil},
+	} {
+		filter := NewRangeFilter(backend, test.begin, test.end, nil, nil)
+		if _, err := filter.Logs(context.Background()); err != test.want {
+			t.Errorf("range %d-%d: error mismatch: have %v, want %v", test.begin, test.end, err, test.want)
+		}
+	}
+}
@@ -23,13 +23,17 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NetworkId                uint64
 		SyncMode                 downloader.SyncMode
 		NoPruning                bool
+		AddressIndex             bool
 		LightServ                int  `toml:",omitempty"`
 		LightPeers               int  `toml:",omitempty"`
 		SkipBcVersionCheck       bool `toml:"-"`
 		DatabaseHandles          int  `toml:"-"`
 		DatabaseCache            int
+		DatabaseFreezer          string
 		TrieCache                int
 		TrieTimeout              time.Duration
+		HistoryBlocks            uint64
+		WasmCacheDiskSize        int64
 		MinerExtraData           hexutil.Bytes `toml:",omitempty"`
 		MinerGasFloor            uint64
 		MinerGasPrice            *big.Int
@@ -66,13 +70,17 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NetworkId = c.NetworkId
 	enc.SyncMode = c.SyncMode
 	enc.NoPruning = c.NoPruning
+	enc.AddressIndex = c.AddressIndex
 	enc.LightServ = c.LightServ
 	enc.LightPeers = c.LightPeers
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
+	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
+	enc.HistoryBlocks = c.HistoryBlocks
+	enc.WasmCacheDiskSize = c.WasmCacheDiskSize
 	enc.MinerExtraData = c.MinerExtraData
 	enc.MinerGasFloor = c.MinerGasFloor
 	enc.MinerGasPrice = c.MinerGasPrice
@@ -113,13 +121,17 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NetworkId                *uint64
 		SyncMode                 *downloader.SyncMode
 		NoPruning                *bool
+		AddressIndex             *bool
 		LightServ                *int  `toml:",omitempty"`
 		LightPeers               *int  `toml:",omitempty"`
 		SkipBcVersionCheck       *bool `toml:"-"`
 		DatabaseHandles          *int  `toml:"-"`
 		DatabaseCache            *int
+		DatabaseFreezer          *string
 		TrieCache                *int
 		TrieTimeout              *time.Duration
+		HistoryBlocks            *uint64
+		WasmCacheDiskSize        *int64
 		MinerExtraData           *hexutil.Bytes `toml:",omitempty"`
 		MinerGasFloor            *uint64
 		MinerGasPrice            *big.Int
@@ -169,6 +181,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.NoPruning != nil {
 		c.NoPruning = *dec.NoPruning
 	}
+	if dec.AddressIndex != nil {
+		c.AddressIndex = *dec.AddressIndex
+	}
 	if dec.LightServ != nil {
 		c.LightServ = *dec.LightServ
 	}
@@ -184,12 +199,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseCache != nil {
 		c.DatabaseCache = *dec.DatabaseCache
 	}
+	if dec.DatabaseFreezer != nil {
+		c.DatabaseFreezer = *dec.DatabaseFreezer
+	}
 	if dec.TrieCache != nil {
 		c.TrieCache = *dec.TrieCache
 	}
 	if dec.TrieTimeout != nil {
 		c.TrieTimeout = *dec.TrieTimeout
 	}
+	if dec.HistoryBlocks != nil {
+		c.HistoryBlocks = *dec.HistoryBlocks
+	}
+	if dec.WasmCacheDiskSize != nil {
+		c.WasmCacheDiskSize = *dec.WasmCacheDiskSize
+	}
 	if dec.MinerExtraData != nil {
 		c.MinerExtraData = *dec.MinerExtraData
 	}
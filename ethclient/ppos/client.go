@@ -0,0 +1,193 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ppos provides a typed client for the PPOS system contracts
+// (staking, governance, restricting and slashing).
+package ppos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/ethclient"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+)
+
+var (
+	// ErrTxFailed is returned for a PPOS transaction whose execution failed
+	// outside of the contract's own checks, e.g. because it ran out of gas.
+	ErrTxFailed = errors.New("ppos transaction failed")
+
+	// ErrNoResult is returned for a receipt carrying no PPOS result log.
+	ErrNoResult = errors.New("no ppos result in receipt")
+)
+
+// Backend is the chain access needed by the client. Both *ethclient.Client
+// and the simulated backends implement it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Client issues typed calls and transactions against the PPOS system contracts.
+type Client struct {
+	backend Backend
+
+	staking     *bind.BoundContract
+	gov         *bind.BoundContract
+	restricting *bind.BoundContract
+	slashing    *bind.BoundContract
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	c, err := ethclient.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client on top of the given backend.
+func NewClient(backend Backend) *Client {
+	bound := func(addr common.Address) *bind.BoundContract {
+		return bind.NewBoundContract(addr, abi.ABI{}, backend, backend, backend)
+	}
+	return &Client{
+		backend:     backend,
+		staking:     bound(vm.StakingContractAddr),
+		gov:         bound(vm.GovContractAddr),
+		restricting: bound(vm.RestrictingContractAddr),
+		slashing:    bound(vm.SlashingContractAddr),
+	}
+}
+
+// WaitResult waits for tx to be mined and returns its receipt along with the
+// contract's result, see ReceiptError.
+func (c *Client) WaitResult(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, c.backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, ReceiptError(receipt)
+}
+
+// ReceiptError returns the result of a mined PPOS transaction: nil if the
+// contract accepted it, the *common.BizError it was rejected with otherwise.
+func ReceiptError(receipt *types.Receipt) error {
+	if receipt.Status == types.ReceiptStatusFailed {
+		return ErrTxFailed
+	}
+	for _, log := range receipt.Logs {
+		if !isPPOSContract(log.Address) || len(log.Topics) != 1 {
+			continue
+		}
+		var data [][]byte
+		if err := rlp.DecodeBytes(log.Data, &data); err != nil || len(data) != 1 {
+			continue
+		}
+		code, err := strconv.ParseUint(string(data[0]), 10, 32)
+		if err != nil {
+			continue
+		}
+		if uint32(code) == common.OkCode {
+			return nil
+		}
+		return codeError(uint32(code))
+	}
+	return ErrNoResult
+}
+
+func isPPOSContract(addr common.Address) bool {
+	switch addr {
+	case vm.StakingContractAddr, vm.GovContractAddr, vm.RestrictingContractAddr, vm.SlashingContractAddr:
+		return true
+	}
+	return false
+}
+
+// encodeInput builds the calldata the PPOS contracts expect: the RLP list of
+// the RLP encoded function code followed by each RLP encoded argument.
+func encodeInput(fnCode uint16, args ...interface{}) ([]byte, error) {
+	input := make([][]byte, 0, len(args)+1)
+	code, err := rlp.EncodeToBytes(fnCode)
+	if err != nil {
+		return nil, err
+	}
+	input = append(input, code)
+	for i, arg := range args {
+		enc, err := rlp.EncodeToBytes(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument %d of function %d: %v", i, fnCode, err)
+		}
+		input = append(input, enc)
+	}
+	return rlp.EncodeToBytes(input)
+}
+
+// transact sends a transaction invoking fnCode of the given contract.
+func transact(contract *bind.BoundContract, opts *bind.TransactOpts, fnCode uint16, args ...interface{}) (*types.Transaction, error) {
+	input, err := encodeInput(fnCode, args...)
+	if err != nil {
+		return nil, err
+	}
+	return contract.RawTransact(opts, input)
+}
+
+// call invokes the query fnCode of the given contract and decodes its
+// result into out.
+func call(contract *bind.BoundContract, opts *bind.CallOpts, out interface{}, fnCode uint16, args ...interface{}) error {
+	input, err := encodeInput(fnCode, args...)
+	if err != nil {
+		return err
+	}
+	output, err := contract.RawCall(opts, input)
+	if err != nil {
+		return err
+	}
+	return decodeResult(output, out)
+}
+
+// decodeResult unpacks the JSON result of a PPOS query, turning a failure
+// code into a *common.BizError.
+func decodeResult(output []byte, out interface{}) error {
+	var result struct {
+		Code uint32
+		Ret  json.RawMessage
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return fmt.Errorf("invalid ppos result: %v", err)
+	}
+	if result.Code != common.OkCode {
+		var msg string
+		if err := json.Unmarshal(result.Ret, &msg); err != nil {
+			msg = string(result.Ret)
+		}
+		return common.NewBizError(result.Code, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Ret, out)
+}
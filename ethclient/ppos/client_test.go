@@ -0,0 +1,115 @@
+package ppos
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	commonvm "github.com/PlatONnetwork/PlatON-Go/common/vm"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+	"github.com/PlatONnetwork/PlatON-Go/x/xcom"
+)
+
+func TestEncodeInput(t *testing.T) {
+	nodeId := discover.NodeID{1, 2, 3}
+	input, err := encodeInput(vm.TxDelegate, uint16(1), nodeId, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var args [][]byte
+	if err := rlp.DecodeBytes(input, &args); err != nil {
+		t.Fatalf("input is not an rlp list: %v", err)
+	}
+	if len(args) != 4 {
+		t.Fatalf("argument count mismatch: have %d, want 4", len(args))
+	}
+	var (
+		fnCode, typ uint16
+		node        discover.NodeID
+		amount      *big.Int
+	)
+	for i, out := range []interface{}{&fnCode, &typ, &node, &amount} {
+		if err := rlp.DecodeBytes(args[i], out); err != nil {
+			t.Fatalf("failed to decode argument %d: %v", i, err)
+		}
+	}
+	if fnCode != vm.TxDelegate || typ != 1 || node != nodeId || amount.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("decoded input mismatch: %d %d %x %v", fnCode, typ, node, amount)
+	}
+}
+
+func TestDecodeResult(t *testing.T) {
+	var version uint32
+	if err := decodeResult(xcom.NewOkResult(uint32(1792)), &version); err != nil {
+		t.Fatal(err)
+	}
+	if version != 1792 {
+		t.Errorf("result mismatch: have %d, want 1792", version)
+	}
+
+	var can staking.CandidateHex
+	err := decodeResult(xcom.NewFailedResult(staking.ErrQueryCandidateInfo.Wrap("Candidate info is not found")), &can)
+	bizErr, ok := err.(*common.BizError)
+	if !ok {
+		t.Fatalf("expected a BizError, got %v", err)
+	}
+	if bizErr.Code != staking.ErrQueryCandidateInfo.Code {
+		t.Errorf("code mismatch: have %d, want %d", bizErr.Code, staking.ErrQueryCandidateInfo.Code)
+	}
+}
+
+func TestDecodeProposal(t *testing.T) {
+	want := &gov.VersionProposal{
+		ProposalID:   common.HexToHash("0x01"),
+		ProposalType: gov.Version,
+		PIPID:        "pip-1",
+		NewVersion:   1792,
+	}
+	raw, _ := json.Marshal(want)
+	have, err := decodeProposal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal, ok := have.(*gov.VersionProposal)
+	if !ok {
+		t.Fatalf("proposal type mismatch: %T", have)
+	}
+	if proposal.ProposalID != want.ProposalID || proposal.NewVersion != want.NewVersion {
+		t.Errorf("proposal mismatch: have %v, want %v", proposal, want)
+	}
+}
+
+func TestReceiptError(t *testing.T) {
+	resultLog := func(fnCode int, code uint32) *types.Log {
+		buf := new(bytes.Buffer)
+		rlp.Encode(buf, [][]byte{[]byte(strconv.FormatUint(uint64(code), 10))})
+		return &types.Log{
+			Address: commonvm.StakingContractAddr,
+			Topics:  []common.Hash{crypto.Keccak256Hash([]byte(strconv.Itoa(fnCode)))},
+			Data:    buf.Bytes(),
+		}
+	}
+	tests := []struct {
+		receipt *types.Receipt
+		err     error
+	}{
+		{&types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{resultLog(vm.TxDelegate, common.OkCode)}}, nil},
+		{&types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{resultLog(vm.TxDelegate, staking.ErrCanNoExist.Code)}}, staking.ErrCanNoExist},
+		{&types.Receipt{Status: types.ReceiptStatusSuccessful}, ErrNoResult},
+		{&types.Receipt{Status: types.ReceiptStatusFailed}, ErrTxFailed},
+	}
+	for i, test := range tests {
+		if err := ReceiptError(test.receipt); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
+	"github.com/PlatONnetwork/PlatON-Go/x/slashing"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+)
+
+// knownErrors indexes the errors the PPOS contracts may reject a transaction
+// with by their code, since a receipt only carries the code.
+var knownErrors = func() map[uint32]*common.BizError {
+	errs := []*common.BizError{
+		common.InternalError,
+		common.NotFound,
+		common.InvalidParameter,
+
+		staking.ErrWrongBlsPubKey,
+		staking.ErrWrongBlsPubKeyProof,
+		staking.ErrDescriptionLen,
+		staking.ErrWrongProgramVersionSign,
+		staking.ErrProgramVersionTooLow,
+		staking.ErrDeclVsFialedCreateCan,
+		staking.ErrNoSameStakingAddr,
+		staking.ErrStakeVonTooLow,
+		staking.ErrCanAlreadyExist,
+		staking.ErrCanNoExist,
+		staking.ErrCanStatusInvalid,
+		staking.ErrIncreaseStakeVonTooLow,
+		staking.ErrDelegateVonTooLow,
+		staking.ErrAccountNoAllowToDelegate,
+		staking.ErrCanNoAllowDelegate,
+		staking.ErrWithdrewDelegateVonTooLow,
+		staking.ErrDelegateNoExist,
+		staking.ErrWrongVonOptType,
+		staking.ErrAccountVonNoEnough,
+		staking.ErrBlockNumberDisordered,
+		staking.ErrDelegateVonNoEnough,
+		staking.ErrWrongWithdrewDelVonCalc,
+		staking.ErrValidatorNoExist,
+		staking.ErrWrongFuncParams,
+		staking.ErrWrongSlashType,
+		staking.ErrSlashVonOverflow,
+		staking.ErrWrongSlashVonCalc,
+		staking.ErrGetVerifierList,
+		staking.ErrGetValidatorList,
+		staking.ErrGetCandidateList,
+		staking.ErrGetDelegateRelated,
+		staking.ErrQueryCandidateInfo,
+		staking.ErrQueryDelegateInfo,
+
+		gov.ActiveVersionError,
+		gov.VoteOptionError,
+		gov.ProposalTypeError,
+		gov.ProposalIDEmpty,
+		gov.ProposalIDExist,
+		gov.ProposalNotFound,
+		gov.PIPIDEmpty,
+		gov.PIPIDExist,
+		gov.EndVotingRoundsTooSmall,
+		gov.EndVotingRoundsTooLarge,
+		gov.NewVersionError,
+		gov.VotingVersionProposalExist,
+		gov.PreActiveVersionProposalExist,
+		gov.VotingCancelProposalExist,
+		gov.TobeCanceledProposalNotFound,
+		gov.TobeCanceledProposalTypeError,
+		gov.TobeCanceledProposalNotAtVoting,
+		gov.ProposerEmpty,
+		gov.VerifierInfoNotFound,
+		gov.VerifierStatusInvalid,
+		gov.TxSenderDifferFromStaking,
+		gov.TxSenderIsNotVerifier,
+		gov.TxSenderIsNotCandidate,
+		gov.VersionSignError,
+		gov.VerifierNotUpgraded,
+		gov.ProposalNotAtVoting,
+		gov.VoteDuplicated,
+		gov.DeclareVersionError,
+		gov.NotifyStakingDeclaredVersionError,
+		gov.TallyResultNotFound,
+		gov.UnsupportedGovernParam,
+		gov.VotingParamProposalExist,
+		gov.GovernParamValueError,
+		gov.ParamProposalIsSameValue,
+
+		slashing.ErrDuplicateSignVerify,
+		slashing.ErrSlashingExist,
+		slashing.ErrBlockNumberTooHigh,
+		slashing.ErrIntervalTooLong,
+		slashing.ErrGetCandidate,
+		slashing.ErrAddrMismatch,
+		slashing.ErrNodeIdMismatch,
+		slashing.ErrBlsPubKeyMismatch,
+		slashing.ErrSlashingFail,
+		slashing.ErrNotValidator,
+		slashing.ErrSameAddr,
+
+		restricting.ErrParamEpochInvalid,
+		restricting.ErrCountRestrictPlansInvalid,
+		restricting.ErrLockedAmountTooLess,
+		restricting.ErrBalanceNotEnough,
+		restricting.ErrAccountNotFound,
+		restricting.ErrSlashingTooMuch,
+		restricting.ErrStakingAmountEmpty,
+		restricting.ErrPledgeLockFundsAmountLessThanZero,
+		restricting.ErrReturnLockFundsAmountLessThanZero,
+		restricting.ErrSlashingAmountLessThanZero,
+		restricting.ErrCreatePlanAmountLessThanZero,
+		restricting.ErrStakingAmountInvalid,
+		restricting.ErrRestrictBalanceNotEnough,
+	}
+	known := make(map[uint32]*common.BizError, len(errs))
+	for _, err := range errs {
+		known[err.Code] = err
+	}
+	return known
+}()
+
+// codeError returns the error for a failure code reported in a receipt.
+func codeError(code uint32) *common.BizError {
+	if err, ok := knownErrors[code]; ok {
+		return err
+	}
+	return common.NewBizError(code, fmt.Sprintf("unknown ppos error code %d", code))
+}
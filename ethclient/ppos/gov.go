@@ -0,0 +1,173 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+)
+
+// AccuVerifiersCount is the voting progress of a proposal.
+type AccuVerifiersCount struct {
+	Verifiers   uint16 // verifiers entitled to vote
+	Yeas        uint16
+	Nays        uint16
+	Abstentions uint16
+}
+
+// SubmitText submits a text proposal on behalf of the verifier.
+func (c *Client) SubmitText(opts *bind.TransactOpts, verifier discover.NodeID, pipID string) (*types.Transaction, error) {
+	return transact(c.gov, opts, vm.SubmitText, verifier, pipID)
+}
+
+// SubmitVersion submits a version upgrade proposal on behalf of the verifier.
+func (c *Client) SubmitVersion(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, newVersion uint32, endVotingRounds uint64) (*types.Transaction, error) {
+	return transact(c.gov, opts, vm.SubmitVersion, verifier, pipID, newVersion, endVotingRounds)
+}
+
+// SubmitParam submits a proposal changing a govern parameter on behalf of the verifier.
+func (c *Client) SubmitParam(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, module, name, newValue string) (*types.Transaction, error) {
+	return transact(c.gov, opts, vm.SubmitParam, verifier, pipID, module, name, newValue)
+}
+
+// Vote votes on the proposal on behalf of the verifier.
+func (c *Client) Vote(opts *bind.TransactOpts, verifier discover.NodeID, proposalID common.Hash, option gov.VoteOption,
+	programVersion uint32, programVersionSign common.VersionSign) (*types.Transaction, error) {
+	return transact(c.gov, opts, vm.Vote, verifier, proposalID, uint8(option), programVersion, programVersionSign)
+}
+
+// DeclareVersion declares the program version the node runs.
+func (c *Client) DeclareVersion(opts *bind.TransactOpts, activeNode discover.NodeID, programVersion uint32,
+	programVersionSign common.VersionSign) (*types.Transaction, error) {
+	return transact(c.gov, opts, vm.Declare, activeNode, programVersion, programVersionSign)
+}
+
+// SubmitCancel submits a proposal canceling the version proposal tobeCanceled
+// on behalf of the verifier.
+func (c *Client) SubmitCancel(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, endVotingRounds uint64,
+	tobeCanceled common.Hash) (*types.Transaction, error) {
+	return transact(c.gov, opts, vm.SubmitCancel, verifier, pipID, endVotingRounds, tobeCanceled)
+}
+
+// GetProposal returns the proposal, typed by its kind.
+func (c *Client) GetProposal(opts *bind.CallOpts, proposalID common.Hash) (gov.Proposal, error) {
+	var raw json.RawMessage
+	if err := call(c.gov, opts, &raw, vm.GetProposal, proposalID); err != nil {
+		return nil, err
+	}
+	return decodeProposal(raw)
+}
+
+// GetTallyResult returns the tally of a proposal whose voting has ended.
+func (c *Client) GetTallyResult(opts *bind.CallOpts, proposalID common.Hash) (*gov.TallyResult, error) {
+	result := new(gov.TallyResult)
+	if err := call(c.gov, opts, result, vm.GetResult, proposalID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListProposal returns all the proposals.
+func (c *Client) ListProposal(opts *bind.CallOpts) ([]gov.Proposal, error) {
+	var raws []json.RawMessage
+	if err := call(c.gov, opts, &raws, vm.ListProposal); err != nil {
+		return nil, err
+	}
+	proposals := make([]gov.Proposal, 0, len(raws))
+	for _, raw := range raws {
+		proposal, err := decodeProposal(raw)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, nil
+}
+
+// GetActiveVersion returns the chain's active version.
+func (c *Client) GetActiveVersion(opts *bind.CallOpts) (uint32, error) {
+	var version uint32
+	if err := call(c.gov, opts, &version, vm.GetActiveVersion); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetGovernParamValue returns the value of a govern parameter.
+func (c *Client) GetGovernParamValue(opts *bind.CallOpts, module, name string) (string, error) {
+	var value string
+	if err := call(c.gov, opts, &value, vm.GetGovernParamValue, module, name); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// GetAccuVerifiersCount returns the voting progress of the proposal as of blockHash.
+func (c *Client) GetAccuVerifiersCount(opts *bind.CallOpts, proposalID, blockHash common.Hash) (*AccuVerifiersCount, error) {
+	var counts []uint16
+	if err := call(c.gov, opts, &counts, vm.GetAccuVerifiersCount, proposalID, blockHash); err != nil {
+		return nil, err
+	}
+	if len(counts) != 4 {
+		return nil, fmt.Errorf("invalid accumulated verifiers count %v", counts)
+	}
+	return &AccuVerifiersCount{Verifiers: counts[0], Yeas: counts[1], Nays: counts[2], Abstentions: counts[3]}, nil
+}
+
+// ListGovernParam returns the govern parameters of the module, or all of
+// them if module is empty.
+func (c *Client) ListGovernParam(opts *bind.CallOpts, module string) ([]*gov.GovernParam, error) {
+	var params []*gov.GovernParam
+	if err := call(c.gov, opts, &params, vm.ListGovernParam, module); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// decodeProposal unpacks a JSON proposal into the type its ProposalType names.
+func decodeProposal(raw json.RawMessage) (gov.Proposal, error) {
+	var header struct {
+		ProposalType gov.ProposalType
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, err
+	}
+	var proposal gov.Proposal
+	switch header.ProposalType {
+	case gov.Text:
+		proposal = new(gov.TextProposal)
+	case gov.Version:
+		proposal = new(gov.VersionProposal)
+	case gov.Param:
+		proposal = new(gov.ParamProposal)
+	case gov.Cancel:
+		proposal = new(gov.CancelProposal)
+	default:
+		return nil, fmt.Errorf("unknown proposal type %d", header.ProposalType)
+	}
+	if err := json.Unmarshal(raw, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
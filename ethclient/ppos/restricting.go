@@ -0,0 +1,40 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
+)
+
+// CreateRestrictingPlan locks funds of the sender for account, released by
+// the given plans.
+func (c *Client) CreateRestrictingPlan(opts *bind.TransactOpts, account common.Address, plans []restricting.RestrictingPlan) (*types.Transaction, error) {
+	return transact(c.restricting, opts, vm.TxCreateRestrictingPlan, account, plans)
+}
+
+// GetRestrictingInfo returns the restricting plans of the account.
+func (c *Client) GetRestrictingInfo(opts *bind.CallOpts, account common.Address) (*restricting.Result, error) {
+	result := new(restricting.Result)
+	if err := call(c.restricting, opts, result, vm.QueryRestrictingInfo, account); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/consensus"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+)
+
+// ReportDuplicateSign reports the JSON encoded evidence of a node signing
+// twice at the same height.
+func (c *Client) ReportDuplicateSign(opts *bind.TransactOpts, dupType consensus.EvidenceType, data string) (*types.Transaction, error) {
+	return transact(c.slashing, opts, vm.TxReportDuplicateSign, uint8(dupType), data)
+}
+
+// CheckDuplicateSign returns the hash of the transaction that reported addr
+// for signing twice at blockNumber, or the zero hash if none did.
+func (c *Client) CheckDuplicateSign(opts *bind.CallOpts, dupType consensus.EvidenceType, addr common.Address, blockNumber uint64) (common.Hash, error) {
+	var txHash string
+	if err := call(c.slashing, opts, &txHash, vm.CheckDuplicateSign, uint8(dupType), addr, blockNumber); err != nil {
+		return common.Hash{}, err
+	}
+	if txHash == "" {
+		return common.Hash{}, nil
+	}
+	hash, err := hexutil.Decode(txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
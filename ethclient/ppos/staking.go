@@ -0,0 +1,120 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi/bind"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+)
+
+// CreateStaking stakes amount on the node, paid from free balance (typ 0) or
+// restricting plan (typ 1), making it a candidate.
+func (c *Client) CreateStaking(opts *bind.TransactOpts, typ uint16, benefitAddress common.Address, nodeId discover.NodeID,
+	externalId, nodeName, website, details string, amount *big.Int, programVersion uint32,
+	programVersionSign common.VersionSign, blsPubKey bls.PublicKeyHex, blsProof bls.SchnorrProofHex) (*types.Transaction, error) {
+	return transact(c.staking, opts, vm.TxCreateStaking, typ, benefitAddress, nodeId, externalId, nodeName, website, details,
+		amount, programVersion, programVersionSign, blsPubKey, blsProof)
+}
+
+// EditCandidate updates the benefit address and description of the candidate.
+func (c *Client) EditCandidate(opts *bind.TransactOpts, benefitAddress common.Address, nodeId discover.NodeID,
+	externalId, nodeName, website, details string) (*types.Transaction, error) {
+	return transact(c.staking, opts, vm.TxEditorCandidate, benefitAddress, nodeId, externalId, nodeName, website, details)
+}
+
+// IncreaseStaking adds amount to the candidate's own staking.
+func (c *Client) IncreaseStaking(opts *bind.TransactOpts, nodeId discover.NodeID, typ uint16, amount *big.Int) (*types.Transaction, error) {
+	return transact(c.staking, opts, vm.TxIncreaseStaking, nodeId, typ, amount)
+}
+
+// WithdrewStaking withdraws the candidate's staking.
+func (c *Client) WithdrewStaking(opts *bind.TransactOpts, nodeId discover.NodeID) (*types.Transaction, error) {
+	return transact(c.staking, opts, vm.TxWithdrewCandidate, nodeId)
+}
+
+// Delegate delegates amount to the candidate.
+func (c *Client) Delegate(opts *bind.TransactOpts, typ uint16, nodeId discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return transact(c.staking, opts, vm.TxDelegate, typ, nodeId, amount)
+}
+
+// WithdrewDelegate withdraws amount from the delegation on the candidate
+// staked at stakingBlockNum.
+func (c *Client) WithdrewDelegate(opts *bind.TransactOpts, stakingBlockNum uint64, nodeId discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return transact(c.staking, opts, vm.TxWithdrewDelegate, stakingBlockNum, nodeId, amount)
+}
+
+// GetVerifierList returns the verifiers of the current settlement epoch.
+func (c *Client) GetVerifierList(opts *bind.CallOpts) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	if err := call(c.staking, opts, &list, vm.QueryVerifierList); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetValidatorList returns the validators of the current consensus round.
+func (c *Client) GetValidatorList(opts *bind.CallOpts) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	if err := call(c.staking, opts, &list, vm.QueryValidatorList); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetCandidateList returns all the candidates.
+func (c *Client) GetCandidateList(opts *bind.CallOpts) (staking.CandidateHexQueue, error) {
+	var list staking.CandidateHexQueue
+	if err := call(c.staking, opts, &list, vm.QueryCandidateList); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetRelatedListByDelAddr returns the candidates the account delegated to.
+func (c *Client) GetRelatedListByDelAddr(opts *bind.CallOpts, addr common.Address) (staking.DelRelatedQueue, error) {
+	var list staking.DelRelatedQueue
+	if err := call(c.staking, opts, &list, vm.QueryRelateList, addr); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetDelegateInfo returns the delegation of delAddr on the candidate staked
+// at stakingBlockNum.
+func (c *Client) GetDelegateInfo(opts *bind.CallOpts, stakingBlockNum uint64, delAddr common.Address, nodeId discover.NodeID) (*staking.DelegationEx, error) {
+	del := new(staking.DelegationEx)
+	if err := call(c.staking, opts, del, vm.QueryDelegateInfo, stakingBlockNum, delAddr, nodeId); err != nil {
+		return nil, err
+	}
+	return del, nil
+}
+
+// GetCandidateInfo returns the candidate of the node.
+func (c *Client) GetCandidateInfo(opts *bind.CallOpts, nodeId discover.NodeID) (*staking.CandidateHex, error) {
+	can := new(staking.CandidateHex)
+	if err := call(c.staking, opts, can, vm.QueryCandidateInfo, nodeId); err != nil {
+		return nil, err
+	}
+	return can, nil
+}
@@ -0,0 +1,182 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/rpc"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+)
+
+func TestBuildSchema(t *testing.T) {
+	// Make sure the schema can be parsed and matched up to the object model.
+	if _, err := newHandler(nil, nil); err != nil {
+		t.Errorf("Could not construct GraphQL handler: %v", err)
+	}
+}
+
+// testBackend serves a chain of bare headers.
+type testBackend struct {
+	Backend
+	headers []*types.Header
+}
+
+func newTestBackend(blocks int) *testBackend {
+	b := new(testBackend)
+	for i := 0; i < blocks; i++ {
+		b.headers = append(b.headers, &types.Header{Number: big.NewInt(int64(i)), Extra: []byte{byte(i)}})
+	}
+	return b
+}
+
+func (b *testBackend) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(b.headers[len(b.headers)-1])
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		return b.headers[len(b.headers)-1], nil
+	}
+	if number < 0 || int(number) >= len(b.headers) {
+		return nil, nil
+	}
+	return b.headers[number], nil
+}
+
+func (b *testBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	for _, header := range b.headers {
+		if header.Hash() == hash {
+			return header, nil
+		}
+	}
+	return nil, nil
+}
+
+var errTestPruned = errors.New("pruned")
+
+// testPPOSReader serves a single candidate for the blocks from committed on,
+// like the PPOS api reading the snapshot db.
+type testPPOSReader struct {
+	PPOSReader
+	b         *testBackend
+	committed uint64
+}
+
+func (r *testPPOSReader) check(ctx context.Context, at rpc.BlockNumberOrHash) error {
+	var (
+		header *types.Header
+		err    error
+	)
+	if hash, ok := at.Hash(); ok {
+		header, err = r.b.HeaderByHash(ctx, hash)
+	} else {
+		number, _ := at.Number()
+		header, err = r.b.HeaderByNumber(ctx, number)
+	}
+	if err != nil || header == nil {
+		return errBlockNotFound
+	}
+	if header.Number.Uint64() < r.committed {
+		return errTestPruned
+	}
+	return nil
+}
+
+func (r *testPPOSReader) GetCandidateList(ctx context.Context, at rpc.BlockNumberOrHash) (staking.CandidateHexQueue, error) {
+	if err := r.check(ctx, at); err != nil {
+		return nil, err
+	}
+	return staking.CandidateHexQueue{&staking.CandidateHex{NodeName: "node"}}, nil
+}
+
+func (r *testPPOSReader) GetDelegations(ctx context.Context, addr common.Address, at rpc.BlockNumberOrHash) ([]*staking.DelegationEx, error) {
+	if err := r.check(ctx, at); err != nil {
+		return nil, err
+	}
+	return []*staking.DelegationEx{{Addr: addr}}, nil
+}
+
+func TestResolverBlocks(t *testing.T) {
+	long := func(n uint64) *Long { l := Long(n); return &l }
+
+	r := &Resolver{backend: newTestBackend(11)}
+	for _, test := range []struct {
+		from       Long
+		to         *Long
+		begin, end int
+	}{
+		{3, nil, 3, 10},
+		{3, long(5), 3, 5},
+		{3, long(100), 3, 10},
+		{0, long(math.MaxUint64), 0, 10},
+		{5, long(4), 0, -1},
+		{11, nil, 0, -1},
+		{math.MaxUint64, nil, 0, -1},
+	} {
+		blocks, err := r.Blocks(context.Background(), struct {
+			From Long
+			To   *Long
+		}{test.from, test.to})
+		if err != nil {
+			t.Fatalf("blocks from %d: %v", test.from, err)
+		}
+		if len(blocks) != test.end-test.begin+1 {
+			t.Fatalf("blocks from %d: block count mismatch: have %d, want %d", test.from, len(blocks), test.end-test.begin+1)
+		}
+		for i, block := range blocks {
+			if number, err := block.Number(context.Background()); err != nil || int(number) != test.begin+i {
+				t.Errorf("blocks from %d: block %d mismatch: have %d (%v), want %d", test.from, i, number, err, test.begin+i)
+			}
+		}
+	}
+
+	// Long ranges are refused rather than allocated
+	r = &Resolver{backend: newTestBackend(maxBlocksRange + 1)}
+	for _, test := range []struct {
+		from Long
+		want error
+	}{
+		{0, errBlocksRange},
+		{1, nil},
+	} {
+		if _, err := r.Blocks(context.Background(), struct {
+			From Long
+			To   *Long
+		}{test.from, nil}); err != test.want {
+			t.Errorf("blocks from %d: error mismatch: have %v, want %v", test.from, err, test.want)
+		}
+	}
+}
+
+func TestBlockPPOS(t *testing.T) {
+	b := newTestBackend(4)
+	r := &Resolver{backend: b, ppos: &testPPOSReader{b: b, committed: 2}}
+
+	for number, want := range map[rpc.BlockNumber]error{1: errTestPruned, 2: nil, 3: nil} {
+		num := number
+		block := &Block{r: r, num: &num}
+		candidates, err := block.Candidates(context.Background())
+		if err != want {
+			t.Errorf("candidates of block %d: error mismatch: have %v, want %v", number, err, want)
+		} else if err == nil && (len(candidates) != 1 || candidates[0].NodeName(context.Background()) != "node") {
+			t.Errorf("candidates of block %d mismatch: %v", number, candidates)
+		}
+		account := &Account{r: r, address: common.Address{1}, blockNumber: number}
+		delegations, err := account.Delegations(context.Background())
+		if err != want {
+			t.Errorf("delegations as of block %d: error mismatch: have %v, want %v", number, err, want)
+		} else if err == nil && len(delegations) != 1 {
+			t.Errorf("delegation count as of block %d mismatch: have %d, want 1", number, len(delegations))
+		}
+	}
+	// Blocks that don't exist don't reach the reader
+	num := rpc.BlockNumber(10)
+	if _, err := (&Block{r: r, num: &num}).Candidates(context.Background()); err != errBlockNotFound {
+		t.Errorf("candidates of a missing block: error mismatch: have %v, want %v", err, errBlockNotFound)
+	}
+}
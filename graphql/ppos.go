@@ -0,0 +1,515 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/p2p/discover"
+	"github.com/PlatONnetwork/PlatON-Go/rpc"
+	"github.com/PlatONnetwork/PlatON-Go/x/gov"
+	"github.com/PlatONnetwork/PlatON-Go/x/restricting"
+	"github.com/PlatONnetwork/PlatON-Go/x/staking"
+)
+
+// PPOSReader reads the staking, governance and restricting data kept by the
+// PPOS plugins as of a block. It is implemented by eth.PublicPPOSAPI.
+type PPOSReader interface {
+	GetCandidate(ctx context.Context, nodeId discover.NodeID, blockNrOrHash rpc.BlockNumberOrHash) (*staking.CandidateHex, error)
+	GetCandidateList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (staking.CandidateHexQueue, error)
+	GetVerifierList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (staking.ValidatorExQueue, error)
+	GetValidatorList(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (staking.ValidatorExQueue, error)
+	GetDelegations(ctx context.Context, addr common.Address, blockNrOrHash rpc.BlockNumberOrHash) ([]*staking.DelegationEx, error)
+	GetProposal(ctx context.Context, proposalID common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (gov.Proposal, error)
+	ListProposal(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]gov.Proposal, error)
+	GetTallyResult(ctx context.Context, proposalID common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*gov.TallyResult, error)
+	GetActiveVersion(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (uint32, error)
+	GetRestrictingInfo(ctx context.Context, account common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*restricting.Result, error)
+}
+
+// bigOrZero converts a plugin amount, which may be unset, to a BigInt.
+func bigOrZero(b *hexutil.Big) hexutil.Big {
+	if b == nil {
+		return hexutil.Big{}
+	}
+	return *b
+}
+
+func (a *Account) Restricting(ctx context.Context) (*RestrictingPlan, error) {
+	result, err := a.r.ppos.GetRestrictingInfo(ctx, a.address, rpc.BlockNumberOrHashWithNumber(a.blockNumber))
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return &RestrictingPlan{result}, nil
+}
+
+func (a *Account) Delegations(ctx context.Context) ([]*Delegation, error) {
+	at := rpc.BlockNumberOrHashWithNumber(a.blockNumber)
+	delegations, err := a.r.ppos.GetDelegations(ctx, a.address, at)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Delegation, 0, len(delegations))
+	for _, del := range delegations {
+		ret = append(ret, &Delegation{
+			r:          a.r,
+			at:         at,
+			delegation: del,
+		})
+	}
+	return ret, nil
+}
+
+// pposBlock returns the selector the PPOS readers resolve the block with.
+func (b *Block) pposBlock(ctx context.Context) (rpc.BlockNumberOrHash, error) {
+	hash, err := b.Hash(ctx)
+	if err != nil {
+		return rpc.BlockNumberOrHash{}, err
+	}
+	return rpc.BlockNumberOrHashWithHash(hash), nil
+}
+
+func (b *Block) Candidates(ctx context.Context) ([]*Candidate, error) {
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := b.r.ppos.GetCandidateList(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Candidate, 0, len(candidates))
+	for _, can := range candidates {
+		ret = append(ret, &Candidate{r: b.r, candidate: can})
+	}
+	return ret, nil
+}
+
+func (b *Block) Candidate(ctx context.Context, args struct{ NodeId string }) (*Candidate, error) {
+	nodeId, err := discover.HexID(args.NodeId)
+	if err != nil {
+		return nil, err
+	}
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return getCandidate(ctx, b.r, nodeId, at)
+}
+
+// getCandidate resolves the candidate of the node, or nil if it has none.
+func getCandidate(ctx context.Context, r *Resolver, nodeId discover.NodeID, at rpc.BlockNumberOrHash) (*Candidate, error) {
+	can, err := r.ppos.GetCandidate(ctx, nodeId, at)
+	if err != nil || can == nil {
+		return nil, err
+	}
+	return &Candidate{r: r, candidate: can}, nil
+}
+
+func (b *Block) Verifiers(ctx context.Context) ([]*Validator, error) {
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	verifiers, err := b.r.ppos.GetVerifierList(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	return wrapValidators(b.r, at, verifiers), nil
+}
+
+func (b *Block) Validators(ctx context.Context) ([]*Validator, error) {
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := b.r.ppos.GetValidatorList(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	return wrapValidators(b.r, at, validators), nil
+}
+
+// wrapValidators wraps the validators resolved as of the block.
+func wrapValidators(r *Resolver, at rpc.BlockNumberOrHash, validators staking.ValidatorExQueue) []*Validator {
+	ret := make([]*Validator, 0, len(validators))
+	for _, v := range validators {
+		ret = append(ret, &Validator{
+			r:         r,
+			at:        at,
+			validator: v,
+		})
+	}
+	return ret
+}
+
+func (b *Block) Proposals(ctx context.Context) ([]*Proposal, error) {
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proposals, err := b.r.ppos.ListProposal(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Proposal, 0, len(proposals))
+	for _, p := range proposals {
+		ret = append(ret, &Proposal{
+			r:        b.r,
+			at:       at,
+			proposal: p,
+		})
+	}
+	return ret, nil
+}
+
+func (b *Block) Proposal(ctx context.Context, args struct{ Id common.Hash }) (*Proposal, error) {
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, err := b.r.ppos.GetProposal(ctx, args.Id, at)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return &Proposal{
+		r:        b.r,
+		at:       at,
+		proposal: p,
+	}, nil
+}
+
+func (b *Block) ActiveVersion(ctx context.Context) (Long, error) {
+	at, err := b.pposBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	version, err := b.r.ppos.GetActiveVersion(ctx, at)
+	return Long(version), err
+}
+
+// Candidate represents a node that staked to take part in the consensus.
+type Candidate struct {
+	r         *Resolver
+	candidate *staking.CandidateHex
+}
+
+func (c *Candidate) NodeId(ctx context.Context) string {
+	return c.candidate.NodeId.String()
+}
+
+func (c *Candidate) BlsPubKey(ctx context.Context) hexutil.Bytes {
+	return hexutil.Bytes(c.candidate.BlsPubKey[:])
+}
+
+func (c *Candidate) Staker(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		r:           c.r,
+		address:     c.candidate.StakingAddress,
+		blockNumber: args.Number(),
+	}
+}
+
+func (c *Candidate) Benefit(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		r:           c.r,
+		address:     c.candidate.BenefitAddress,
+		blockNumber: args.Number(),
+	}
+}
+
+func (c *Candidate) StakingTxIndex(ctx context.Context) int32 {
+	return int32(c.candidate.StakingTxIndex)
+}
+
+func (c *Candidate) ProgramVersion(ctx context.Context) Long {
+	return Long(c.candidate.ProgramVersion)
+}
+
+func (c *Candidate) Status(ctx context.Context) Long {
+	return Long(c.candidate.Status)
+}
+
+func (c *Candidate) StakingEpoch(ctx context.Context) Long {
+	return Long(c.candidate.StakingEpoch)
+}
+
+func (c *Candidate) StakingBlockNum(ctx context.Context) Long {
+	return Long(c.candidate.StakingBlockNum)
+}
+
+func (c *Candidate) Shares(ctx context.Context) hexutil.Big {
+	return bigOrZero(c.candidate.Shares)
+}
+
+func (c *Candidate) Released(ctx context.Context) hexutil.Big {
+	return bigOrZero(c.candidate.Released)
+}
+
+func (c *Candidate) ReleasedHes(ctx context.Context) hexutil.Big {
+	return bigOrZero(c.candidate.ReleasedHes)
+}
+
+func (c *Candidate) RestrictingPlan(ctx context.Context) hexutil.Big {
+	return bigOrZero(c.candidate.RestrictingPlan)
+}
+
+func (c *Candidate) RestrictingPlanHes(ctx context.Context) hexutil.Big {
+	return bigOrZero(c.candidate.RestrictingPlanHes)
+}
+
+func (c *Candidate) ExternalId(ctx context.Context) string {
+	return c.candidate.ExternalId
+}
+
+func (c *Candidate) NodeName(ctx context.Context) string {
+	return c.candidate.NodeName
+}
+
+func (c *Candidate) Website(ctx context.Context) string {
+	return c.candidate.Website
+}
+
+func (c *Candidate) Details(ctx context.Context) string {
+	return c.candidate.Details
+}
+
+// Validator represents a node elected to verify or produce blocks.
+type Validator struct {
+	r         *Resolver
+	at        rpc.BlockNumberOrHash
+	validator *staking.ValidatorEx
+}
+
+func (v *Validator) NodeId(ctx context.Context) string {
+	return v.validator.NodeId.String()
+}
+
+func (v *Validator) BlsPubKey(ctx context.Context) hexutil.Bytes {
+	return hexutil.Bytes(v.validator.BlsPubKey[:])
+}
+
+func (v *Validator) Staker(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		r:           v.r,
+		address:     v.validator.StakingAddress,
+		blockNumber: args.Number(),
+	}
+}
+
+func (v *Validator) Benefit(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		r:           v.r,
+		address:     v.validator.BenefitAddress,
+		blockNumber: args.Number(),
+	}
+}
+
+func (v *Validator) StakingTxIndex(ctx context.Context) int32 {
+	return int32(v.validator.StakingTxIndex)
+}
+
+func (v *Validator) ProgramVersion(ctx context.Context) Long {
+	return Long(v.validator.ProgramVersion)
+}
+
+func (v *Validator) StakingBlockNum(ctx context.Context) Long {
+	return Long(v.validator.StakingBlockNum)
+}
+
+func (v *Validator) Shares(ctx context.Context) hexutil.Big {
+	return bigOrZero(v.validator.Shares)
+}
+
+func (v *Validator) ValidatorTerm(ctx context.Context) Long {
+	return Long(v.validator.ValidatorTerm)
+}
+
+func (v *Validator) ExternalId(ctx context.Context) string {
+	return v.validator.ExternalId
+}
+
+func (v *Validator) NodeName(ctx context.Context) string {
+	return v.validator.NodeName
+}
+
+func (v *Validator) Website(ctx context.Context) string {
+	return v.validator.Website
+}
+
+func (v *Validator) Details(ctx context.Context) string {
+	return v.validator.Details
+}
+
+func (v *Validator) Candidate(ctx context.Context) (*Candidate, error) {
+	return getCandidate(ctx, v.r, v.validator.NodeId, v.at)
+}
+
+// Delegation represents the von an account delegated to a candidate.
+type Delegation struct {
+	r          *Resolver
+	at         rpc.BlockNumberOrHash
+	delegation *staking.DelegationEx
+}
+
+func (d *Delegation) Delegator(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		r:           d.r,
+		address:     d.delegation.Addr,
+		blockNumber: args.Number(),
+	}
+}
+
+func (d *Delegation) NodeId(ctx context.Context) string {
+	return d.delegation.NodeId.String()
+}
+
+func (d *Delegation) StakingBlockNum(ctx context.Context) Long {
+	return Long(d.delegation.StakingBlockNum)
+}
+
+func (d *Delegation) DelegateEpoch(ctx context.Context) Long {
+	return Long(d.delegation.DelegateEpoch)
+}
+
+func (d *Delegation) Released(ctx context.Context) hexutil.Big {
+	return bigOrZero(d.delegation.Released)
+}
+
+func (d *Delegation) ReleasedHes(ctx context.Context) hexutil.Big {
+	return bigOrZero(d.delegation.ReleasedHes)
+}
+
+func (d *Delegation) RestrictingPlan(ctx context.Context) hexutil.Big {
+	return bigOrZero(d.delegation.RestrictingPlan)
+}
+
+func (d *Delegation) RestrictingPlanHes(ctx context.Context) hexutil.Big {
+	return bigOrZero(d.delegation.RestrictingPlanHes)
+}
+
+func (d *Delegation) Candidate(ctx context.Context) (*Candidate, error) {
+	return getCandidate(ctx, d.r, d.delegation.NodeId, d.at)
+}
+
+// Proposal represents a governance proposal.
+type Proposal struct {
+	r        *Resolver
+	at       rpc.BlockNumberOrHash
+	proposal gov.Proposal
+}
+
+func (p *Proposal) Id(ctx context.Context) common.Hash {
+	return p.proposal.GetProposalID()
+}
+
+func (p *Proposal) Type(ctx context.Context) int32 {
+	return int32(p.proposal.GetProposalType())
+}
+
+func (p *Proposal) PipId(ctx context.Context) string {
+	return p.proposal.GetPIPID()
+}
+
+func (p *Proposal) Proposer(ctx context.Context) string {
+	return p.proposal.GetProposer().String()
+}
+
+func (p *Proposal) SubmitBlock(ctx context.Context) Long {
+	return Long(p.proposal.GetSubmitBlock())
+}
+
+func (p *Proposal) EndVotingBlock(ctx context.Context) Long {
+	return Long(p.proposal.GetEndVotingBlock())
+}
+
+func (p *Proposal) TallyResult(ctx context.Context) (*TallyResult, error) {
+	result, err := p.r.ppos.GetTallyResult(ctx, p.proposal.GetProposalID(), p.at)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return &TallyResult{result}, nil
+}
+
+// TallyResult represents the outcome of the voting on a proposal.
+type TallyResult struct {
+	result *gov.TallyResult
+}
+
+func (t *TallyResult) Yeas(ctx context.Context) int32 {
+	return int32(t.result.Yeas)
+}
+
+func (t *TallyResult) Nays(ctx context.Context) int32 {
+	return int32(t.result.Nays)
+}
+
+func (t *TallyResult) Abstentions(ctx context.Context) int32 {
+	return int32(t.result.Abstentions)
+}
+
+func (t *TallyResult) AccuVerifiers(ctx context.Context) int32 {
+	return int32(t.result.AccuVerifiers)
+}
+
+func (t *TallyResult) Status(ctx context.Context) int32 {
+	return int32(t.result.Status)
+}
+
+func (t *TallyResult) CanceledBy(ctx context.Context) common.Hash {
+	return t.result.CanceledBy
+}
+
+// RestrictingPlan represents the restricted balance of an account.
+type RestrictingPlan struct {
+	result *restricting.Result
+}
+
+func (p *RestrictingPlan) Balance(ctx context.Context) hexutil.Big {
+	return bigOrZero(p.result.Balance)
+}
+
+func (p *RestrictingPlan) Debt(ctx context.Context) hexutil.Big {
+	return bigOrZero(p.result.Debt)
+}
+
+func (p *RestrictingPlan) Pledge(ctx context.Context) hexutil.Big {
+	return bigOrZero(p.result.Pledge)
+}
+
+func (p *RestrictingPlan) Releases(ctx context.Context) []*Release {
+	ret := make([]*Release, 0, len(p.result.Entry))
+	for _, entry := range p.result.Entry {
+		ret = append(ret, &Release{entry})
+	}
+	return ret
+}
+
+// Release represents a release of restricted balance.
+type Release struct {
+	info restricting.ReleaseAmountInfo
+}
+
+func (r *Release) Block(ctx context.Context) Long {
+	return Long(r.info.Height)
+}
+
+func (r *Release) Amount(ctx context.Context) hexutil.Big {
+	return bigOrZero(r.info.Amount)
+}
@@ -0,0 +1,499 @@
+// Copyright 2018-2019 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+const schema string = `
+    # Bytes32 is a 32 byte binary string, represented as 0x-prefixed hexadecimal.
+    scalar Bytes32
+    # Address is a 20 byte PlatON address, represented as 0x-prefixed hexadecimal.
+    scalar Address
+    # Bytes is an arbitrary length binary string, represented as 0x-prefixed hexadecimal.
+    # An empty byte string is represented as '0x'. Byte strings must have an even number of hexadecimal nybbles.
+    scalar Bytes
+    # BigInt is a large integer. Input is accepted as either a JSON number or as a string.
+    # Strings may be either decimal or 0x-prefixed hexadecimal. Output values are all
+    # 0x-prefixed hexadecimal.
+    scalar BigInt
+    # Long is a 64 bit unsigned integer.
+    scalar Long
+
+    schema {
+        query: Query
+        mutation: Mutation
+    }
+
+    # Account is a PlatON account at a particular block.
+    type Account {
+        # Address is the address owning the account.
+        address: Address!
+        # Balance is the balance of the account, in von.
+        balance: BigInt!
+        # TransactionCount is the number of transactions sent from this account,
+        # or in the case of a contract, the number of contracts created. Otherwise
+        # known as the nonce.
+        transactionCount: Long!
+        # Code contains the smart contract code for this account, if the account
+        # is a (non-self-destructed) contract.
+        code: Bytes!
+        # Storage provides access to the storage of a contract account, indexed
+        # by its 32 byte slot identifier.
+        storage(slot: Bytes32!): Bytes!
+        # Restricting is the restricting plan of the account, or null if it has none.
+        restricting: RestrictingPlan
+        # Delegations lists the delegations made by the account. Like the
+        # candidates of a block, they are only available from the last block
+        # committed to the PPOS snapshot database on.
+        delegations: [Delegation!]!
+    }
+
+    # Log is a PlatON event log.
+    type Log {
+        # Index is the index of this log in the block.
+        index: Int!
+        # Account is the account which generated this log - this will always
+        # be a contract account.
+        account(block: Long): Account!
+        # Topics is a list of 0-4 indexed topics for the log.
+        topics: [Bytes32!]!
+        # Data is unindexed data for this log.
+        data: Bytes!
+        # Transaction is the transaction that generated this log entry.
+        transaction: Transaction!
+    }
+
+    # Transaction is a PlatON transaction.
+    type Transaction {
+        # Hash is the hash of this transaction.
+        hash: Bytes32!
+        # Type is the envelope type of the transaction: 0 for legacy, 1 for
+        # sponsored and 2 for batch transactions.
+        type: Int!
+        # Nonce is the nonce of the account this transaction was generated with.
+        nonce: Long!
+        # Index is the index of this transaction in the parent block. This will
+        # be null if the transaction has not yet been mined.
+        index: Int
+        # From is the account that sent this transaction - this will always be
+        # an externally owned account.
+        from(block: Long): Account!
+        # To is the account the transaction was sent to. This is null for
+        # contract-creating and batch transactions.
+        to(block: Long): Account
+        # Payer is the account paying the gas of a sponsored transaction. This
+        # is null for other transactions.
+        payer(block: Long): Account
+        # Value is the value, in von, sent along with this transaction.
+        value: BigInt!
+        # GasPrice is the price offered to miners for gas, in von per unit.
+        gasPrice: BigInt!
+        # Gas is the maximum amount of gas this transaction can consume.
+        gas: Long!
+        # InputData is the data supplied to the target of the transaction.
+        inputData: Bytes!
+        # Calls are the calls executed by a batch transaction. This is null
+        # for other transactions.
+        calls: [BatchCall!]
+        # Block is the block this transaction was mined in. This will be null if
+        # the transaction has not yet been mined.
+        block: Block
+
+        # Status is the return status of the transaction. This will be 1 if the
+        # transaction succeeded, or 0 if it failed (due to a revert, or due to
+        # running out of gas). If the transaction has not yet been mined, this
+        # field will be null.
+        status: Long
+        # GasUsed is the amount of gas that was used processing this transaction.
+        # If the transaction has not yet been mined, this field will be null.
+        gasUsed: Long
+        # CumulativeGasUsed is the total gas used in the block up to and including
+        # this transaction. If the transaction has not yet been mined, this field
+        # will be null.
+        cumulativeGasUsed: Long
+        # CreatedContract is the account that was created by a contract creation
+        # transaction. If the transaction was not a contract creation transaction,
+        # or it has not yet been mined, this field will be null.
+        createdContract(block: Long): Account
+        # Logs is a list of log entries emitted by this transaction. If the
+        # transaction has not yet been mined, this field will be null.
+        logs: [Log!]
+        # RevertData is the payload the transaction reverted with. This is null
+        # if the transaction did not revert or has not yet been mined.
+        revertData: Bytes
+    }
+
+    # BatchCall is one of the calls executed by a batch transaction.
+    type BatchCall {
+        # Index is the index of the call in the batch.
+        index: Int!
+        # To is the account the call was made to.
+        to(block: Long): Account!
+        # Value is the value, in von, sent along with the call.
+        value: BigInt!
+        # InputData is the data supplied to the target of the call.
+        inputData: Bytes!
+        # Status is the return status of the call. This will be null if the
+        # transaction has not yet been mined.
+        status: Long
+        # GasUsed is the amount of gas used by the call. This will be null if
+        # the transaction has not yet been mined.
+        gasUsed: Long
+        # Logs is a list of log entries emitted by the call. This will be null
+        # if the transaction has not yet been mined.
+        logs: [Log!]
+        # RevertData is the payload the call reverted with, if it did.
+        revertData: Bytes
+    }
+
+    # BlockFilterCriteria encapsulates log filter criteria for a filter applied
+    # to a single block.
+    input BlockFilterCriteria {
+        # Addresses is list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        topics: [[Bytes32!]!]
+    }
+
+    # Block is a PlatON block.
+    type Block {
+        # Number is the number of this block, starting at 0 for the genesis block.
+        number: Long!
+        # Hash is the block hash of this block.
+        hash: Bytes32!
+        # Parent is the parent block of this block.
+        parent: Block
+        # Nonce is the block nonce, which carries the VRF proof of the producer.
+        nonce: Bytes!
+        # TransactionsRoot is the keccak256 hash of the root of the trie of transactions in this block.
+        transactionsRoot: Bytes32!
+        # TransactionCount is the number of transactions in this block. if
+        # transactions are not available for this block, this field will be null.
+        transactionCount: Int
+        # StateRoot is the keccak256 hash of the state trie after this block was processed.
+        stateRoot: Bytes32!
+        # ReceiptsRoot is the keccak256 hash of the trie of transaction receipts in this block.
+        receiptsRoot: Bytes32!
+        # Miner is the account that produced this block.
+        miner(block: Long): Account!
+        # ExtraData is an arbitrary data field supplied by the producer.
+        extraData: Bytes!
+        # GasLimit is the maximum amount of gas that was available to transactions in this block.
+        gasLimit: Long!
+        # GasUsed is the amount of gas that was used executing transactions in this block.
+        gasUsed: Long!
+        # Timestamp is the unix timestamp, in milliseconds, at which this block was produced.
+        timestamp: BigInt!
+        # LogsBloom is a bloom filter that can be used to check if a block may
+        # contain log entries matching a filter.
+        logsBloom: Bytes!
+        # Transactions is a list of transactions associated with this block. If
+        # transactions are unavailable for this block, this field will be null.
+        transactions: [Transaction!]
+        # TransactionAt returns the transaction at the specified index. If
+        # transactions are unavailable for this block, or if the index is out of
+        # bounds, this field will be null.
+        transactionAt(index: Int!): Transaction
+        # Logs returns a filtered set of logs from this block.
+        logs(filter: BlockFilterCriteria!): [Log!]!
+        # Account fetches a PlatON account at the current block's state.
+        account(address: Address!): Account!
+        # Call executes a local call operation at the current block's state.
+        call(data: CallData!): CallResult
+        # EstimateGas estimates the amount of gas that will be required for
+        # successful execution of a transaction at the current block's state.
+        estimateGas(data: CallData!): Long!
+
+        # Candidates, verifiers, validators and the proposal list are only
+        # available from the last block committed to the PPOS snapshot
+        # database on, older blocks fail to resolve them.
+
+        # Candidates lists the staking candidates as of this block.
+        candidates: [Candidate!]!
+        # Candidate returns the candidate of the node, or null if it has none.
+        candidate(nodeId: String!): Candidate
+        # Verifiers lists the verifiers of the settlement epoch of this block.
+        verifiers: [Validator!]!
+        # Validators lists the validators of the consensus round of this block.
+        validators: [Validator!]!
+        # Proposals lists the governance proposals as of this block.
+        proposals: [Proposal!]!
+        # Proposal returns the governance proposal with the id, or null if
+        # there is none.
+        proposal(id: Bytes32!): Proposal
+        # ActiveVersion is the version active on the chain as of this block.
+        activeVersion: Long!
+    }
+
+    # CallData represents the data associated with a local contract call.
+    # All fields are optional.
+    input CallData {
+        # From is the address making the call.
+        from: Address
+        # To is the address the call is sent to.
+        to: Address
+        # Gas is the amount of gas sent with the call.
+        gas: Long
+        # GasPrice is the price, in von, offered for each unit of gas.
+        gasPrice: BigInt
+        # Value is the value, in von, sent along with the call.
+        value: BigInt
+        # Data is the data sent to the callee.
+        data: Bytes
+    }
+
+    # CallResult is the result of a local call operation.
+    type CallResult {
+        # Data is the return data of the called contract.
+        data: Bytes!
+        # GasUsed is the amount of gas used by the call, after any refunds.
+        gasUsed: Long!
+        # Status is the result of the call - 1 for success or 0 for failure.
+        status: Long!
+    }
+
+    # FilterCriteria encapsulates log filter criteria for searching log entries.
+    input FilterCriteria {
+        # FromBlock is the block at which to start searching, inclusive. Defaults
+        # to the latest block if not supplied.
+        fromBlock: Long
+        # ToBlock is the block at which to stop searching, inclusive. Defaults
+        # to the latest block if not supplied.
+        toBlock: Long
+        # Addresses is a list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        topics: [[Bytes32!]!]
+    }
+
+    # Candidate is a node that staked to take part in the consensus.
+    type Candidate {
+        # NodeId is the id of the node, as hexadecimal.
+        nodeId: String!
+        # BlsPubKey is the BLS public key of the node.
+        blsPubKey: Bytes!
+        # Staker is the account that made the staking.
+        staker(block: Long): Account!
+        # Benefit is the account receiving the rewards of the node.
+        benefit(block: Long): Account!
+        # StakingTxIndex is the index of the staking transaction in its block.
+        stakingTxIndex: Int!
+        # ProgramVersion is the version of the node program.
+        programVersion: Long!
+        # Status is the status bitmap of the candidate.
+        status: Long!
+        # StakingEpoch is the settlement epoch the staking was last changed in.
+        stakingEpoch: Long!
+        # StakingBlockNum is the number of the block the staking was made in.
+        stakingBlockNum: Long!
+        # Shares is the total of the staked and delegated von.
+        shares: BigInt!
+        # Released is the free balance staked, in effect.
+        released: BigInt!
+        # ReleasedHes is the free balance staked, in hesitation.
+        releasedHes: BigInt!
+        # RestrictingPlan is the restricted balance staked, in effect.
+        restrictingPlan: BigInt!
+        # RestrictingPlanHes is the restricted balance staked, in hesitation.
+        restrictingPlanHes: BigInt!
+        # ExternalId is the id third parties pull the node description with.
+        externalId: String!
+        # NodeName is the name of the node.
+        nodeName: String!
+        # Website is the home page of the node.
+        website: String!
+        # Details is the description of the node.
+        details: String!
+    }
+
+    # Validator is a node elected to verify or produce blocks.
+    type Validator {
+        # NodeId is the id of the node, as hexadecimal.
+        nodeId: String!
+        # BlsPubKey is the BLS public key of the node.
+        blsPubKey: Bytes!
+        # Staker is the account that made the staking.
+        staker(block: Long): Account!
+        # Benefit is the account receiving the rewards of the node.
+        benefit(block: Long): Account!
+        # StakingTxIndex is the index of the staking transaction in its block.
+        stakingTxIndex: Int!
+        # ProgramVersion is the version of the node program.
+        programVersion: Long!
+        # StakingBlockNum is the number of the block the staking was made in.
+        stakingBlockNum: Long!
+        # Shares is the total of the staked and delegated von.
+        shares: BigInt!
+        # ValidatorTerm is the number of consecutive consensus rounds the node
+        # has been a validator in.
+        validatorTerm: Long!
+        # ExternalId is the id third parties pull the node description with.
+        externalId: String!
+        # NodeName is the name of the node.
+        nodeName: String!
+        # Website is the home page of the node.
+        website: String!
+        # Details is the description of the node.
+        details: String!
+        # Candidate is the candidate of the node, or null if it withdrew.
+        candidate: Candidate
+    }
+
+    # Delegation is the von an account delegated to a candidate.
+    type Delegation {
+        # Delegator is the account that made the delegation.
+        delegator(block: Long): Account!
+        # NodeId is the id of the node delegated to, as hexadecimal.
+        nodeId: String!
+        # StakingBlockNum is the number of the block the staking delegated to
+        # was made in.
+        stakingBlockNum: Long!
+        # DelegateEpoch is the settlement epoch the delegation was last changed in.
+        delegateEpoch: Long!
+        # Released is the free balance delegated, in effect.
+        released: BigInt!
+        # ReleasedHes is the free balance delegated, in hesitation.
+        releasedHes: BigInt!
+        # RestrictingPlan is the restricted balance delegated, in effect.
+        restrictingPlan: BigInt!
+        # RestrictingPlanHes is the restricted balance delegated, in hesitation.
+        restrictingPlanHes: BigInt!
+        # Candidate is the candidate delegated to, or null if it withdrew.
+        candidate: Candidate
+    }
+
+    # Proposal is a governance proposal.
+    type Proposal {
+        # Id is the id of the proposal.
+        id: Bytes32!
+        # Type is the type of the proposal.
+        type: Int!
+        # PipId is the PIP number of the proposal.
+        pipId: String!
+        # Proposer is the id of the node that submitted the proposal, as hexadecimal.
+        proposer: String!
+        # SubmitBlock is the number of the block the proposal was submitted in.
+        submitBlock: Long!
+        # EndVotingBlock is the number of the block the voting ends at.
+        endVotingBlock: Long!
+        # TallyResult is the tally of the proposal, or null if its voting has
+        # not ended yet.
+        tallyResult: TallyResult
+    }
+
+    # TallyResult is the outcome of the voting on a proposal.
+    type TallyResult {
+        # Yeas is the number of yes votes.
+        yeas: Int!
+        # Nays is the number of no votes.
+        nays: Int!
+        # Abstentions is the number of abstentions.
+        abstentions: Int!
+        # AccuVerifiers is the number of verifiers entitled to vote.
+        accuVerifiers: Int!
+        # Status is the status of the proposal.
+        status: Int!
+        # CanceledBy is the id of the proposal that canceled this one, if any.
+        canceledBy: Bytes32!
+    }
+
+    # RestrictingPlan is the restricted balance of an account.
+    type RestrictingPlan {
+        # Balance is the restricted balance not released yet.
+        balance: BigInt!
+        # Debt is the amount released while the balance was pledged, owed to
+        # the account.
+        debt: BigInt!
+        # Pledge is the restricted balance staked or delegated.
+        pledge: BigInt!
+        # Releases lists the releases still to come.
+        releases: [Release!]!
+    }
+
+    # Release is a release of restricted balance.
+    type Release {
+        # Block is the number of the block the amount is released at.
+        block: Long!
+        # Amount is the amount released.
+        amount: BigInt!
+    }
+
+    # SyncState contains the current synchronisation state of the client.
+    type SyncState {
+        # StartingBlock is the block number at which synchronisation started.
+        startingBlock: Long!
+        # CurrentBlock is the point at which synchronisation has presently reached.
+        currentBlock: Long!
+        # HighestBlock is the latest known block number.
+        highestBlock: Long!
+        # PulledStates is the number of state entries fetched so far, or null
+        # if this is not known or not relevant.
+        pulledStates: Long
+        # KnownStates is the number of states the node knows of so far, or null
+        # if this is not known or not relevant.
+        knownStates: Long
+    }
+
+    # Pending represents the current pending state.
+    type Pending {
+        # TransactionCount is the number of transactions in the pending state.
+        transactionCount: Int!
+        # Transactions is a list of transactions in the current pending state.
+        transactions: [Transaction!]
+        # Account fetches a PlatON account for the pending state.
+        account(address: Address!): Account!
+        # Call executes a local call operation for the pending state.
+        call(data: CallData!): CallResult
+        # EstimateGas estimates the amount of gas that will be required for
+        # successful execution of a transaction for the pending state.
+        estimateGas(data: CallData!): Long!
+    }
+
+    type Query {
+        # Block fetches a PlatON block by number or by hash. If neither is
+        # supplied, the most recent known block is returned.
+        block(number: Long, hash: Bytes32): Block
+        # Blocks returns all the blocks between two numbers, inclusive. If
+        # to is not supplied or is past the most recent known block, it
+        # defaults to that block. At most 1024 blocks are returned at once.
+        blocks(from: Long!, to: Long): [Block!]!
+        # Pending returns the current pending state.
+        pending: Pending!
+        # Transaction returns a transaction specified by its hash.
+        transaction(hash: Bytes32!): Transaction
+        # Logs returns log entries matching the provided filter.
+        logs(filter: FilterCriteria!): [Log!]!
+        # GasPrice returns the node's estimate of a gas price sufficient to
+        # ensure a transaction is mined in a timely fashion.
+        gasPrice: BigInt!
+        # ProtocolVersion returns the current wire protocol version number.
+        protocolVersion: Int!
+        # Syncing returns information on the current synchronisation state.
+        syncing: SyncState
+    }
+
+    type Mutation {
+        # SendRawTransaction sends an RLP-encoded transaction to the network.
+        sendRawTransaction(data: Bytes!): Bytes32!
+    }
+`
@@ -23,10 +23,12 @@ import (
 	"fmt"
 	"math/big"
 	"time"
+	"unicode/utf8"
 
 	"strings"
 
 	"github.com/PlatONnetwork/PlatON-Go/accounts"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/abi"
 	"github.com/PlatONnetwork/PlatON-Go/accounts/keystore"
 	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
@@ -133,8 +135,29 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	return content
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
+// txStatusNames are the names the pool statuses are reported under.
+var txStatusNames = map[core.TxStatus]string{
+	core.TxStatusUnknown:  "unknown",
+	core.TxStatusQueued:   "queued",
+	core.TxStatusPending:  "pending",
+	core.TxStatusIncluded: "included",
+}
+
+// RPCTxStatus is the status of a transaction in the pool, along with the
+// transactions that replaced it.
+type RPCTxStatus struct {
+	Status        string        `json:"status"`
+	Replacements  []common.Hash `json:"replacements"`
+	Current       common.Hash   `json:"current"`
+	CurrentStatus string        `json:"currentStatus"`
+}
+
+// Status returns the number of pending and queued transaction in the pool. Given
+// a transaction hash, it returns the status of that transaction instead.
+func (s *PublicTxPoolAPI) Status(hash *common.Hash) interface{} {
+	if hash != nil {
+		return s.txStatus(*hash)
+	}
 	pending, queue := s.b.Stats()
 	return map[string]hexutil.Uint{
 		"pending": hexutil.Uint(pending),
@@ -142,6 +165,31 @@ func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 	}
 }
 
+// txStatus reports the status of the transaction and of the one currently
+// standing for it in the pool, following its replacements.
+func (s *PublicTxPoolAPI) txStatus(hash common.Hash) *RPCTxStatus {
+	replacements := s.b.TxPoolReplacements(hash)
+	current := hash
+	if len(replacements) > 0 {
+		current = replacements[len(replacements)-1]
+	}
+	status := s.b.TxPoolStatus([]common.Hash{hash, current})
+
+	result := &RPCTxStatus{
+		Status:        txStatusNames[status[0]],
+		Replacements:  replacements,
+		Current:       current,
+		CurrentStatus: txStatusNames[status[1]],
+	}
+	if result.Replacements == nil {
+		result.Replacements = []common.Hash{}
+	}
+	if len(replacements) > 0 && status[0] == core.TxStatusUnknown {
+		result.Status = "replaced"
+	}
+	return result
+}
+
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
 func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
@@ -374,7 +422,7 @@ func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args SendTxArgs
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return submitTransaction(ctx, s.b, signed)
+	return SubmitTransaction(ctx, s.b, signed)
 }
 
 // SignTransaction will create a transaction from the given arguments and
@@ -536,6 +584,9 @@ func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.
 		}
 		return response, err
 	}
+	if err == nil {
+		err = historyPruned(s.b, blockNr)
+	}
 	return nil, err
 }
 
@@ -546,9 +597,40 @@ func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash comm
 	if block != nil {
 		return s.rpcOutputBlock(block, true, fullTx)
 	}
+	if err == nil {
+		err = historyPrunedByHash(s.b, blockHash)
+	}
 	return nil, err
 }
 
+// historyPruned returns core.ErrHistoryPruned if the body and receipts of the
+// canonical block with the given number were removed by history expiry.
+func historyPruned(b Backend, blockNr rpc.BlockNumber) error {
+	if blockNr > 0 && uint64(blockNr) < rawdb.ReadOldestRetainedBlock(b.ChainDb()) {
+		return core.ErrHistoryPruned
+	}
+	return nil
+}
+
+// historyPrunedByHash returns core.ErrHistoryPruned if the body and receipts of
+// the block with the given hash were removed by history expiry.
+func historyPrunedByHash(b Backend, hash common.Hash) error {
+	if number := rawdb.ReadHeaderNumber(b.ChainDb(), hash); number != nil {
+		return historyPruned(b, rpc.BlockNumber(*number))
+	}
+	return nil
+}
+
+// transactionPruned returns core.ErrHistoryPruned if the transaction with the
+// given hash was included in a block whose history was removed by history
+// expiry. The transaction lookups outlive the pruned history for this.
+func transactionPruned(b Backend, hash common.Hash) error {
+	if blockHash, number, _ := rawdb.ReadTxLookupEntry(b.ChainDb(), hash); blockHash != (common.Hash{}) {
+		return historyPruned(b, rpc.BlockNumber(number))
+	}
+	return nil
+}
+
 // GetCode returns the code stored at the given address in the state for the given block number.
 func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
@@ -581,17 +663,19 @@ type CallArgs struct {
 	Data     hexutil.Bytes   `json:"data"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
+// DoCall executes the call message against the state of the given block and
+// returns its result, the gas used and whether the execution failed.
+func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
-	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, 0, false, err
 	}
 	// Set sender address or use a default if none specified
 	addr := args.From
 	if addr == (common.Address{}) {
-		if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
+		if wallets := b.AccountManager().Wallets(); len(wallets) > 0 {
 			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
 				addr = accounts[0].Address
 			}
@@ -622,7 +706,7 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	defer cancel()
 
 	// Get a new instance of the EVM.
-	evm, vmError, err := s.b.GetEVM(ctx, msg, state, header, vmCfg)
+	evm, vmError, err := b.GetEVM(ctx, msg, state, header, vmCfg)
 	if err != nil {
 		return nil, 0, false, err
 	}
@@ -646,13 +730,72 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
+	result, _, failed, err := DoCall(ctx, s.b, args, blockNr, vm.Config{}, 5*time.Second)
+	if err == nil && failed && len(result) > 0 {
+		return nil, newRevertError(result)
+	}
 	return (hexutil.Bytes)(result), err
 }
 
+// revertSelector is the selector of the Error(string) payload of a Solidity revert.
+var revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// revertError is returned by eth_call when the contract reverted, either
+// through the EVM REVERT opcode or the WASM platonRevert import. The raw
+// revert payload is returned as error data.
+type revertError struct {
+	error
+	data hexutil.Bytes
+}
+
+func newRevertError(data []byte) *revertError {
+	err := errors.New("execution reverted")
+	if reason := revertReason(data); reason != "" {
+		err = fmt.Errorf("execution reverted: %v", reason)
+	}
+	return &revertError{error: err, data: common.CopyBytes(data)}
+}
+
+// ErrorCode returns the JSON error code for a revertal.
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData returns the revert payload.
+func (e *revertError) ErrorData() interface{} {
+	return e.data
+}
+
+// revertReason returns a human readable reason of a revert payload. EVM
+// payloads are ABI encoded Error(string) values, WASM payloads are the raw
+// message passed to platonRevert.
+func revertReason(data []byte) string {
+	if bytes.HasPrefix(data, revertSelector) {
+		typ, err := abi.NewType("string")
+		if err != nil {
+			return ""
+		}
+		var reason string
+		if err := (abi.Arguments{{Type: typ}}).Unpack(&reason, data[4:]); err != nil {
+			return ""
+		}
+		return reason
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return ""
+}
+
 // EstimateGas returns an estimate of the amount of gas needed to execute the
 // given transaction against the current pending block.
 func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+	return DoEstimateGas(ctx, s.b, args, rpc.PendingBlockNumber)
+}
+
+// DoEstimateGas binary searches the lowest gas allowance the call executes
+// with against the state of the given block.
+func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -662,8 +805,8 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	if uint64(args.Gas) >= params.TxGas {
 		hi = uint64(args.Gas)
 	} else {
-		// Retrieve the current pending block to act as the gas ceiling
-		block, err := s.b.BlockByNumber(ctx, rpc.PendingBlockNumber)
+		// Retrieve the block to act as the gas ceiling
+		block, err := b.BlockByNumber(ctx, blockNr)
 		if err != nil {
 			return 0, err
 		}
@@ -675,7 +818,7 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	executable := func(gas uint64) bool {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{}, 0)
+		_, _, failed, err := DoCall(ctx, b, args, blockNr, vm.Config{}, 0)
 		if err != nil || failed {
 			return false
 		}
@@ -709,6 +852,16 @@ type ExecutionResult struct {
 	StructLogs  []StructLogRes `json:"structLogs"`
 }
 
+// WasmExecutionResult groups all structured logs emitted by the WASM
+// interpreter while replaying a transaction in debug mode as well as
+// transaction execution status, the amount of gas used and the return value
+type WasmExecutionResult struct {
+	Gas         uint64             `json:"gas"`
+	Failed      bool               `json:"failed"`
+	ReturnValue string             `json:"returnValue"`
+	StructLogs  []vm.WasmStructLog `json:"structLogs"`
+}
+
 // StructLogRes stores a structured log emitted by the EVM while replaying a
 // transaction in debug mode
 type StructLogRes struct {
@@ -817,26 +970,31 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        common.Hash     `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
+	BlockHash        common.Hash       `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Gas              hexutil.Uint64    `json:"gas"`
+	GasPrice         *hexutil.Big      `json:"gasPrice"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	TransactionIndex hexutil.Uint      `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	V                *hexutil.Big      `json:"v"`
+	R                *hexutil.Big      `json:"r"`
+	S                *hexutil.Big      `json:"s"`
+	Payer            *common.Address   `json:"payer,omitempty"`
+	PayerV           *hexutil.Big      `json:"payerV,omitempty"`
+	PayerR           *hexutil.Big      `json:"payerR,omitempty"`
+	PayerS           *hexutil.Big      `json:"payerS,omitempty"`
+	Calls            []types.BatchCall `json:"calls,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
 // representation, with the given location metadata set (if available).
 func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
-	var signer types.Signer = types.NewEIP155Signer(tx.ChainId())
+	var signer types.Signer = types.NewBatchSigner(tx.ChainId())
 	from, _ := types.Sender(signer, tx)
 	v, r, s := tx.RawSignatureValues()
 
@@ -853,6 +1011,12 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
 	}
+	if tx.Sponsored() {
+		v, r, s := tx.RawPayerSignatureValues()
+		result.Payer = tx.Sponsor()
+		result.PayerV, result.PayerR, result.PayerS = (*hexutil.Big)(v), (*hexutil.Big)(r), (*hexutil.Big)(s)
+	}
+	result.Calls = tx.Calls()
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -907,53 +1071,53 @@ func NewPublicTransactionPoolAPI(b Backend, nonceLock *AddrLocker) *PublicTransa
 }
 
 // GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
-func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
+func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*hexutil.Uint, error) {
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
 		n := hexutil.Uint(len(block.Transactions()))
-		return &n
+		return &n, nil
 	}
-	return nil
+	return nil, historyPruned(s.b, blockNr)
 }
 
 // GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
-func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint {
+func (s *PublicTransactionPoolAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) (*hexutil.Uint, error) {
 	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
 		n := hexutil.Uint(len(block.Transactions()))
-		return &n
+		return &n, nil
 	}
-	return nil
+	return nil, historyPrunedByHash(s.b, blockHash)
 }
 
 // GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
-func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
+func (s *PublicTransactionPoolAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) (*RPCTransaction, error) {
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index))
+		return newRPCTransactionFromBlockIndex(block, uint64(index)), nil
 	}
-	return nil
+	return nil, historyPruned(s.b, blockNr)
 }
 
 // GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
-func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) *RPCTransaction {
+func (s *PublicTransactionPoolAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) (*RPCTransaction, error) {
 	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
-		return newRPCTransactionFromBlockIndex(block, uint64(index))
+		return newRPCTransactionFromBlockIndex(block, uint64(index)), nil
 	}
-	return nil
+	return nil, historyPrunedByHash(s.b, blockHash)
 }
 
 // GetRawTransactionByBlockNumberAndIndex returns the bytes of the transaction for the given block number and index.
-func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) hexutil.Bytes {
+func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) (hexutil.Bytes, error) {
 	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
-		return newRPCRawTransactionFromBlockIndex(block, uint64(index))
+		return newRPCRawTransactionFromBlockIndex(block, uint64(index)), nil
 	}
-	return nil
+	return nil, historyPruned(s.b, blockNr)
 }
 
 // GetRawTransactionByBlockHashAndIndex returns the bytes of the transaction for the given block hash and index.
-func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) hexutil.Bytes {
+func (s *PublicTransactionPoolAPI) GetRawTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) (hexutil.Bytes, error) {
 	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
-		return newRPCRawTransactionFromBlockIndex(block, uint64(index))
+		return newRPCRawTransactionFromBlockIndex(block, uint64(index)), nil
 	}
-	return nil
+	return nil, historyPrunedByHash(s.b, blockHash)
 }
 
 // GetTransactionCount returns the number of transactions the given address has sent for the given block number
@@ -967,17 +1131,129 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 }
 
 // GetTransactionByHash returns the transaction for the given hash
-func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *RPCTransaction {
+func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, error) {
 	// Try to return an already finalized transaction
 	if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash); tx != nil {
-		return newRPCTransaction(tx, blockHash, blockNumber, index)
+		return newRPCTransaction(tx, blockHash, blockNumber, index), nil
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
-		return newRPCPendingTransaction(tx)
+		return newRPCPendingTransaction(tx), nil
 	}
 	// Transaction unknown, return as such
-	return nil
+	return nil, transactionPruned(s.b, hash)
+}
+
+// addressTxPageSize is the number of transactions a page of
+// GetTransactionsByAddress holds.
+const addressTxPageSize = 100
+
+// errAddressIndexNotReady is returned if the address transaction index lags too
+// far behind the requested blocks for them to be scanned directly.
+var errAddressIndexNotReady = errors.New("address transaction index not ready")
+
+// GetTransactionsByAddress returns a page of the canonical transactions in the
+// given block range the address took part in as sender, recipient or delegation
+// target, in chain order. It requires the node to run the address transaction
+// index; blocks the index has not covered yet are scanned directly, as long as
+// they are no more than a synced index leaves behind.
+func (s *PublicTransactionPoolAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, page hexutil.Uint) ([]*RPCTransaction, error) {
+	size, sections := s.b.AddressIndexStatus()
+	if size == 0 {
+		return nil, errors.New("address transaction index is disabled")
+	}
+	head := s.b.CurrentBlock().NumberU64()
+	from, to := uint64(fromBlock), uint64(toBlock)
+	if fromBlock < 0 {
+		from = head
+	}
+	if toBlock < 0 || to > head {
+		to = head
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	// A synced index leaves less than a section and its confirmations unindexed
+	if tail := sections * size; to >= tail {
+		if from > tail {
+			tail = from
+		}
+		if to-tail >= 2*size {
+			return nil, errAddressIndexNotReady
+		}
+	}
+	var (
+		db    = s.b.ChainDb()
+		skip  = uint64(page) * addressTxPageSize
+		txs   = make([]*RPCTransaction, 0)
+		block *types.Block
+	)
+	// collect adds the transaction at the given position to the page, returning
+	// false once the page is full.
+	collect := func(number, index uint64) (bool, error) {
+		if skip > 0 {
+			skip--
+			return true, nil
+		}
+		if block == nil || block.NumberU64() != number {
+			var err error
+			if block, err = s.b.BlockByNumber(ctx, rpc.BlockNumber(number)); block == nil {
+				if err := historyPruned(s.b, rpc.BlockNumber(number)); err != nil {
+					return false, err
+				}
+				return false, fmt.Errorf("block #%d not found: %v", number, err)
+			}
+		}
+		if tx := newRPCTransactionFromBlockIndex(block, index); tx != nil {
+			txs = append(txs, tx)
+		}
+		return len(txs) < addressTxPageSize, nil
+	}
+	// Serve the indexed sections from the index, keyed by their canonical head
+	next := from
+	for section := from / size; section < sections && section*size <= to; section++ {
+		sectionHead := rawdb.ReadCanonicalHash(db, (section+1)*size-1)
+		for _, entry := range rawdb.ReadAddressTxEntries(db, address, section, sectionHead) {
+			if entry.BlockNumber < from || entry.BlockNumber > to {
+				continue
+			}
+			if more, err := collect(entry.BlockNumber, entry.Index); !more || err != nil {
+				return txs, err
+			}
+		}
+		next = (section + 1) * size
+	}
+	// Scan the blocks past the indexed sections
+	for number := next; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tail, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if tail == nil {
+			if err := historyPruned(s.b, rpc.BlockNumber(number)); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("block #%d not found: %v", number, err)
+		}
+		signer := types.MakeSigner(s.b.ChainConfig(), tail.Number())
+		for i, tx := range tail.Transactions() {
+			addrs, err := core.TxAddresses(signer, tx)
+			if err != nil {
+				return nil, err
+			}
+			for _, addr := range addrs {
+				if addr != address {
+					continue
+				}
+				block = tail
+				if more, err := collect(number, uint64(i)); !more || err != nil {
+					return txs, err
+				}
+				break
+			}
+		}
+	}
+	return txs, nil
 }
 
 // GetRawTransactionByHash returns the bytes of the transaction for the given hash.
@@ -988,7 +1264,7 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 	if tx, _, _, _ = rawdb.ReadTransaction(s.b.ChainDb(), hash); tx == nil {
 		if tx = s.b.GetPoolTransaction(hash); tx == nil {
 			// Transaction not found anywhere, abort
-			return nil, nil
+			return nil, transactionPruned(s.b, hash)
 		}
 	}
 	// Serialize to RLP and return
@@ -999,7 +1275,7 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
 	if tx == nil {
-		return nil, nil
+		return nil, transactionPruned(s.b, hash)
 	}
 	receipts, err := s.b.GetReceipts(ctx, blockHash)
 	if err != nil {
@@ -1010,7 +1286,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 	receipt := receipts[index]
 
-	var signer types.Signer = types.NewEIP155Signer(tx.ChainId())
+	var signer types.Signer = types.NewBatchSigner(tx.ChainId())
 	from, _ := types.Sender(signer, tx)
 
 	fields := map[string]interface{}{
@@ -1029,9 +1305,15 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 	// Assign receipt status or post state.
 	fields["status"] = hexutil.Uint(receipt.Status)
+	if len(receipt.RevertData) > 0 {
+		fields["revertData"] = hexutil.Bytes(receipt.RevertData)
+	}
 	if receipt.Logs == nil {
 		fields["logs"] = [][]*types.Log{}
 	}
+	if receipt.Calls != nil {
+		fields["calls"] = receipt.Calls
+	}
 	// If the ContractAddress is 20 0x0 bytes, assume it is not a contract creation
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
@@ -1068,6 +1350,11 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+	// Payer makes the transaction a sponsored one, whose gas it pays.
+	Payer *common.Address `json:"payer"`
+	// Calls make the transaction a batch executing all of them or none, in
+	// place of to, value and input.
+	Calls []types.BatchCall `json:"calls"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1096,6 +1383,15 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.Data != nil && args.Input != nil && !bytes.Equal(*args.Data, *args.Input) {
 		return errors.New(`Both "data" and "input" are set and not equal. Please use "input" to pass transaction call data.`)
 	}
+	if args.Calls != nil {
+		if args.To != nil || args.Value.ToInt().Sign() != 0 || args.Data != nil || args.Input != nil || args.Payer != nil {
+			return errors.New(`batch transactions take their recipients, values and input from "calls" and cannot be sponsored`)
+		}
+		if len(args.Calls) == 0 {
+			return errors.New(`batch transaction without any calls provided`)
+		}
+		return nil
+	}
 	if args.To == nil {
 		// Contract creation
 		var input []byte
@@ -1118,20 +1414,30 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Input != nil {
 		input = *args.Input
 	}
+	var tx *types.Transaction
+	if args.Calls != nil {
+		return types.NewBatchTransaction(uint64(*args.Nonce), args.Calls, uint64(*args.Gas), (*big.Int)(args.GasPrice))
+	}
 	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+		tx = types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	} else {
+		tx = types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	}
+	if args.Payer != nil {
+		tx = types.NewSponsoredTransaction(tx, *args.Payer)
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	return tx
 }
 
-// submitTransaction is a helper function that submits tx to txPool and logs a message.
-func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+// SubmitTransaction is a helper function that submits tx to txPool and logs a message.
+func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
 	if err := b.SendTx(ctx, tx); err != nil {
 		return common.Hash{}, err
 	}
-	if tx.To() == nil {
-		signer := types.NewEIP155Signer(b.ChainConfig().ChainID)
-		from, err := types.Sender(signer, tx)
+	if calls := tx.Calls(); calls != nil {
+		log.Info("Submitted batch transaction", "fullhash", tx.Hash().Hex(), "calls", len(calls))
+	} else if tx.To() == nil {
+		from, err := types.Sender(poolSigner(b), tx)
 		if err != nil {
 			return common.Hash{}, err
 		}
@@ -1177,7 +1483,13 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return submitTransaction(ctx, s.b, signed)
+	// Sponsored transactions need the signature of their payer too
+	if signed.Sponsored() {
+		if signed, err = s.signPayer(signed); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return SubmitTransaction(ctx, s.b, signed)
 }
 
 // SendRawTransaction will add the signed transaction to the transaction pool.
@@ -1187,7 +1499,66 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
 		return common.Hash{}, err
 	}
-	return submitTransaction(ctx, s.b, tx)
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
+// SignPayer adds the signature of the payer, whose key the node holds, to the
+// sponsored transaction signed by its sender. The transaction is returned, not
+// submitted.
+func (s *PublicTransactionPoolAPI) SignPayer(ctx context.Context, encodedTx hexutil.Bytes) (*SignTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	signed, err := s.signPayer(tx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signed}, nil
+}
+
+// SendSponsoredTransaction adds the signature of the payer, whose key the node
+// holds, to the sponsored transaction signed by its sender and submits it to the
+// transaction pool.
+func (s *PublicTransactionPoolAPI) SendSponsoredTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := s.signPayer(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.b, signed)
+}
+
+// signPayer signs the sponsored transaction on behalf of its payer, once the
+// sender's signature is checked.
+func (s *PublicTransactionPoolAPI) signPayer(tx *types.Transaction) (*types.Transaction, error) {
+	payer := tx.Sponsor()
+	if payer == nil {
+		return nil, errors.New("transaction is not sponsored")
+	}
+	signer := types.NewSponsorSigner(s.b.ChainConfig().ChainID)
+	if _, err := types.Sender(signer, tx); err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: *payer}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	hash := signer.PayerHash(tx)
+	sig, err := wallet.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(signer, sig)
 }
 
 // Sign calculates an ECDSA signature for:
@@ -1262,8 +1633,8 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 		}
 	}
 	transactions := make([]*RPCTransaction, 0, len(pending))
+	signer := poolSigner(s.b)
 	for _, tx := range pending {
-		var signer types.Signer = types.NewEIP155Signer(tx.ChainId())
 		from, _ := types.Sender(signer, tx)
 		if _, exists := accounts[from]; exists {
 			transactions = append(transactions, newRPCPendingTransaction(tx))
@@ -1295,9 +1666,9 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 		return common.Hash{}, err
 	}
 
+	signer := poolSigner(s.b)
+	wantSigHash := signer.Hash(matchTx)
 	for _, p := range pending {
-		var signer types.Signer = types.NewEIP155Signer(p.ChainId())
-		wantSigHash := signer.Hash(matchTx)
 
 		if pFrom, err := types.Sender(signer, p); err == nil && pFrom == sendArgs.From && signer.Hash(p) == wantSigHash {
 			// Match. Re-sign and send the transaction.
@@ -1321,6 +1692,131 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	return common.Hash{}, fmt.Errorf("Transaction %#x not found", matchTx.Hash())
 }
 
+// ReplaceTransaction re-signs the pool transaction identified by hash with a higher
+// gas price and submits it in its place. If the transaction was replaced already,
+// its latest replacement is the one bumped. Without a gas price, the lowest one the
+// pool accepts as a replacement is used.
+//
+// Sponsored transactions, whose gas price is signed by their payer too, and
+// contract creations, which the pool doesn't accept, can only be cancelled.
+func (s *PublicTransactionPoolAPI) ReplaceTransaction(ctx context.Context, hash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+	tx, from, err := s.poolTransaction(hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	calls := tx.Calls()
+	switch {
+	case tx.Sponsored():
+		return common.Hash{}, errors.New("sponsored transaction cannot be replaced without its payer signature")
+	case calls == nil && tx.To() == nil:
+		return common.Hash{}, errors.New("contract creation cannot be replaced")
+	}
+	price, err := s.replacementGasPrice(tx, gasPrice)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var replacement *types.Transaction
+	if calls != nil {
+		replacement = types.NewBatchTransaction(tx.Nonce(), calls, tx.Gas(), price)
+	} else {
+		replacement = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), price, tx.Data())
+	}
+	signed, err := s.sign(from, replacement)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.b, signed)
+}
+
+// CancelTransaction replaces the pool transaction identified by hash with an empty
+// transfer from its sender to itself, priced as in ReplaceTransaction.
+func (s *PublicTransactionPoolAPI) CancelTransaction(ctx context.Context, hash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+	tx, from, err := s.poolTransaction(hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	price, err := s.replacementGasPrice(tx, gasPrice)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := s.sign(from, types.NewTransaction(tx.Nonce(), from, new(big.Int), params.TxGas, price, nil))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.b, signed)
+}
+
+// ReplaceRawTransaction submits a signed transaction in place of the pool
+// transaction identified by hash. The replacement must come from the same sender,
+// reuse its nonce and pay a sufficiently bumped gas price.
+func (s *PublicTransactionPoolAPI) ReplaceRawTransaction(ctx context.Context, hash common.Hash, encodedTx hexutil.Bytes) (common.Hash, error) {
+	replacement := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, replacement); err != nil {
+		return common.Hash{}, err
+	}
+	tx, from, err := s.poolTransaction(hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signer := poolSigner(s.b)
+	if sender, err := types.Sender(signer, replacement); err != nil {
+		return common.Hash{}, err
+	} else if sender != from || replacement.Nonce() != tx.Nonce() {
+		return common.Hash{}, fmt.Errorf("replacement must be sent by %s with nonce %d", from.Hex(), tx.Nonce())
+	}
+	if _, err := s.replacementGasPrice(tx, (*hexutil.Big)(replacement.GasPrice())); err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.b, replacement)
+}
+
+// poolTransaction retrieves the pool transaction currently standing for the one
+// identified by hash, along with its sender.
+func (s *PublicTransactionPoolAPI) poolTransaction(hash common.Hash) (*types.Transaction, common.Address, error) {
+	if replacements := s.b.TxPoolReplacements(hash); len(replacements) > 0 {
+		hash = replacements[len(replacements)-1]
+	}
+	tx := s.b.GetPoolTransaction(hash)
+	if tx == nil {
+		return nil, common.Address{}, fmt.Errorf("transaction %#x not found in the pool", hash)
+	}
+	from, err := types.Sender(poolSigner(s.b), tx)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return tx, from, nil
+}
+
+// poolSigner returns the signer of the transactions to be included in the next
+// block, the ones in the pool.
+func poolSigner(b Backend) types.Signer {
+	return types.MakeSigner(b.ChainConfig(), new(big.Int).Add(b.CurrentBlock().Number(), common.Big1))
+}
+
+// replacementGasPrice checks the requested gas price against the minimum bump
+// the pool requires to replace tx, defaulting to that minimum.
+func (s *PublicTransactionPoolAPI) replacementGasPrice(tx *types.Transaction, gasPrice *hexutil.Big) (*big.Int, error) {
+	min := bumpedGasPrice(tx.GasPrice(), s.b.TxPoolPriceBump())
+	if gasPrice == nil {
+		return min, nil
+	}
+	if price := (*big.Int)(gasPrice); price.Cmp(min) < 0 {
+		return nil, fmt.Errorf("gas price %v too low to replace transaction %#x, at least %v required", price, tx.Hash(), min)
+	}
+	return (*big.Int)(gasPrice), nil
+}
+
+// bumpedGasPrice returns the lowest gas price replacing a transaction priced at
+// gasPrice, mirroring the threshold the pool enforces for priceBump.
+func bumpedGasPrice(gasPrice *big.Int, priceBump uint64) *big.Int {
+	bumped := new(big.Int).Mul(gasPrice, big.NewInt(100+int64(priceBump)))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(gasPrice) <= 0 {
+		bumped.Add(gasPrice, common.Big1)
+	}
+	return bumped
+}
+
 // PublicDebugAPI is the collection of Ethereum APIs exposed over the public
 // debugging endpoint.
 type PublicDebugAPI struct {
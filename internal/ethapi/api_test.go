@@ -0,0 +1,465 @@
+package ethapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/accounts"
+	"github.com/PlatONnetwork/PlatON-Go/accounts/keystore"
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/common/hexutil"
+	"github.com/PlatONnetwork/PlatON-Go/core"
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
+	"github.com/PlatONnetwork/PlatON-Go/core/state"
+	"github.com/PlatONnetwork/PlatON-Go/core/types"
+	"github.com/PlatONnetwork/PlatON-Go/core/vm"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/ethdb"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"github.com/PlatONnetwork/PlatON-Go/rlp"
+	"github.com/PlatONnetwork/PlatON-Go/rpc"
+)
+
+// testTxPoolBackend serves the transaction pool API from a set of pooled
+// transactions and records the ones submitted.
+type testTxPoolBackend struct {
+	Backend
+	am       *accounts.Manager
+	pool     map[common.Hash]*types.Transaction
+	replaced map[common.Hash]common.Hash
+	sent     []*types.Transaction
+}
+
+// newTestTxPoolBackend creates a backend whose account manager can sign for the
+// given key.
+func newTestTxPoolBackend(t *testing.T, key *ecdsa.PrivateKey) (*testTxPoolBackend, func()) {
+	dir, err := ioutil.TempDir("", "ethapi-test")
+	if err != nil {
+		t.Fatalf("failed to create key dir: %v", err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatalf("failed to unlock account: %v", err)
+	}
+	b := &testTxPoolBackend{
+		am:       accounts.NewManager(ks),
+		pool:     make(map[common.Hash]*types.Transaction),
+		replaced: make(map[common.Hash]common.Hash),
+	}
+	return b, func() {
+		b.am.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func (b *testTxPoolBackend) AccountManager() *accounts.Manager { return b.am }
+func (b *testTxPoolBackend) ChainConfig() *params.ChainConfig  { return params.TestChainConfig }
+func (b *testTxPoolBackend) TxPoolPriceBump() uint64           { return 10 }
+
+func (b *testTxPoolBackend) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
+}
+
+func (b *testTxPoolBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	return b.pool[hash]
+}
+
+func (b *testTxPoolBackend) GetPoolTransactions() (types.Transactions, error) {
+	var txs types.Transactions
+	for _, tx := range b.pool {
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (b *testTxPoolBackend) TxPoolReplacements(hash common.Hash) []common.Hash {
+	var chain []common.Hash
+	for next, ok := b.replaced[hash]; ok; next, ok = b.replaced[next] {
+		chain = append(chain, next)
+	}
+	return chain
+}
+
+func (b *testTxPoolBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+// Tests that a pool transaction is re-signed with a bumped gas price, following
+// the replacements it already had.
+func TestReplaceTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	b, done := newTestTxPoolBackend(t, key)
+	defer done()
+
+	var (
+		signer      = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		from        = crypto.PubkeyToAddress(key.PublicKey)
+		original, _ = types.SignTx(types.NewTransaction(3, common.Address{1}, big.NewInt(5), 50000, big.NewInt(100), []byte{0x01}), signer, key)
+		current, _  = types.SignTx(types.NewTransaction(3, common.Address{1}, big.NewInt(5), 50000, big.NewInt(200), []byte{0x01}), signer, key)
+		api         = NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	)
+	b.pool[current.Hash()] = current
+	b.replaced[original.Hash()] = current.Hash()
+
+	// The replaced transaction stands for its current replacement
+	if _, err := api.ReplaceTransaction(context.Background(), original.Hash(), (*hexutil.Big)(big.NewInt(219))); err == nil {
+		t.Fatalf("replacement without the price bump accepted")
+	}
+	if len(b.sent) != 0 {
+		t.Fatalf("refused replacement submitted")
+	}
+	hash, err := api.ReplaceTransaction(context.Background(), original.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	if len(b.sent) != 1 || b.sent[0].Hash() != hash {
+		t.Fatalf("submitted transactions mismatch: have %v, want %x", b.sent, hash)
+	}
+	tx := b.sent[0]
+	if sender, _ := types.Sender(signer, tx); sender != from {
+		t.Errorf("sender mismatch: have %x, want %x", sender, from)
+	}
+	if tx.Nonce() != 3 || *tx.To() != (common.Address{1}) || tx.Value().Cmp(big.NewInt(5)) != 0 || tx.Gas() != 50000 || len(tx.Data()) != 1 {
+		t.Errorf("replacement doesn't carry the replaced transaction: %v", tx)
+	}
+	if tx.GasPrice().Cmp(big.NewInt(220)) != 0 {
+		t.Errorf("gas price mismatch: have %v, want 220", tx.GasPrice())
+	}
+	if _, err := api.ReplaceTransaction(context.Background(), common.Hash{1}, nil); err == nil {
+		t.Errorf("replacement of an unknown transaction accepted")
+	}
+}
+
+// Tests that the sponsored and batch transactions of the managed accounts are
+// listed as pending, their senders being derived by the signer of the pool.
+func TestPendingTransactions(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	b, done := newTestTxPoolBackend(t, key)
+	defer done()
+
+	var (
+		signer       = types.NewBatchSigner(params.TestChainConfig.ChainID)
+		sponsored, _ = types.SignTx(types.NewSponsoredTransaction(types.NewTransaction(0, common.Address{1}, big.NewInt(5), 50000, big.NewInt(100), nil), common.Address{2}), signer, key)
+		batch, _     = types.SignTx(types.NewBatchTransaction(1, []types.BatchCall{{To: common.Address{1}, Value: big.NewInt(5)}}, 50000, big.NewInt(100)), signer, key)
+		foreign, _   = types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(5), 50000, big.NewInt(100), nil), signer, other)
+		api          = NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	)
+	for _, tx := range []*types.Transaction{sponsored, batch, foreign} {
+		b.pool[tx.Hash()] = tx
+	}
+	pending, err := api.PendingTransactions()
+	if err != nil {
+		t.Fatalf("failed to list pending transactions: %v", err)
+	}
+	have := make(map[common.Hash]bool)
+	for _, tx := range pending {
+		have[tx.Hash] = true
+	}
+	if len(have) != 2 || !have[sponsored.Hash()] || !have[batch.Hash()] {
+		t.Errorf("pending transactions mismatch: have %v, want %x and %x", have, sponsored.Hash(), batch.Hash())
+	}
+}
+
+// Tests that sponsored transactions and contract creations are not replaced,
+// as the payer signature and the pool would refuse the replacement.
+func TestReplaceUnreplaceableTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	b, done := newTestTxPoolBackend(t, key)
+	defer done()
+
+	var (
+		signer       = types.NewBatchSigner(params.TestChainConfig.ChainID)
+		sponsored, _ = types.SignTx(types.NewSponsoredTransaction(types.NewTransaction(0, common.Address{1}, big.NewInt(5), 50000, big.NewInt(100), nil), common.Address{2}), signer, key)
+		creation, _  = types.SignTx(types.NewContractCreation(1, big.NewInt(0), 50000, big.NewInt(100), []byte{0x01}), signer, key)
+		api          = NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	)
+	for _, test := range []struct {
+		name string
+		tx   *types.Transaction
+		err  string
+	}{
+		{"sponsored", sponsored, "sponsored transaction cannot be replaced without its payer signature"},
+		{"creation", creation, "contract creation cannot be replaced"},
+	} {
+		b.pool[test.tx.Hash()] = test.tx
+		if _, err := api.ReplaceTransaction(context.Background(), test.tx.Hash(), nil); err == nil || err.Error() != test.err {
+			t.Errorf("%s: error mismatch: have %v, want %q", test.name, err, test.err)
+		}
+	}
+	if len(b.sent) != 0 {
+		t.Fatalf("refused replacements submitted: %v", b.sent)
+	}
+}
+
+// Tests that a pool transaction is cancelled by an empty transfer of its sender
+// to itself.
+func TestCancelTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	b, done := newTestTxPoolBackend(t, key)
+	defer done()
+
+	var (
+		signer = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		tx, _  = types.SignTx(types.NewTransaction(7, common.Address{1}, big.NewInt(5), 50000, big.NewInt(100), []byte{0x01}), signer, key)
+		api    = NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	)
+	b.pool[tx.Hash()] = tx
+
+	if _, err := api.CancelTransaction(context.Background(), tx.Hash(), (*hexutil.Big)(big.NewInt(109))); err == nil {
+		t.Fatalf("cancellation without the price bump accepted")
+	}
+	if _, err := api.CancelTransaction(context.Background(), tx.Hash(), nil); err != nil {
+		t.Fatalf("failed to cancel transaction: %v", err)
+	}
+	if len(b.sent) != 1 {
+		t.Fatalf("submitted transaction count mismatch: have %d, want 1", len(b.sent))
+	}
+	cancel := b.sent[0]
+	if sender, _ := types.Sender(signer, cancel); sender != from {
+		t.Errorf("sender mismatch: have %x, want %x", sender, from)
+	}
+	if cancel.Nonce() != 7 || *cancel.To() != from || cancel.Value().Sign() != 0 || cancel.Gas() != params.TxGas || len(cancel.Data()) != 0 {
+		t.Errorf("cancellation is not an empty transfer to self: %v", cancel)
+	}
+	if cancel.GasPrice().Cmp(big.NewInt(110)) != 0 {
+		t.Errorf("gas price mismatch: have %v, want 110", cancel.GasPrice())
+	}
+}
+
+// Tests that a signed replacement is only submitted if it comes from the sender
+// of the replaced transaction, reuses its nonce and bumps its gas price.
+func TestReplaceRawTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	b, done := newTestTxPoolBackend(t, key)
+	defer done()
+
+	var (
+		signer = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		tx, _  = types.SignTx(types.NewTransaction(2, common.Address{1}, big.NewInt(5), 50000, big.NewInt(100), nil), signer, key)
+		api    = NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	)
+	b.pool[tx.Hash()] = tx
+
+	encode := func(nonce uint64, gasPrice int64, key *ecdsa.PrivateKey) (*types.Transaction, hexutil.Bytes) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{2}, big.NewInt(0), 50000, big.NewInt(gasPrice), nil), signer, key)
+		data, _ := rlp.EncodeToBytes(tx)
+		return tx, data
+	}
+	for _, test := range []struct {
+		name     string
+		nonce    uint64
+		gasPrice int64
+		key      *ecdsa.PrivateKey
+	}{
+		{"other sender", 2, 200, other},
+		{"other nonce", 3, 200, key},
+		{"price bump not met", 2, 109, key},
+	} {
+		_, data := encode(test.nonce, test.gasPrice, test.key)
+		if _, err := api.ReplaceRawTransaction(context.Background(), tx.Hash(), data); err == nil {
+			t.Errorf("%s: replacement accepted", test.name)
+		}
+	}
+	if len(b.sent) != 0 {
+		t.Fatalf("refused replacements submitted: %v", b.sent)
+	}
+	replacement, data := encode(2, 110, key)
+	hash, err := api.ReplaceRawTransaction(context.Background(), tx.Hash(), data)
+	if err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	if hash != replacement.Hash() || len(b.sent) != 1 || b.sent[0].Hash() != replacement.Hash() {
+		t.Errorf("submitted transaction mismatch: have %x, want %x", hash, replacement.Hash())
+	}
+}
+
+// testAddressBackend serves a chain of blocks, the first sections of which the
+// address transaction index covers.
+type testAddressBackend struct {
+	Backend
+	db       ethdb.Database
+	blocks   []*types.Block
+	size     uint64
+	sections uint64
+}
+
+func (b *testAddressBackend) ChainDb() ethdb.Database          { return b.db }
+func (b *testAddressBackend) ChainConfig() *params.ChainConfig { return params.TestChainConfig }
+func (b *testAddressBackend) CurrentBlock() *types.Block       { return b.blocks[len(b.blocks)-1] }
+
+func (b *testAddressBackend) AddressIndexStatus() (uint64, uint64) {
+	return b.size, b.sections
+}
+
+func (b *testAddressBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+// Tests that only the few blocks a synced address index leaves behind are
+// scanned directly.
+func TestGetTransactionsByAddressTail(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		tx, _  = types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		b      = &testAddressBackend{db: ethdb.NewMemDatabase(), size: 4}
+	)
+	for i := 0; i <= 20; i++ {
+		var txs []*types.Transaction
+		if i == 18 {
+			txs = append(txs, tx)
+		}
+		b.blocks = append(b.blocks, types.NewBlock(&types.Header{Number: big.NewInt(int64(i))}, txs, nil))
+	}
+	api := NewPublicTransactionPoolAPI(b, new(AddrLocker))
+
+	for _, test := range []struct {
+		sections uint64
+		from     rpc.BlockNumber
+		want     error
+	}{
+		{0, 0, errAddressIndexNotReady},
+		{0, 12, errAddressIndexNotReady},
+		{0, 13, nil},
+		{3, 0, errAddressIndexNotReady},
+		{4, 0, nil},
+	} {
+		b.sections = test.sections
+		txs, err := api.GetTransactionsByAddress(context.Background(), from, test.from, rpc.LatestBlockNumber, 0)
+		if err != test.want {
+			t.Errorf("%d sections from block %d: error mismatch: have %v, want %v", test.sections, test.from, err, test.want)
+			continue
+		}
+		if err == nil && (len(txs) != 1 || txs[0].Hash != tx.Hash()) {
+			t.Errorf("%d sections from block %d: transactions mismatch: have %v, want %x", test.sections, test.from, txs, tx.Hash())
+		}
+	}
+}
+
+// testPrunedBackend serves transactions from a chain database only.
+type testPrunedBackend struct {
+	Backend
+	db ethdb.Database
+}
+
+func (b *testPrunedBackend) ChainDb() ethdb.Database                           { return b.db }
+func (b *testPrunedBackend) GetPoolTransaction(common.Hash) *types.Transaction { return nil }
+
+// Tests that the transactions of blocks whose history was pruned are reported
+// as pruned rather than unknown.
+func TestGetPrunedTransaction(t *testing.T) {
+	var (
+		tx    = types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		block = types.NewBlock(&types.Header{Number: big.NewInt(5)}, []*types.Transaction{tx}, nil)
+		b     = &testPrunedBackend{db: ethdb.NewMemDatabase()}
+		api   = NewPublicTransactionPoolAPI(b, new(AddrLocker))
+	)
+	// The body and receipts are gone, the lookup entry is kept
+	rawdb.WriteTxLookupEntries(b.db, block)
+
+	for _, test := range []struct {
+		oldest uint64
+		hash   common.Hash
+		want   error
+	}{
+		{0, tx.Hash(), nil},
+		{5, tx.Hash(), nil},
+		{6, tx.Hash(), core.ErrHistoryPruned},
+		{6, common.Hash{1}, nil},
+	} {
+		rawdb.WriteOldestRetainedBlock(b.db, test.oldest)
+		if rpcTx, err := api.GetTransactionByHash(context.Background(), test.hash); rpcTx != nil || err != test.want {
+			t.Errorf("oldest %d, tx %x: transaction mismatch: have %v (%v), want %v", test.oldest, test.hash, rpcTx, err, test.want)
+		}
+		if raw, err := api.GetRawTransactionByHash(context.Background(), test.hash); raw != nil || err != test.want {
+			t.Errorf("oldest %d, tx %x: raw transaction mismatch: have %x (%v), want %v", test.oldest, test.hash, raw, err, test.want)
+		}
+		if receipt, err := api.GetTransactionReceipt(context.Background(), test.hash); receipt != nil || err != test.want {
+			t.Errorf("oldest %d, tx %x: receipt mismatch: have %v (%v), want %v", test.oldest, test.hash, receipt, err, test.want)
+		}
+	}
+}
+
+// testCallBackend executes calls against a fixed state.
+type testCallBackend struct {
+	Backend
+	config *params.ChainConfig
+	state  *state.StateDB
+	header *types.Header
+}
+
+func (b *testCallBackend) StateAndHeaderByNumber(context.Context, rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	return b.state, b.header, nil
+}
+
+func (b *testCallBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+	return vm.NewEVM(core.NewEVMContext(msg, header, nil), state, b.config, vmCfg), func() error { return nil }, nil
+}
+
+// Tests that eth_call returns the payload of a reverting contract as the data
+// of a JSON-RPC error with code 3.
+func TestCallRevertError(t *testing.T) {
+	config := *params.TestChainConfig
+	config.VMInterpreter = "evm"
+
+	var (
+		from     = common.Address{1}
+		reverter = common.Address{2} // REVERT with "msg"
+		b        = &testCallBackend{
+			config: &config,
+			header: &types.Header{Number: big.NewInt(1), Time: big.NewInt(0), GasLimit: 10000000, Extra: make([]byte, 97)},
+		}
+	)
+	b.state, _ = state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	b.state.SetBalance(from, big.NewInt(params.LAT))
+	b.state.SetCode(reverter, common.Hex2Bytes("626d73676000526003601dfd"))
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", NewPublicBlockChainAPI(b)); err != nil {
+		t.Fatalf("failed to register api: %v", err)
+	}
+	body := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "eth_call", "params": [{"from": "%s", "to": "%s", "gas": "0x186a0", "gasPrice": "0x1"}, "latest"]}`, from.Hex(), reverter.Hex())
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	var res struct {
+		Result *hexutil.Bytes
+		Error  *struct {
+			Code    int
+			Message string
+			Data    hexutil.Bytes
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response %s: %v", rec.Body, err)
+	}
+	if res.Result != nil || res.Error == nil {
+		t.Fatalf("expected revert error, have %s", rec.Body)
+	}
+	if res.Error.Code != 3 || res.Error.Message != "execution reverted: msg" || string(res.Error.Data) != "msg" {
+		t.Errorf("revert error mismatch: have %d %q %q", res.Error.Code, res.Error.Message, res.Error.Data)
+	}
+}
@@ -26,6 +26,7 @@ var Modules = map[string]string{
 	"miner":      Miner_JS,
 	"net":        Net_JS,
 	"personal":   Personal_JS,
+	"ppos":       Ppos_JS,
 	"rpc":        RPC_JS,
 	"shh":        Shh_JS,
 	"swarmfs":    SWARMFS_JS,
@@ -375,6 +376,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceCall',
+			call: 'debug_traceCall',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -391,6 +398,17 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'wasmStorageRangeAt',
+			call: 'debug_wasmStorageRangeAt',
+			params: 5,
+		}),
+		new web3._extend.Method({
+			name: 'storageDiff',
+			call: 'debug_storageDiff',
+			params: 3,
+			inputFormatter: [null, null, null],
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByNumber',
 			call: 'debug_getModifiedAccountsByNumber',
@@ -446,6 +464,33 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
 		}),
+		new web3._extend.Method({
+			name: 'replaceTransaction',
+			call: 'platon_replaceTransaction',
+			params: 2,
+			inputFormatter: [null, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'cancelTransaction',
+			call: 'platon_cancelTransaction',
+			params: 2,
+			inputFormatter: [null, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'replaceRawTransaction',
+			call: 'platon_replaceRawTransaction',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'signPayer',
+			call: 'platon_signPayer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'sendSponsoredTransaction',
+			call: 'platon_sendSponsoredTransaction',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'signTransaction',
 			call: 'platon_signTransaction',
@@ -463,6 +508,12 @@ web3._extend({
 			call: 'platon_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByAddress',
+			call: 'platon_getTransactionsByAddress',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.toHex]
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -625,10 +676,97 @@ web3._extend({
 });
 `
 
+const Ppos_JS = `
+web3._extend({
+	property: 'ppos',
+	methods: [
+		new web3._extend.Method({
+			name: 'getCandidate',
+			call: 'ppos_getCandidate',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateList',
+			call: 'ppos_getCandidateList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getVerifierList',
+			call: 'ppos_getVerifierList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorList',
+			call: 'ppos_getValidatorList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDelegations',
+			call: 'ppos_getDelegations',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProposal',
+			call: 'ppos_getProposal',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listProposal',
+			call: 'ppos_listProposal',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTallyResult',
+			call: 'ppos_getTallyResult',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getActiveVersion',
+			call: 'ppos_getActiveVersion',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getGovernParam',
+			call: 'ppos_getGovernParam',
+			params: 3,
+			inputFormatter: [null, null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listGovernParam',
+			call: 'ppos_listGovernParam',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRestrictingInfo',
+			call: 'ppos_getRestrictingInfo',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+	],
+	properties: []
+});
+`
+
 const TxPool_JS = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'txStatus',
+			call: 'txpool_status',
+			params: 1
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
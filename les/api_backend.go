@@ -135,6 +135,26 @@ func (b *LesApiBackend) Stats() (pending int, queued int) {
 	return b.eth.txPool.Stats(), 0
 }
 
+// TxPoolStatus reports the transactions known to the light pool as pending,
+// since it keeps no future queue.
+func (b *LesApiBackend) TxPoolStatus(txHashes []common.Hash) []core.TxStatus {
+	status := make([]core.TxStatus, len(txHashes))
+	for i, hash := range txHashes {
+		if b.eth.txPool.GetTransaction(hash) != nil {
+			status[i] = core.TxStatusPending
+		}
+	}
+	return status
+}
+
+func (b *LesApiBackend) TxPoolReplacements(txHash common.Hash) []common.Hash {
+	return nil
+}
+
+func (b *LesApiBackend) TxPoolPriceBump() uint64 {
+	return core.DefaultTxPoolConfig.PriceBump
+}
+
 func (b *LesApiBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
 	return b.eth.txPool.Content()
 }
@@ -195,6 +215,12 @@ func (b *LesApiBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocksClient, sections
 }
 
+// AddressIndexStatus reports the address transaction index as disabled, light
+// clients do not have the block bodies to build it from.
+func (b *LesApiBackend) AddressIndexStatus() (uint64, uint64) {
+	return 0, 0
+}
+
 func (b *LesApiBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	for i := 0; i < bloomFilterThreads; i++ {
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
@@ -78,7 +78,7 @@ type LightEthereum struct {
 }
 
 func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
-	chainDb, err := eth.CreateDB(ctx, config, "lightchaindata")
+	chainDb, err := eth.CreateDB(ctx, config, "lightchaindata", "")
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,8 @@ type BasicBlock struct {
 
 	JmpCond    TyValueID
 	YieldValue TyValueID
+
+	JmpOffset int // Offset in the function body of the wasm instruction ending the block
 }
 
 type TyJmpKind uint8
@@ -23,6 +25,22 @@ const (
 	JmpReturn
 )
 
+// jmpOp returns the name of the instruction terminating the block.
+func (bb *BasicBlock) jmpOp() string {
+	switch bb.JmpKind {
+	case JmpUncond:
+		return "jmp"
+	case JmpEither:
+		return "jmp_either"
+	case JmpTable:
+		return "jmp_table"
+	case JmpReturn:
+		return "return"
+	default:
+		panic("got JmpUndef")
+	}
+}
+
 func (g *CFGraph) ToInsSeq() []Instr {
 	out := make([]Instr, 0)
 	blockRelocs := make([]int, len(g.Blocks))
@@ -33,7 +51,7 @@ func (g *CFGraph) ToInsSeq() []Instr {
 		for _, op := range bb.Code {
 			out = append(out, op)
 		}
-		out = append(out, Instr{}) // jmp placeholder
+		out = append(out, Instr{Offset: bb.JmpOffset}) // jmp placeholder
 		blockEnds[i] = len(out)
 	}
 
@@ -43,25 +61,16 @@ func (g *CFGraph) ToInsSeq() []Instr {
 		for j, target := range bb.JmpTargets {
 			jmpIns.Immediates[j] = int64(blockRelocs[target])
 		}
+		jmpIns.Op = bb.jmpOp()
 		switch bb.JmpKind {
-		case JmpUndef:
-			panic("got JmpUndef")
 		case JmpUncond:
-			jmpIns.Op = "jmp"
 			jmpIns.Values = []TyValueID{bb.YieldValue}
-		case JmpEither:
-			jmpIns.Op = "jmp_either"
-			jmpIns.Values = []TyValueID{bb.JmpCond, bb.YieldValue}
-		case JmpTable:
-			jmpIns.Op = "jmp_table"
+		case JmpEither, JmpTable:
 			jmpIns.Values = []TyValueID{bb.JmpCond, bb.YieldValue}
 		case JmpReturn:
-			jmpIns.Op = "return"
 			if bb.YieldValue != 0 {
 				jmpIns.Values = []TyValueID{bb.YieldValue}
 			}
-		default:
-			panic("unreachable")
 		}
 	}
 
@@ -104,10 +113,15 @@ func (c *SSAFunctionCompiler) NewCFGraph() *CFGraph {
 			if currentBlock != nil {
 				currentBlock.JmpKind = JmpUncond
 				currentBlock.JmpTargets = []int{label}
+				currentBlock.JmpOffset = ins.Offset
 			}
 			currentBlock = &g.Blocks[label]
 		}
 		switch ins.Op {
+		case "jmp", "jmp_if", "jmp_either", "jmp_table", "return":
+			currentBlock.JmpOffset = ins.Offset
+		}
+		switch ins.Op {
 		case "jmp":
 			currentBlock.JmpKind = JmpUncond
 			currentBlock.JmpTargets = []int{insLabels[int(ins.Immediates[0])]}
@@ -151,6 +165,9 @@ func (c *SSAFunctionCompiler) NewCFGraph() *CFGraph {
 			panic("last block should always have an undefined jump target")
 		}
 		lastBlock.JmpKind = JmpReturn
+		if len(c.Code) != 0 {
+			lastBlock.JmpOffset = c.Code[len(c.Code)-1].Offset
+		}
 	}
 
 	return g
@@ -8,6 +8,10 @@ const (
 	// ...
 )
 
+// InsertGasCounters prepends an add_gas instruction to every basic block
+// charging the cost of all instructions in the block, including the jump
+// or return that terminates it. Charging per block keeps the gas used by
+// a function independent of whether it is interpreted or JIT compiled.
 func (c *SSAFunctionCompiler) InsertGasCounters(gp GasPolicy) {
 	cfg := c.NewCFGraph()
 
@@ -20,11 +24,18 @@ func (c *SSAFunctionCompiler) InsertGasCounters(gp GasPolicy) {
 				panic("total cost overflow")
 			}
 		}
+		totalCost += gp.GetCost(blk.jmpOp())
+		if totalCost < 0 {
+			panic("total cost overflow")
+		}
 
 		if totalCost != 0 {
-			blk.Code = append([]Instr{
-				buildInstr(0, "add_gas", []int64{totalCost}, []TyValueID{}),
-			}, blk.Code...)
+			addGas := buildInstr(0, "add_gas", []int64{totalCost}, []TyValueID{})
+			addGas.Offset = blk.JmpOffset
+			if len(blk.Code) != 0 {
+				addGas.Offset = blk.Code[0].Offset
+			}
+			blk.Code = append([]Instr{addGas}, blk.Code...)
 		}
 	}
 	c.Code = cfg.ToInsSeq()
@@ -11,3 +11,7 @@ type SimpleGasPolicy struct {
 func (p *SimpleGasPolicy) GetCost(key string) int64 {
 	return p.GasPerInstruction
 }
+
+// DefaultGasPolicy charges one unit of gas per instruction, matching the
+// static costs of the interpreter gas table.
+var DefaultGasPolicy GasPolicy = &SimpleGasPolicy{GasPerInstruction: 1}
@@ -27,6 +27,21 @@ type InterpreterCode struct {
 	Bytes      []byte
 	JITInfo    interface{}
 	JITDone    bool
+
+	// GasMetered is set when gas counters were inserted into the code at
+	// compile time, in which case instructions are not charged one by one.
+	GasMetered bool
+}
+
+// GasMetered reports whether the given module code was compiled with gas
+// counters.
+func GasMetered(code []InterpreterCode) bool {
+	for _, c := range code {
+		if c.GasMetered {
+			return true
+		}
+	}
+	return false
 }
 
 func LoadModule(raw []byte) (*Module, error) {
@@ -105,7 +120,12 @@ func LoadModule(raw []byte) (*Module, error) {
 	}, nil
 }
 
-func (m *Module) CompileForInterpreter(gp GasPolicy) (_retCode []InterpreterCode, retErr error) {
+func (m *Module) CompileForInterpreter(gp GasPolicy) ([]InterpreterCode, error) {
+	code, _, err := m.compile(gp, false)
+	return code, err
+}
+
+func (m *Module) compile(gp GasPolicy, withSourceMaps bool) (_retCode []InterpreterCode, _retMaps []SourceMap, retErr error) {
 	defer utils.CatchPanic(&retErr)
 
 	ret := make([]InterpreterCode, 0)
@@ -150,6 +170,11 @@ func (m *Module) CompileForInterpreter(gp GasPolicy) (_retCode []InterpreterCode
 	numFuncImports := len(ret)
 	ret = append(ret, make([]InterpreterCode, len(m.Base.FunctionIndexSpace))...)
 
+	var sourceMaps []SourceMap
+	if withSourceMaps {
+		sourceMaps = make([]SourceMap, len(ret))
+	}
+
 	for i, f := range m.Base.FunctionIndexSpace {
 		//fmt.Printf("Compiling function %d (%+v) with %d locals\n", i, f.Sig, len(f.Body.Locals))
 		d, err := disasm.Disassemble(f, m.Base)
@@ -158,6 +183,14 @@ func (m *Module) CompileForInterpreter(gp GasPolicy) (_retCode []InterpreterCode
 		}
 		compiler := NewSSAFunctionCompiler(m.Base, d)
 		compiler.CallIndexOffset = numFuncImports
+		if withSourceMaps {
+			if compiler.SourceOffsets, err = instructionOffsets(f.Body.Code); err != nil {
+				panic(err)
+			}
+			if len(compiler.SourceOffsets) != len(d.Code) {
+				panic("instruction offsets don't match the disassembly")
+			}
+		}
 		compiler.Compile(importTypeIDs)
 		if gp != nil {
 			compiler.InsertGasCounters(gp)
@@ -170,14 +203,19 @@ func (m *Module) CompileForInterpreter(gp GasPolicy) (_retCode []InterpreterCode
 		for _, v := range f.Body.Locals {
 			numLocals += int(v.Count)
 		}
+		code, insRelocs := compiler.serialize()
 		ret[numFuncImports+i] = InterpreterCode{
 			NumRegs:    numRegs,
 			NumParams:  len(f.Sig.ParamTypes),
 			NumLocals:  numLocals,
 			NumReturns: len(f.Sig.ReturnTypes),
-			Bytes:      compiler.Serialize(),
+			Bytes:      code,
+			GasMetered: gp != nil,
+		}
+		if withSourceMaps {
+			sourceMaps[numFuncImports+i] = newSourceMap(compiler.Code, insRelocs)
 		}
 	}
 
-	return ret, nil
+	return ret, sourceMaps, nil
 }
@@ -16,6 +16,13 @@ import (
 // Types are erased in the generated code.
 // Example: float32/float64 are represented as uint32/uint64 respectively.
 func (c *SSAFunctionCompiler) Serialize() []byte {
+	code, _ := c.serialize()
+	return code
+}
+
+// serialize encodes the compiled instructions, also returning the position
+// of each of them in the encoded code.
+func (c *SSAFunctionCompiler) serialize() ([]byte, []int) {
 	buf := &bytes.Buffer{}
 	insRelocs := make([]int, len(c.Code))
 	reloc32Targets := make([]int, 0)
@@ -745,5 +752,5 @@ func (c *SSAFunctionCompiler) Serialize() []byte {
 		binary.LittleEndian.PutUint32(ret[t:t+4], uint32(insRelocs[insPos]))
 	}
 
-	return ret
+	return ret, insRelocs
 }
@@ -0,0 +1,110 @@
+package compiler
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/go-interpreter/wagon/wasm/leb128"
+	ops "github.com/go-interpreter/wagon/wasm/operators"
+)
+
+// SourceMapEntry records that the compiled code of a function from IP up to
+// the IP of the next entry was compiled from the wasm instruction at Offset
+// in the function body.
+type SourceMapEntry struct {
+	IP     int
+	Offset int
+}
+
+// SourceMap maps the compiled code of a function back to its body, with
+// entries sorted by IP.
+type SourceMap []SourceMapEntry
+
+// Offset returns the offset in the function body of the wasm instruction
+// the instruction at ip was compiled from, or -1 if unknown.
+func (sm SourceMap) Offset(ip int) int {
+	i := sort.Search(len(sm), func(i int) bool { return sm[i].IP > ip })
+	if i == 0 {
+		return -1
+	}
+	return sm[i-1].Offset
+}
+
+// IP returns the position of the first instruction compiled from the wasm
+// instruction at offset in the function body, or -1 if none was.
+func (sm SourceMap) IP(offset int) int {
+	for _, e := range sm {
+		if e.Offset == offset {
+			return e.IP
+		}
+	}
+	return -1
+}
+
+// CompileWithSourceMaps compiles the module like CompileForInterpreter and
+// also returns the source map of every function, nil for imports.
+func (m *Module) CompileWithSourceMaps(gp GasPolicy) ([]InterpreterCode, []SourceMap, error) {
+	return m.compile(gp, true)
+}
+
+// newSourceMap collects the IPs at which the source offset of the serialized
+// instructions changes.
+func newSourceMap(code []Instr, insRelocs []int) SourceMap {
+	sm := make(SourceMap, 0)
+	for i, ins := range code {
+		if len(sm) == 0 || sm[len(sm)-1].Offset != ins.Offset {
+			sm = append(sm, SourceMapEntry{IP: insRelocs[i], Offset: ins.Offset})
+		}
+	}
+	return sm
+}
+
+// instructionOffsets returns the offsets of the instructions of a function
+// body in the order they are disassembled.
+func instructionOffsets(body []byte) ([]int, error) {
+	var (
+		r       = bytes.NewReader(body)
+		offsets = make([]int, 0)
+	)
+	for r.Len() > 0 {
+		offsets = append(offsets, len(body)-r.Len())
+		op, _ := r.ReadByte()
+
+		var err error
+		switch op {
+		case ops.Block, ops.Loop, ops.If:
+			_, err = leb128.ReadVarint32(r)
+		case ops.Br, ops.BrIf, ops.Call, ops.GetLocal, ops.SetLocal, ops.TeeLocal, ops.GetGlobal, ops.SetGlobal,
+			ops.CurrentMemory, ops.GrowMemory:
+			_, err = leb128.ReadVarUint32(r)
+		case ops.BrTable:
+			var targets uint32
+			if targets, err = leb128.ReadVarUint32(r); err == nil {
+				// The targets are followed by the default target
+				for i := uint32(0); i <= targets && err == nil; i++ {
+					_, err = leb128.ReadVarUint32(r)
+				}
+			}
+		case ops.CallIndirect, ops.I32Load, ops.I64Load, ops.F32Load, ops.F64Load, ops.I32Load8s, ops.I32Load8u,
+			ops.I32Load16s, ops.I32Load16u, ops.I64Load8s, ops.I64Load8u, ops.I64Load16s, ops.I64Load16u, ops.I64Load32s,
+			ops.I64Load32u, ops.I32Store, ops.I64Store, ops.F32Store, ops.F64Store, ops.I32Store8, ops.I32Store16,
+			ops.I64Store8, ops.I64Store16, ops.I64Store32:
+			if _, err = leb128.ReadVarUint32(r); err == nil {
+				_, err = leb128.ReadVarUint32(r)
+			}
+		case ops.I32Const:
+			_, err = leb128.ReadVarint32(r)
+		case ops.I64Const:
+			_, err = leb128.ReadVarint64(r)
+		case ops.F32Const:
+			_, err = io.ReadFull(r, make([]byte, 4))
+		case ops.F64Const:
+			_, err = io.ReadFull(r, make([]byte, 8))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return offsets, nil
+}
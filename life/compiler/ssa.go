@@ -23,6 +23,11 @@ type SSAFunctionCompiler struct {
 
 	CallIndexOffset int
 
+	// SourceOffsets are the offsets of the disassembled instructions in the
+	// function body. If set, compiled instructions record the offset of the
+	// instruction they were compiled from.
+	SourceOffsets []int
+
 	StackValueSets map[int][]TyValueID
 	UsedValueIDs   map[TyValueID]struct{}
 
@@ -52,6 +57,8 @@ type Instr struct {
 	Op         string
 	Immediates []int64
 	Values     []TyValueID
+
+	Offset int // Offset in the function body of the wasm instruction compiled into this one
 }
 
 // NewSSAFunctionCompiler instantiates a compiler which translates a WebAssembly modules
@@ -136,7 +143,22 @@ func (c *SSAFunctionCompiler) Compile(importTypeIDs []int) {
 
 	unreachableDepth := 0
 
-	for _, ins := range c.Source.Code {
+	// Instructions are attributed to the wasm instruction being compiled
+	// once the next one is reached.
+	stamped, source := 0, -1
+	stampOffsets := func() {
+		if c.SourceOffsets == nil || source < 0 {
+			return
+		}
+		for ; stamped < len(c.Code); stamped++ {
+			c.Code[stamped].Offset = c.SourceOffsets[source]
+		}
+	}
+	defer stampOffsets()
+
+	for pos, ins := range c.Source.Code {
+		stampOffsets()
+		source = pos
 		//fmt.Printf("%s %d\n", ins.Op.Name, len(c.Stack))
 		wasUnreachable := false
 
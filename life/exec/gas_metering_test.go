@@ -0,0 +1,188 @@
+package exec_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/life/resolver"
+)
+
+// gasMeteringResults are the return values of the modules in life/tests,
+// compiled from the .wat sources next to them. Only the binaries listed in
+// life/tests/.gitignore are checked in, the others are run when built
+// locally. Modules missing here are only checked for running the same way
+// with and without gas counters.
+var gasMeteringResults = map[string]int64{
+	"call":           52,
+	"fib":            9227465,
+	"fib_indirect":   9227465,
+	"host_gas":       245,
+	"i64_extend_i32": 4294967305,
+	"if_else":        99,
+	"sum":            199999990000000,
+}
+
+// gasMeteringSkipped are the modules of life/tests that import functions the
+// contract resolver does not provide.
+var gasMeteringSkipped = map[string]bool{
+	"imports": true,
+}
+
+type gasMeteringRun struct {
+	ret     int64
+	gasUsed uint64
+	err     error
+}
+
+func loadGasMeteringCorpus(t *testing.T) map[string][]byte {
+	files, err := filepath.Glob("../tests/*.wasm")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed to list test modules: %v", err)
+	}
+	corpus := make(map[string][]byte)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".wasm")
+		if gasMeteringSkipped[name] {
+			continue
+		}
+		code, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		corpus[name] = code
+	}
+	return corpus
+}
+
+// runWithGas runs the first function of the module defined in the module
+// itself with the contract resolver.
+func runWithGas(t *testing.T, m *compiler.Module, functionCode []compiler.InterpreterCode, gasLimit uint64) gasMeteringRun {
+	return runWithConfig(t, exec.VMConfig{}, m, functionCode, gasLimit)
+}
+
+// runWithConfig is runWithGas with the given vm config.
+func runWithConfig(t *testing.T, config exec.VMConfig, m *compiler.Module, functionCode []compiler.InterpreterCode, gasLimit uint64) gasMeteringRun {
+	context := &exec.VMContext{Config: config, GasLimit: gasLimit}
+	vm, err := exec.NewVirtualMachineWithModule(m, functionCode, context, resolver.NewResolver(0x01), nil)
+	if err != nil {
+		t.Fatalf("failed to create vm: %v", err)
+	}
+	// Only memory taken from the pool can be given back to it.
+	if len(vm.Memory.Memory) > 0 {
+		defer vm.Stop()
+	}
+
+	entry := len(functionCode) - len(m.Base.FunctionIndexSpace)
+	ret, err := vm.Run(entry)
+	return gasMeteringRun{ret, context.GasUsed, err}
+}
+
+// TestGasMeteringConformance checks that charging gas per basic block uses
+// exactly as much gas as charging every executed instruction on its own,
+// including the gas of the host functions called.
+func TestGasMeteringConformance(t *testing.T) {
+	for name, code := range loadGasMeteringCorpus(t) {
+		m, metered, err := exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+		if err != nil {
+			t.Fatalf("%s: failed to compile: %v", name, err)
+		}
+		have := runWithGas(t, m, metered, 1<<50)
+
+		// The same control flow graph without counters, charged per instruction.
+		m, plain, err := exec.ParseModuleAndFunc(code, &compiler.SimpleGasPolicy{GasPerInstruction: 0})
+		if err != nil {
+			t.Fatalf("%s: failed to compile: %v", name, err)
+		}
+		for i := range plain {
+			plain[i].GasMetered = false
+		}
+		want := runWithGas(t, m, plain, 1<<50)
+
+		if (have.err == nil) != (want.err == nil) {
+			t.Fatalf("%s: error mismatch: metered %v, unmetered %v", name, have.err, want.err)
+		}
+		if have.ret != want.ret {
+			t.Errorf("%s: result mismatch: metered %d, unmetered %d", name, have.ret, want.ret)
+		}
+		if result, ok := gasMeteringResults[name]; ok && (have.err != nil || have.ret != result) {
+			t.Errorf("%s: result mismatch: have %d (%v), want %d", name, have.ret, have.err, result)
+		}
+		// A trap fails the call and consumes all its gas, whatever was charged
+		// for the basic block it happened in.
+		if have.err != nil {
+			continue
+		}
+		if have.gasUsed != want.gasUsed {
+			t.Errorf("%s: gas mismatch: metered %d, unmetered %d", name, have.gasUsed, want.gasUsed)
+		}
+		// Running out of gas must fail regardless of where the limit falls.
+		m, metered, _ = exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+		if run := runWithGas(t, m, metered, have.gasUsed-1); run.err == nil {
+			t.Errorf("%s: expected out of gas with limit %d", name, have.gasUsed-1)
+		} else if run.gasUsed > have.gasUsed-1 {
+			t.Errorf("%s: gas used %d exceeds limit %d", name, run.gasUsed, have.gasUsed-1)
+		}
+	}
+}
+
+// TestGasMeteringJIT checks that code compiled with gas counters is left to
+// the interpreter when the JIT is enabled, charging the same gas.
+func TestGasMeteringJIT(t *testing.T) {
+	for name, code := range loadGasMeteringCorpus(t) {
+		m, metered, err := exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+		if err != nil {
+			t.Fatalf("%s: failed to compile: %v", name, err)
+		}
+		want := runWithGas(t, m, metered, 1<<50)
+
+		m, metered, _ = exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+		have := runWithConfig(t, exec.VMConfig{EnableJIT: true}, m, metered, 1<<50)
+		if have.ret != want.ret || have.gasUsed != want.gasUsed || (have.err == nil) != (want.err == nil) {
+			t.Errorf("%s: run mismatch: jit %+v, interpreter %+v", name, have, want)
+		}
+	}
+}
+
+// TestGasMeteringHostCost checks that host functions are charged their own
+// cost on top of the instructions of metered code.
+func TestGasMeteringHostCost(t *testing.T) {
+	code := loadGasMeteringCorpus(t)["host_gas"]
+
+	m, metered, err := exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+	run := runWithGas(t, m, metered, 1<<50)
+	if run.err != nil {
+		t.Fatalf("run failed: %v", run.err)
+	}
+	// sha256 of 64 bytes, 60 + 2 words * 12
+	if run.gasUsed < 84 {
+		t.Errorf("host function not charged: gas used %d", run.gasUsed)
+	}
+}
+
+func TestGasMeteringDeterministic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping repeated runs of the corpus in short mode")
+	}
+	for name, code := range loadGasMeteringCorpus(t) {
+		var first uint64
+		for i := 0; i < 3; i++ {
+			m, functionCode, err := exec.ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+			if err != nil {
+				t.Fatalf("%s: failed to compile: %v", name, err)
+			}
+			run := runWithGas(t, m, functionCode, 1<<50)
+			if i == 0 {
+				first = run.gasUsed
+			} else if run.gasUsed != first {
+				t.Errorf("%s: run %d used %d gas, first run used %d", name, i, run.gasUsed, first)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package exec
 
 import (
 	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler/opcodes"
 	"math/big"
 )
 
@@ -25,4 +26,24 @@ type StateDB interface {
 	Transfer(addr common.Address, value *big.Int) (ret []byte, leftOverGas uint64, err error)
 	DelegateCall(addr, params []byte) ([]byte, error)
 	Call(addr, params []byte) ([]byte, error)
+	Create(code []byte, value *big.Int, gas uint64) (addr common.Address, leftOverGas uint64, err error)
+	Clone(addr common.Address, value *big.Int, gas uint64) (newAddr common.Address, leftOverGas uint64, err error)
+	Migrate(code, args []byte, value *big.Int, gas uint64) (newAddr common.Address, leftOverGas uint64, err error)
+}
+
+// Tracer receives the instructions executed by a VirtualMachine and the host
+// functions they invoke. It is set through VMContext.Tracer and called
+// synchronously; the frame handed over is the live one and must be copied if
+// it is retained.
+type Tracer interface {
+	// CaptureStep is called before the instruction at ip of the frame is
+	// executed, with the gas left once the instruction has been charged.
+	CaptureStep(vm *VirtualMachine, frame *Frame, ip int, op opcodes.Opcode, gas uint64)
+
+	// CaptureHostCall is called before the host function imported as
+	// module.field runs with the given arguments.
+	CaptureHostCall(vm *VirtualMachine, module, field string, args []int64)
+
+	// CaptureHostReturn is called after the host function returned ret.
+	CaptureHostReturn(vm *VirtualMachine, module, field string, ret int64)
 }
@@ -0,0 +1,121 @@
+package exec
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler/opcodes"
+)
+
+type traceStep struct {
+	function int
+	op       opcodes.Opcode
+	gas      uint64
+}
+
+type hostCall struct {
+	field string
+	args  []int64
+	ret   int64
+}
+
+type recordingTracer struct {
+	steps []traceStep
+	calls []hostCall
+}
+
+func (t *recordingTracer) CaptureStep(vm *VirtualMachine, frame *Frame, ip int, op opcodes.Opcode, gas uint64) {
+	t.steps = append(t.steps, traceStep{frame.FunctionID, op, gas})
+}
+
+func (t *recordingTracer) CaptureHostCall(vm *VirtualMachine, module, field string, args []int64) {
+	t.calls = append(t.calls, hostCall{field: module + "." + field, args: append([]int64{}, args...)})
+}
+
+func (t *recordingTracer) CaptureHostReturn(vm *VirtualMachine, module, field string, ret int64) {
+	t.calls[len(t.calls)-1].ret = ret
+}
+
+type doubleResolver struct{}
+
+func (r *doubleResolver) ResolveFunc(module, field string) *FunctionImport {
+	return &FunctionImport{
+		Execute: func(vm *VirtualMachine) int64 {
+			return vm.GetCurrentFrame().Locals[0] * 2
+		},
+		GasCost: func(vm *VirtualMachine) (uint64, error) {
+			return 1, nil
+		},
+	}
+}
+
+func (r *doubleResolver) ResolveGlobal(module, field string) int64 {
+	panic("global import not allowed")
+}
+
+func TestTracerSteps(t *testing.T) {
+	// call.wat, calling the second function from the first
+	wasm, err := ioutil.ReadFile("../tests/call.wasm")
+	if err != nil {
+		t.Fatalf("failed to read module: %v", err)
+	}
+	m, code, err := ParseModuleAndFunc(wasm, compiler.DefaultGasPolicy)
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+	tracer := new(recordingTracer)
+	context := &VMContext{GasLimit: 1 << 20, Tracer: tracer}
+	vm, err := NewVirtualMachineWithModule(m, code, context, &NopResolver{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create vm: %v", err)
+	}
+	if ret, err := vm.Run(0); err != nil || ret != 52 {
+		t.Fatalf("run failed: have %d (%v), want 52", ret, err)
+	}
+	functions := make(map[int]bool)
+	for i, step := range tracer.steps {
+		functions[step.function] = true
+		if i > 0 && step.gas > tracer.steps[i-1].gas {
+			t.Errorf("step %d: gas left increased from %d to %d", i, tracer.steps[i-1].gas, step.gas)
+		}
+	}
+	if !functions[0] || !functions[1] {
+		t.Errorf("steps of both functions expected, have %v", functions)
+	}
+	if last := tracer.steps[len(tracer.steps)-1]; last.gas != context.GasLimit-context.GasUsed {
+		t.Errorf("gas left mismatch: have %d, want %d", last.gas, context.GasLimit-context.GasUsed)
+	}
+}
+
+func TestTracerHostCall(t *testing.T) {
+	// (import "env" "double" (func (param i32) (result i32)))
+	// (func (result i32) (call 0 (i32.const 21)))
+	code := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x0a, 0x02, 0x60, 0x00, 0x01, 0x7f, 0x60, 0x01, 0x7f, 0x01, 0x7f,
+		0x02, 0x0e, 0x01, 0x03, 'e', 'n', 'v', 0x06, 'd', 'o', 'u', 'b', 'l', 'e', 0x00, 0x01,
+		0x03, 0x02, 0x01, 0x00,
+		0x0a, 0x08, 0x01, 0x06, 0x00, 0x41, 0x15, 0x10, 0x00, 0x0b,
+	}
+	m, functionCode, err := ParseModuleAndFunc(code, compiler.DefaultGasPolicy)
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+	tracer := new(recordingTracer)
+	context := &VMContext{GasLimit: 1 << 20, Tracer: tracer}
+	vm, err := NewVirtualMachineWithModule(m, functionCode, context, &doubleResolver{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create vm: %v", err)
+	}
+	if ret, err := vm.Run(1); err != nil || ret != 42 {
+		t.Fatalf("run failed: have %d (%v), want 42", ret, err)
+	}
+	if len(tracer.calls) != 1 {
+		t.Fatalf("host call count mismatch: have %d, want 1", len(tracer.calls))
+	}
+	call := tracer.calls[0]
+	if call.field != "env.double" || len(call.args) != 1 || call.args[0] != 21 || call.ret != 42 {
+		t.Errorf("host call mismatch: %+v", call)
+	}
+}
@@ -0,0 +1,140 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+	"github.com/go-interpreter/wagon/disasm"
+	"github.com/go-interpreter/wagon/wasm"
+)
+
+// Errors reported by ValidateModule, wrapped in a ValidationError.
+var (
+	ErrCodeTooLarge        = errors.New("code size exceeds the limit")
+	ErrFloatInstruction    = errors.New("floating-point instructions are not deterministic, use the softfloat builtins instead")
+	ErrUnknownImport       = errors.New("import is not provided by the resolver")
+	ErrMemoryLimitExceeded = errors.New("memory declaration exceeds the limit")
+	ErrTableLimitExceeded  = errors.New("table declaration exceeds the limit")
+)
+
+// ValidationError is returned for a module rejected by ValidateModule.
+type ValidationError struct {
+	Err    error  // One of the validation errors above
+	Detail string // Where the module breaks the rule
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid wasm module: %s: %v", e.Detail, e.Err)
+}
+
+// ImportChecker is implemented by import resolvers that can tell whether they
+// provide a function, so that modules importing unknown functions can be
+// rejected before they run.
+type ImportChecker interface {
+	HasFunc(module, field string) bool
+}
+
+// ValidationConfig holds the limits ValidateModule checks modules against, 0
+// for no limit. They only apply to modules being deployed, unlike the limits
+// of VMConfig enforced on every run.
+type ValidationConfig struct {
+	MaxCodeSize    int // Size of the code in bytes
+	MaxMemoryPages int // Initial and maximum pages of the declared memory
+	MaxTableSize   int // Initial and maximum entries of the declared table
+}
+
+// ValidateModule checks that a module only uses what the virtual machine can
+// run deterministically, within the limits of the given config. It is meant to
+// be run once, when the code is deployed:
+//   - the code must not be larger than MaxCodeSize;
+//   - functions must not compute with floating-point instructions, whose
+//     results differ across platforms, float arithmetic is to be imported
+//     from the softfloat builtins of the resolver;
+//   - imported functions must be provided by the resolver, if it implements
+//     ImportChecker;
+//   - memory and table declarations must fit MaxMemoryPages and MaxTableSize.
+func ValidateModule(code []byte, m *compiler.Module, config ValidationConfig, resolver ImportResolver) error {
+	if config.MaxCodeSize != 0 && len(code) > config.MaxCodeSize {
+		return &ValidationError{ErrCodeTooLarge, fmt.Sprintf("%d bytes, limit %d", len(code), config.MaxCodeSize)}
+	}
+
+	numImports := 0
+	if m.Base.Import != nil {
+		checker, _ := resolver.(ImportChecker)
+		for _, imp := range m.Base.Import.Entries {
+			if imp.Type.Kind() != wasm.ExternalFunction {
+				continue
+			}
+			numImports++
+			if checker != nil && !checker.HasFunc(imp.ModuleName, imp.FieldName) {
+				return &ValidationError{ErrUnknownImport, fmt.Sprintf("function %s.%s", imp.ModuleName, imp.FieldName)}
+			}
+		}
+	}
+
+	if m.Base.Memory != nil && len(m.Base.Memory.Entries) > 0 {
+		if err := validateLimits(&m.Base.Memory.Entries[0].Limits, config.MaxMemoryPages, "memory"); err != nil {
+			return &ValidationError{ErrMemoryLimitExceeded, err.Error()}
+		}
+	}
+	if m.Base.Table != nil && len(m.Base.Table.Entries) > 0 {
+		if err := validateLimits(&m.Base.Table.Entries[0].Limits, config.MaxTableSize, "table"); err != nil {
+			return &ValidationError{ErrTableLimitExceeded, err.Error()}
+		}
+	}
+
+	for i, f := range m.Base.FunctionIndexSpace {
+		d, err := disasm.Disassemble(f, m.Base)
+		if err != nil {
+			return err
+		}
+		for _, ins := range d.Code {
+			if isFloatOp(ins.Op.Name, ins.Op.Args, ins.Op.Returns) {
+				id := numImports + i
+				name := m.FunctionNames[id]
+				if name == "" {
+					name = fmt.Sprintf("#%d", id)
+				}
+				return &ValidationError{ErrFloatInstruction, fmt.Sprintf("%s in function %s", ins.Op.Name, name)}
+			}
+		}
+	}
+	return nil
+}
+
+// validateLimits checks the initial and, if set, maximum size of a memory or
+// table declaration against the limit, 0 meaning no limit. Declarations
+// without a maximum are accepted as the virtual machine caps their growth.
+func validateLimits(limits *wasm.ResizableLimits, max int, kind string) error {
+	if max == 0 {
+		return nil
+	}
+	if int(limits.Initial) > max {
+		return fmt.Errorf("%s initial size %d, limit %d", kind, limits.Initial, max)
+	}
+	if limits.Flags&0x1 != 0 && int(limits.Maximum) > max {
+		return fmt.Errorf("%s maximum size %d, limit %d", kind, limits.Maximum, max)
+	}
+	return nil
+}
+
+// isFloatOp reports whether an operator computes on float values. Loads,
+// stores, constants and reinterpretations only move the bits of a float and
+// are deterministic, so they are allowed.
+func isFloatOp(name string, args []wasm.ValueType, returns wasm.ValueType) bool {
+	if strings.Contains(name, ".load") || strings.Contains(name, ".store") ||
+		strings.HasSuffix(name, ".const") || strings.Contains(name, ".reinterpret") {
+		return false
+	}
+	if returns == wasm.ValueTypeF32 || returns == wasm.ValueTypeF64 {
+		return true
+	}
+	for _, arg := range args {
+		if arg == wasm.ValueTypeF32 || arg == wasm.ValueTypeF64 {
+			return true
+		}
+	}
+	return false
+}
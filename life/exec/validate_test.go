@@ -0,0 +1,117 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
+)
+
+type importSet map[string]bool
+
+func (s importSet) ResolveFunc(module, field string) *FunctionImport {
+	panic("func import not allowed")
+}
+
+func (s importSet) ResolveGlobal(module, field string) int64 {
+	panic("global import not allowed")
+}
+
+func (s importSet) HasFunc(module, field string) bool {
+	return s[module+"."+field]
+}
+
+var moduleHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+func moduleBytes(sections ...byte) []byte {
+	return append(append([]byte{}, moduleHeader...), sections...)
+}
+
+func TestValidateModule(t *testing.T) {
+	config := ValidationConfig{MaxCodeSize: 64, MaxMemoryPages: 16, MaxTableSize: 16}
+	tests := []struct {
+		name string
+		code []byte
+		want error
+	}{
+		{
+			// (func (result f32) (f32.add (f32.const 1) (f32.const 1)))
+			name: "float arithmetic",
+			code: moduleBytes(
+				0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7d,
+				0x03, 0x02, 0x01, 0x00,
+				0x0a, 0x0f, 0x01, 0x0d, 0x00, 0x43, 0x00, 0x00, 0x80, 0x3f, 0x43, 0x00, 0x00, 0x80, 0x3f, 0x92, 0x0b,
+			),
+			want: ErrFloatInstruction,
+		},
+		{
+			// (func (result i32) (i32.reinterpret/f32 (f32.const 1)))
+			name: "float bits",
+			code: moduleBytes(
+				0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+				0x03, 0x02, 0x01, 0x00,
+				0x0a, 0x0a, 0x01, 0x08, 0x00, 0x43, 0x00, 0x00, 0x80, 0x3f, 0xbc, 0x0b,
+			),
+		},
+		{
+			// (import "env" "missing" (func))
+			name: "unknown import",
+			code: moduleBytes(
+				0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+				0x02, 0x0f, 0x01, 0x03, 'e', 'n', 'v', 0x07, 'm', 'i', 's', 's', 'i', 'n', 'g', 0x00, 0x00,
+			),
+			want: ErrUnknownImport,
+		},
+		{
+			// (import "env" "known" (func))
+			name: "known import",
+			code: moduleBytes(
+				0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+				0x02, 0x0d, 0x01, 0x03, 'e', 'n', 'v', 0x05, 'k', 'n', 'o', 'w', 'n', 0x00, 0x00,
+			),
+		},
+		{
+			// (memory 17)
+			name: "memory initial",
+			code: moduleBytes(0x05, 0x03, 0x01, 0x00, 0x11),
+			want: ErrMemoryLimitExceeded,
+		},
+		{
+			// (memory 1 17)
+			name: "memory maximum",
+			code: moduleBytes(0x05, 0x04, 0x01, 0x01, 0x01, 0x11),
+			want: ErrMemoryLimitExceeded,
+		},
+		{
+			// (memory 1 16)
+			name: "memory",
+			code: moduleBytes(0x05, 0x04, 0x01, 0x01, 0x01, 0x10),
+		},
+		{
+			// (table 17 anyfunc)
+			name: "table",
+			code: moduleBytes(0x04, 0x04, 0x01, 0x70, 0x00, 0x11),
+			want: ErrTableLimitExceeded,
+		},
+		{
+			name: "code size",
+			code: moduleBytes(append([]byte{0x00, 0x39, 0x01, 'x'}, make([]byte, 55)...)...),
+			want: ErrCodeTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		m, err := compiler.LoadModule(tt.code)
+		if err != nil {
+			t.Fatalf("%s: failed to load module: %v", tt.name, err)
+		}
+		err = ValidateModule(tt.code, m, config, importSet{"env.known": true})
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if verr, ok := err.(*ValidationError); !ok || verr.Err != tt.want {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
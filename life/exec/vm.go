@@ -26,6 +26,16 @@ type FunctionImport struct {
 	GasCost GasCost
 }
 
+// RevertError is raised by an import to abort the execution while keeping
+// the remaining gas. Data is handed back to the caller as the return value.
+type RevertError struct {
+	Data []byte
+}
+
+func (e *RevertError) Error() string {
+	return "execution reverted"
+}
+
 const (
 	// DefaultCallStackSize is the default call stack size.
 	DefaultCallStackSize = 512
@@ -39,6 +49,12 @@ const (
 	DefaultMemoryPages = 16
 	DynamicMemoryPages = 16
 
+	// DefaultMaxMemoryPages and DefaultMaxTableSize are the limits modules
+	// are validated against when deployed, they are not enforced on modules
+	// that already run.
+	DefaultMaxMemoryPages = 256
+	DefaultMaxTableSize   = 8192
+
 	DefaultMemPoolCount   = 5
 	DefaultMemBlockSize   = 5
 	DefaultMemTreeMaxPage = 8
@@ -97,6 +113,7 @@ type VMContext struct {
 
 	StateDB StateDB
 	Log     log.Logger
+	Tracer  Tracer
 }
 
 type VMMemory struct {
@@ -116,6 +133,7 @@ type Frame struct {
 	IP           int
 	ReturnReg    int
 	Continuation int32
+	GasMetered   bool
 }
 
 // ImportResolver is an interface for allowing one to define imports to WebAssembly modules
@@ -131,7 +149,7 @@ func ParseModuleAndFunc(code []byte, gasPolicy compiler.GasPolicy) (*compiler.Mo
 		return nil, nil, err
 	}
 
-	functionCode, err := m.CompileForInterpreter(nil)
+	functionCode, err := m.CompileForInterpreter(gasPolicy)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -319,9 +337,12 @@ func (f *Frame) Init(vm *VirtualMachine, functionID int, code compiler.Interpret
 	f.Code = code.Bytes
 	f.IP = 0
 	f.Continuation = 0
+	f.GasMetered = code.GasMetered
 
 	//fmt.Printf("Enter function %d (%s)\n", functionID, vm.Module.FunctionNames[functionID])
-	if vm.Context.Config.EnableJIT {
+	// The JIT doesn't charge gas, code compiled with gas counters always runs
+	// in the interpreter.
+	if vm.Context.Config.EnableJIT && !code.GasMetered {
 		code := &vm.FunctionCode[functionID]
 		if !code.JITDone {
 			if len(code.Bytes) > JITCodeSizeThreshold {
@@ -422,15 +443,14 @@ func (vm *VirtualMachine) Ignite(functionID int, params ...int64) {
 	copy(frame.Locals, params)
 }
 
+// AddAndCheckGas charges delta to the gas used by the context and panics
+// once the gas limit is exceeded.
 func (vm *VirtualMachine) AddAndCheckGas(delta uint64) {
-	newGas := vm.Gas + delta
-	if newGas < vm.Gas {
-		panic("gas overflow")
+	newGas := vm.Context.GasUsed + delta
+	if newGas < vm.Context.GasUsed || newGas > vm.Context.GasLimit {
+		panic(fmt.Sprintf("out of gas  cost:%d GasUsed:%d GasLimit:%d", delta, vm.Context.GasUsed, vm.Context.GasLimit))
 	}
-	if vm.Context.Config.GasLimit != 0 && newGas > vm.Context.Config.GasLimit {
-		panic("gas limit exceeded")
-	}
-	vm.Gas = newGas
+	vm.Context.GasUsed = newGas
 }
 
 // Execute starts the virtual machines main instruction processing loop.
@@ -478,11 +498,26 @@ func (vm *VirtualMachine) Execute() {
 		ins := opcodes.Opcode(frame.Code[frame.IP+4])
 		frame.IP += 5
 
-		cost, err := vm.JumpTable[ins].GasCost(vm, frame)
-		if err != nil || (cost+vm.Context.GasUsed) > vm.Context.GasLimit {
+		// Code compiled with gas counters is charged per basic block by
+		// AddGas instead, except for the cost of the host functions it
+		// invokes, which depends on their arguments.
+		var (
+			cost uint64
+			err  error
+		)
+		switch {
+		case !frame.GasMetered:
+			cost, err = vm.JumpTable[ins].GasCost(vm, frame)
+		case ins == opcodes.InvokeImport:
+			cost, err = ImportGasFunc(vm, frame)
+		}
+		if err != nil {
 			panic(fmt.Sprintf("out of gas  cost:%d GasUsed:%d GasLimit:%d", cost, vm.Context.GasUsed, vm.Context.GasLimit))
 		}
-		vm.Context.GasUsed += cost
+		vm.AddAndCheckGas(cost)
+		if vm.Context.Tracer != nil {
+			vm.Context.Tracer.CaptureStep(vm, frame, frame.IP-5, ins, vm.Context.GasLimit-vm.Context.GasUsed)
+		}
 
 		//fmt.Printf("INS: [%d] %s\n", valueID, ins.String())
 
@@ -1523,7 +1558,15 @@ func (vm *VirtualMachine) Execute() {
 			importID := int(LE.Uint32(frame.Code[frame.IP : frame.IP+4]))
 			frame.IP += 4
 			vm.Delegate = func() {
+				tracer := vm.Context.Tracer
+				if tracer == nil {
+					frame.Regs[valueID] = vm.FunctionImports[importID].Execute(vm)
+					return
+				}
+				entry := &vm.Module.Base.Import.Entries[importID]
+				tracer.CaptureHostCall(vm, entry.ModuleName, entry.FieldName, frame.Locals)
 				frame.Regs[valueID] = vm.FunctionImports[importID].Execute(vm)
+				tracer.CaptureHostReturn(vm, entry.ModuleName, entry.FieldName, frame.Regs[valueID])
 			}
 			return
 
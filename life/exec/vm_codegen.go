@@ -553,6 +553,8 @@ case 0:
 
 // GenerateCodeForFunction generates C code for the given function.
 // Returns true if codegen succeeds, or false if the current function cannot be code-generated.
+// Functions compiled with gas counters, every function of a contract from the
+// wasm gas fork on, are out of its scope and never code-generated.
 func (vm *VirtualMachine) GenerateCodeForFunction(functionID int) bool {
 	code := &vm.FunctionCode[functionID]
 	c := &jitContext{
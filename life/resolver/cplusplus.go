@@ -20,6 +20,7 @@ import (
 	"github.com/PlatONnetwork/PlatON-Go/crypto"
 	"github.com/PlatONnetwork/PlatON-Go/life/compiler"
 	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 )
 
 var (
@@ -27,7 +28,24 @@ var (
 	cgbl = newGlobalSet()
 )
 
-type CResolver struct{}
+// hostForkFuncs are the host functions added by the wasm host fork, contracts
+// importing them trap on a call before it.
+var hostForkFuncs = map[string]bool{
+	"platonDeploy":     true,
+	"platonClone":      true,
+	"platonMigrate":    true,
+	"platonRevert":     true,
+	"emitEventIndexed": true,
+	"ecrecover":        true,
+	"sha256":           true,
+	"ripemd160":        true,
+	"blsVerify":        true,
+	"vrfVerify":        true,
+}
+
+type CResolver struct {
+	preHostFork bool // hide the host functions added by the wasm host fork
+}
 
 func (r *CResolver) ResolveFunc(module, field string) *exec.FunctionImport {
 	df := &exec.FunctionImport{
@@ -39,6 +57,9 @@ func (r *CResolver) ResolveFunc(module, field string) *exec.FunctionImport {
 		},
 	}
 
+	if r.preHostFork && module == "env" && hostForkFuncs[field] {
+		return df
+	}
 	if m, exist := cfc[module]; exist == true {
 		if f, exist := m[field]; exist == true {
 			return f
@@ -50,6 +71,16 @@ func (r *CResolver) ResolveFunc(module, field string) *exec.FunctionImport {
 	}
 }
 
+// HasFunc implements exec.ImportChecker, it reports whether the host function
+// is provided by the resolver.
+func (r *CResolver) HasFunc(module, field string) bool {
+	if r.preHostFork && module == "env" && hostForkFuncs[field] {
+		return false
+	}
+	_, ok := cfc[module][field]
+	return ok
+}
+
 func (r *CResolver) ResolveGlobal(module, field string) int64 {
 	if m, exist := cgbl[module]; exist == true {
 		if g, exist := m[field]; exist == true {
@@ -91,7 +122,8 @@ func newCfcSet() map[string]map[string]*exec.FunctionImport {
 			"printn":     &exec.FunctionImport{Execute: envPrintn, GasCost: envPrintnGasCost},
 			"printhex":   &exec.FunctionImport{Execute: envPrinthex, GasCost: envPrinthexGasCost},
 
-			"abort": &exec.FunctionImport{Execute: envAbort, GasCost: envAbortGasCost},
+			"abort":        &exec.FunctionImport{Execute: envAbort, GasCost: envAbortGasCost},
+			"platonRevert": &exec.FunctionImport{Execute: envPlatonRevert, GasCost: envPlatonRevertGasCost},
 
 			// compiler builtins
 			// arithmetic long double
@@ -158,6 +190,16 @@ func newCfcSet() map[string]map[string]*exec.FunctionImport {
 			"getState":     &exec.FunctionImport{Execute: envGetState, GasCost: envGetStateGasCost},
 			"getStateSize": &exec.FunctionImport{Execute: envGetStateSize, GasCost: envGetStateSizeGasCost},
 
+			// events with indexed topics
+			"emitEventIndexed": &exec.FunctionImport{Execute: envEmitEventIndexed, GasCost: envEmitEventIndexedGasCost},
+
+			// crypto
+			"ecrecover": &exec.FunctionImport{Execute: envEcrecover, GasCost: envEcrecoverGasCost},
+			"sha256":    &exec.FunctionImport{Execute: envSha256, GasCost: envSha256GasCost},
+			"ripemd160": &exec.FunctionImport{Execute: envRipemd160, GasCost: envRipemd160GasCost},
+			"blsVerify": &exec.FunctionImport{Execute: envBlsVerify, GasCost: envBlsVerifyGasCost},
+			"vrfVerify": &exec.FunctionImport{Execute: envVrfVerify, GasCost: envVrfVerifyGasCost},
+
 			// support for vc
 			//"vc_InitGadgetEnv":          &exec.FunctionImport{Execute: envInitGadgetEnv, GasCost: envInitGadgetEnvGasCost},
 			//"vc_UninitGadgetEnv":        &exec.FunctionImport{Execute: envUninitGadgetEnv, GasCost: envUninitGadgetEnvGasCost},
@@ -179,6 +221,9 @@ func newCfcSet() map[string]map[string]*exec.FunctionImport {
 			"platonDelegateCall":       &exec.FunctionImport{Execute: envPlatonDelegateCall, GasCost: envPlatonCallStringGasCost},
 			"platonDelegateCallInt64":  &exec.FunctionImport{Execute: envPlatonDelegateCallInt64, GasCost: envPlatonCallStringGasCost},
 			"platonDelegateCallString": &exec.FunctionImport{Execute: envPlatonDelegateCallString, GasCost: envPlatonCallStringGasCost},
+			"platonDeploy":             &exec.FunctionImport{Execute: envPlatonDeploy, GasCost: envPlatonDeployGasCost},
+			"platonClone":              &exec.FunctionImport{Execute: envPlatonClone, GasCost: envPlatonCloneGasCost},
+			"platonMigrate":            &exec.FunctionImport{Execute: envPlatonMigrate, GasCost: envPlatonMigrateGasCost},
 		},
 	}
 }
@@ -494,6 +539,22 @@ func envAbortGasCost(vm *exec.VirtualMachine) (uint64, error) {
 	return 0, nil
 }
 
+// define: void platonRevert(const char *msg, size_t msgLen);
+// Aborts the execution, reverts the state changes and returns msg to the
+// caller without consuming the remaining gas.
+func envPlatonRevert(vm *exec.VirtualMachine) int64 {
+	msg := uint32(vm.GetCurrentFrame().Locals[0])
+	msgLen := uint32(vm.GetCurrentFrame().Locals[1])
+
+	data := common.CopyBytes(memoryRange(vm, msg, msgLen))
+	panic(&exec.RevertError{Data: data})
+}
+
+func envPlatonRevertGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	msgLen := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return compiler.GasQuickStep + msgLen, nil
+}
+
 // define: int64_t gasPrice();
 func envGasPrice(vm *exec.VirtualMachine) int64 {
 	gasPrice := vm.Context.StateDB.GasPrice()
@@ -646,6 +707,43 @@ func envEmitEventGasCost(vm *exec.VirtualMachine) (uint64, error) {
 	return 1, nil
 }
 
+// maxEventTopics is the number of topics a log may carry.
+const maxEventTopics = 4
+
+//void emitEventIndexed(const uint8_t *topics, size_t topicCount, const uint8_t *data, size_t dataLen);
+// topics holds topicCount 32 byte values back to back, which are stored as
+// the log topics as is.
+func envEmitEventIndexed(vm *exec.VirtualMachine) int64 {
+	topicsSrc := uint32(vm.GetCurrentFrame().Locals[0])
+	topicCount := uint32(vm.GetCurrentFrame().Locals[1])
+	dataSrc := uint32(vm.GetCurrentFrame().Locals[2])
+	dataLen := uint32(vm.GetCurrentFrame().Locals[3])
+
+	if topicCount > maxEventTopics {
+		panic(fmt.Errorf("emitEventIndexed: invalid topic count %d", topicCount))
+	}
+	src := memoryRange(vm, topicsSrc, topicCount*common.HashLength)
+	topics := make([]common.Hash, topicCount)
+	for i := range topics {
+		copy(topics[i][:], src[i*common.HashLength:])
+	}
+	d := common.CopyBytes(memoryRange(vm, dataSrc, dataLen))
+	address := vm.Context.StateDB.Address()
+	bn := vm.Context.StateDB.BlockNumber().Uint64()
+
+	vm.Context.StateDB.AddLog(address, topics, d, bn)
+	return 0
+}
+
+func envEmitEventIndexedGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	topicCount := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	dataLen := uint64(uint32(vm.GetCurrentFrame().Locals[3]))
+	if topicCount > maxEventTopics {
+		topicCount = maxEventTopics
+	}
+	return params.LogGas + topicCount*params.LogTopicGas + dataLen*params.LogDataGas, nil
+}
+
 func envSetState(vm *exec.VirtualMachine) int64 {
 	key := int(int32(vm.GetCurrentFrame().Locals[0]))
 	keyLen := int(int32(vm.GetCurrentFrame().Locals[1]))
@@ -808,3 +906,107 @@ func envPlatonCallInt64GasCost(vm *exec.VirtualMachine) (uint64, error) {
 func envPlatonCallStringGasCost(vm *exec.VirtualMachine) (uint64, error) {
 	return 1, nil
 }
+
+// define: int64_t platonDeploy(const uint8_t *code, size_t codeLen, const uint8_t value[32], uint8_t newAddr[20]);
+// code is the RLP([txType][code][abi]) payload of a contract-creation transaction.
+// Returns 0 and writes the new contract address on success, 1 on failure.
+func envPlatonDeploy(vm *exec.VirtualMachine) int64 {
+	code := uint32(vm.GetCurrentFrame().Locals[0])
+	codeLen := uint32(vm.GetCurrentFrame().Locals[1])
+	value := uint32(vm.GetCurrentFrame().Locals[2])
+	newAddr := uint32(vm.GetCurrentFrame().Locals[3])
+
+	copyCode := common.CopyBytes(memoryRange(vm, code, codeLen))
+	bValue := new(big.Int)
+	// 256 bits
+	bValue.SetBytes(memoryRange(vm, value, 32))
+	value256 := inner.U256(bValue)
+
+	return createContract(vm, newAddr, func(gas uint64) (common.Address, uint64, error) {
+		return vm.Context.StateDB.Create(copyCode, value256, gas)
+	})
+}
+
+func envPlatonDeployGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	codeLen := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return params.CreateGas + (codeLen+31)/32*params.CopyGas, nil
+}
+
+// define: int64_t platonClone(const uint8_t addr[20], const uint8_t value[32], uint8_t newAddr[20]);
+// Deploys a new contract with the code stored at addr.
+// Returns 0 and writes the new contract address on success, 1 on failure.
+func envPlatonClone(vm *exec.VirtualMachine) int64 {
+	addr := uint32(vm.GetCurrentFrame().Locals[0])
+	value := uint32(vm.GetCurrentFrame().Locals[1])
+	newAddr := uint32(vm.GetCurrentFrame().Locals[2])
+
+	src := common.BytesToAddress(memoryRange(vm, addr, 20))
+	bValue := new(big.Int)
+	// 256 bits
+	bValue.SetBytes(memoryRange(vm, value, 32))
+	value256 := inner.U256(bValue)
+
+	return createContract(vm, newAddr, func(gas uint64) (common.Address, uint64, error) {
+		return vm.Context.StateDB.Clone(src, value256, gas)
+	})
+}
+
+func envPlatonCloneGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return params.CreateGas, nil
+}
+
+// define: int64_t platonMigrate(const uint8_t *code, size_t codeLen, const uint8_t *args, size_t argsLen, const uint8_t value[32], uint8_t newAddr[20]);
+// Replaces the calling contract with a new one deployed from code, moving its storage and balance.
+// args is the RLP input of a call made to the new contract once migrated, it may be empty.
+// Returns 0 and writes the new contract address on success, 1 on failure.
+func envPlatonMigrate(vm *exec.VirtualMachine) int64 {
+	code := uint32(vm.GetCurrentFrame().Locals[0])
+	codeLen := uint32(vm.GetCurrentFrame().Locals[1])
+	args := uint32(vm.GetCurrentFrame().Locals[2])
+	argsLen := uint32(vm.GetCurrentFrame().Locals[3])
+	value := uint32(vm.GetCurrentFrame().Locals[4])
+	newAddr := uint32(vm.GetCurrentFrame().Locals[5])
+
+	copyCode := common.CopyBytes(memoryRange(vm, code, codeLen))
+	copyArgs := common.CopyBytes(memoryRange(vm, args, argsLen))
+	bValue := new(big.Int)
+	// 256 bits
+	bValue.SetBytes(memoryRange(vm, value, 32))
+	value256 := inner.U256(bValue)
+
+	return createContract(vm, newAddr, func(gas uint64) (common.Address, uint64, error) {
+		return vm.Context.StateDB.Migrate(copyCode, copyArgs, value256, gas)
+	})
+}
+
+func envPlatonMigrateGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	codeLen := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	argsLen := uint64(uint32(vm.GetCurrentFrame().Locals[3]))
+	return params.CreateGas + (codeLen+argsLen+31)/32*params.CopyGas, nil
+}
+
+// createContract forwards all but one 64th of the remaining gas to create,
+// charges what the new contract consumed and writes its address to newAddr.
+func createContract(vm *exec.VirtualMachine, newAddr uint32, create func(gas uint64) (common.Address, uint64, error)) int64 {
+	out := memoryRange(vm, newAddr, 20)
+	gas := vm.Context.GasLimit - vm.Context.GasUsed
+	gas -= gas / 64
+
+	addr, leftOverGas, err := create(gas)
+	vm.Context.GasUsed += gas - leftOverGas
+	if err != nil {
+		return 1
+	}
+	copy(out, addr.Bytes())
+	return 0
+}
+
+// memoryRange returns size bytes of the memory of the module at offset, both
+// read as the unsigned 32 bit arguments of a host function. It traps if the
+// range is out of the memory, instead of slicing it with a negative bound.
+func memoryRange(vm *exec.VirtualMachine, offset, size uint32) []byte {
+	if end := uint64(offset) + uint64(size); end > uint64(len(vm.Memory.Memory)) {
+		panic(fmt.Sprintf("memory access out of bounds(%d + %d > %d)", offset, size, len(vm.Memory.Memory)))
+	}
+	return vm.Memory.Memory[offset : offset+size]
+}
@@ -2,7 +2,12 @@ package resolver
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/params"
 )
 
 func TestCfcSet(t *testing.T) {
@@ -14,3 +19,172 @@ func TestCfcSet(t *testing.T) {
 		}
 	}
 }
+
+func TestPreHostForkResolver(t *testing.T) {
+	r, pre := NewResolver(0x01), NewPreHostForkResolver(0x01)
+	for field := range hostForkFuncs {
+		if _, ok := cfc["env"][field]; !ok {
+			t.Fatalf("%s: missing from the host functions", field)
+		}
+		if !r.(exec.ImportChecker).HasFunc("env", field) {
+			t.Errorf("%s: not provided after the fork", field)
+		}
+		if pre.(exec.ImportChecker).HasFunc("env", field) {
+			t.Errorf("%s: provided before the fork", field)
+		}
+		if pre.ResolveFunc("env", field) == cfc["env"][field] {
+			t.Errorf("%s: resolved before the fork", field)
+		}
+	}
+	if pre.ResolveFunc("env", "malloc") != cfc["env"]["malloc"] {
+		t.Error("malloc: not resolved before the fork")
+	}
+}
+
+// logStateDB records the logs added by a contract.
+type logStateDB struct {
+	exec.StateDB
+	topics [][]common.Hash
+	data   [][]byte
+}
+
+func (db *logStateDB) Address() common.Address { return common.Address{1} }
+func (db *logStateDB) BlockNumber() *big.Int   { return big.NewInt(1) }
+func (db *logStateDB) AddLog(address common.Address, topics []common.Hash, data []byte, bn uint64) {
+	db.topics = append(db.topics, topics)
+	db.data = append(db.data, data)
+}
+
+func TestEnvEmitEventIndexed(t *testing.T) {
+	from, to := common.HexToHash("0x01"), common.HexToHash("0x02")
+	data := []byte("transfer")
+	vm, offsets := newTestVM(append(from.Bytes(), to.Bytes()...), data)
+	db := &logStateDB{}
+	vm.Context.StateDB = db
+	vm.GetCurrentFrame().Locals = []int64{offsets[0], 2, offsets[1], int64(len(data))}
+
+	if gas, _ := envEmitEventIndexedGasCost(vm); gas != params.LogGas+2*params.LogTopicGas+8*params.LogDataGas {
+		t.Errorf("gas mismatch: have %d", gas)
+	}
+	envEmitEventIndexed(vm)
+	if len(db.topics) != 1 || len(db.topics[0]) != 2 {
+		t.Fatalf("unexpected logs: %v", db.topics)
+	}
+	if db.topics[0][0] != from || db.topics[0][1] != to {
+		t.Errorf("topic mismatch: have %v", db.topics[0])
+	}
+	if string(db.data[0]) != "transfer" {
+		t.Errorf("data mismatch: have %q", db.data[0])
+	}
+
+	// Too many topics and buffers reaching past the memory trap
+	for _, locals := range [][]int64{
+		{offsets[0], 5, offsets[1], int64(len(data))},
+		{1000, 1, offsets[1], int64(len(data))},
+		{-1, 1, offsets[1], int64(len(data))},
+		{offsets[0], 2, offsets[1], 0xffffffff},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("locals %v: no trap", locals)
+				}
+			}()
+			vm.GetCurrentFrame().Locals = locals
+			envEmitEventIndexed(vm)
+		}()
+	}
+	if len(db.topics) != 1 {
+		t.Errorf("log added by a trapping call: %v", db.topics)
+	}
+}
+
+// createStateDB records the contracts created by a contract.
+type createStateDB struct {
+	exec.StateDB
+	code  []byte
+	src   common.Address
+	value *big.Int
+	gas   uint64
+	used  uint64 // Gas consumed by the new contract
+}
+
+func (db *createStateDB) Create(code []byte, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	db.code, db.value, db.gas = code, value, gas
+	return common.Address{0xc0}, gas - db.used, nil
+}
+
+func (db *createStateDB) Clone(addr common.Address, value *big.Int, gas uint64) (common.Address, uint64, error) {
+	db.src, db.value, db.gas = addr, value, gas
+	return common.Address{0xc1}, gas - db.used, nil
+}
+
+func TestEnvPlatonDeploy(t *testing.T) {
+	code := []byte("contract code")
+	value := common.BigToHash(big.NewInt(7))
+	vm, offsets := newTestVM(code, value.Bytes(), make([]byte, 20))
+	db := &createStateDB{used: 1000}
+	vm.Context.StateDB = db
+	vm.Context.GasLimit, vm.Context.GasUsed = 64100, 100
+	vm.GetCurrentFrame().Locals = []int64{offsets[0], int64(len(code)), offsets[1], offsets[2]}
+
+	if gas, _ := envPlatonDeployGasCost(vm); gas != params.CreateGas+params.CopyGas {
+		t.Errorf("gas mismatch: have %d", gas)
+	}
+	if ret := envPlatonDeploy(vm); ret != 0 {
+		t.Fatalf("deploy failed: %d", ret)
+	}
+	if string(db.code) != string(code) || db.value.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("create mismatch: have code %q, value %v", db.code, db.value)
+	}
+	// All but one 64th of the remaining gas is forwarded, the used part charged
+	if db.gas != 63000 {
+		t.Errorf("forwarded gas mismatch: have %d, want %d", db.gas, 63000)
+	}
+	if vm.Context.GasUsed != 1100 {
+		t.Errorf("used gas mismatch: have %d, want %d", vm.Context.GasUsed, 1100)
+	}
+	if addr := common.BytesToAddress(vm.Memory.Memory[offsets[2] : offsets[2]+20]); addr != (common.Address{0xc0}) {
+		t.Errorf("address mismatch: have %x", addr)
+	}
+}
+
+func TestEnvPlatonClone(t *testing.T) {
+	src := common.Address{0xaa}
+	value := common.BigToHash(big.NewInt(9))
+	vm, offsets := newTestVM(src.Bytes(), value.Bytes(), make([]byte, 20))
+	db := &createStateDB{used: 10}
+	vm.Context.StateDB = db
+	vm.Context.GasLimit = 6400
+	vm.GetCurrentFrame().Locals = []int64{offsets[0], offsets[1], offsets[2]}
+
+	if ret := envPlatonClone(vm); ret != 0 {
+		t.Fatalf("clone failed: %d", ret)
+	}
+	if db.src != src || db.value.Cmp(big.NewInt(9)) != 0 || db.gas != 6300 {
+		t.Errorf("clone mismatch: have source %x, value %v, gas %d", db.src, db.value, db.gas)
+	}
+	if vm.Context.GasUsed != 10 {
+		t.Errorf("used gas mismatch: have %d, want %d", vm.Context.GasUsed, 10)
+	}
+	if addr := common.BytesToAddress(vm.Memory.Memory[offsets[2] : offsets[2]+20]); addr != (common.Address{0xc1}) {
+		t.Errorf("address mismatch: have %x", addr)
+	}
+}
+
+func TestEnvPlatonDeployOutOfBounds(t *testing.T) {
+	vm, offsets := newTestVM(common.Hash{}.Bytes(), make([]byte, 20))
+	vm.Context.StateDB = &createStateDB{}
+	// A length of 2^31 or more is not read as a negative one
+	vm.GetCurrentFrame().Locals = []int64{0, 1 << 31, offsets[0], offsets[1]}
+
+	if gas, _ := envPlatonDeployGasCost(vm); gas != params.CreateGas+(1<<31+31)/32*params.CopyGas {
+		t.Errorf("gas mismatch: have %d", gas)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected trap for code out of memory")
+		}
+	}()
+	envPlatonDeploy(vm)
+}
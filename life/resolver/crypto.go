@@ -0,0 +1,170 @@
+package resolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/vrf"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+	"github.com/PlatONnetwork/PlatON-Go/params"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// define: int64_t ecrecover(const uint8_t hash[32], const uint8_t *sig, size_t sigLen, uint8_t addr[20]);
+// sig is r || s || v with v in {0, 1, 27, 28}.
+// Returns 0 and writes the signer address on success, 1 on failure.
+func envEcrecover(vm *exec.VirtualMachine) int64 {
+	hash := uint32(vm.GetCurrentFrame().Locals[0])
+	sig := uint32(vm.GetCurrentFrame().Locals[1])
+	sigLen := uint32(vm.GetCurrentFrame().Locals[2])
+	addr := uint32(vm.GetCurrentFrame().Locals[3])
+
+	signer, ok := ecrecoverAddress(memoryRange(vm, hash, 32), memoryRange(vm, sig, sigLen))
+	if !ok {
+		return 1
+	}
+	copy(memoryRange(vm, addr, 20), signer)
+	return 0
+}
+
+func envEcrecoverGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return params.EcrecoverGas, nil
+}
+
+// ecrecoverAddress applies the same checks as the ecrecover precompile.
+func ecrecoverAddress(hash, sig []byte) ([]byte, bool) {
+	if len(sig) != 65 {
+		return nil, false
+	}
+	r := new(big.Int).SetBytes(sig[0:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if !crypto.ValidateSignatureValues(v, r, s, false) {
+		return nil, false
+	}
+	rsv := make([]byte, 65)
+	copy(rsv, sig[:64])
+	rsv[64] = v
+	pubKey, err := crypto.Ecrecover(hash, rsv)
+	if err != nil {
+		return nil, false
+	}
+	// the first byte of pubkey is bitcoin heritage
+	return crypto.Keccak256(pubKey[1:])[12:], true
+}
+
+// define: void sha256(char *src, size_t srcLen, char *dest, size_t destLen);
+// Traps if dest is shorter than the 32 byte digest.
+func envSha256(vm *exec.VirtualMachine) int64 {
+	offset := uint32(vm.GetCurrentFrame().Locals[0])
+	size := uint32(vm.GetCurrentFrame().Locals[1])
+	destOffset := uint32(vm.GetCurrentFrame().Locals[2])
+	destSize := uint32(vm.GetCurrentFrame().Locals[3])
+
+	if destSize < sha256.Size {
+		panic(fmt.Sprintf("sha256 output buffer too small(%d < %d)", destSize, sha256.Size))
+	}
+	hash := sha256.Sum256(memoryRange(vm, offset, size))
+	copy(memoryRange(vm, destOffset, destSize), hash[:])
+	return 0
+}
+
+func envSha256GasCost(vm *exec.VirtualMachine) (uint64, error) {
+	size := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return wordGasCost(size, params.Sha256BaseGas, params.Sha256PerWordGas), nil
+}
+
+// define: void ripemd160(char *src, size_t srcLen, char *dest, size_t destLen);
+// Traps if dest is shorter than the 20 byte digest.
+func envRipemd160(vm *exec.VirtualMachine) int64 {
+	offset := uint32(vm.GetCurrentFrame().Locals[0])
+	size := uint32(vm.GetCurrentFrame().Locals[1])
+	destOffset := uint32(vm.GetCurrentFrame().Locals[2])
+	destSize := uint32(vm.GetCurrentFrame().Locals[3])
+
+	if destSize < ripemd160.Size {
+		panic(fmt.Sprintf("ripemd160 output buffer too small(%d < %d)", destSize, ripemd160.Size))
+	}
+	ripemd := ripemd160.New()
+	ripemd.Write(memoryRange(vm, offset, size))
+	copy(memoryRange(vm, destOffset, destSize), ripemd.Sum(nil))
+	return 0
+}
+
+func envRipemd160GasCost(vm *exec.VirtualMachine) (uint64, error) {
+	size := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return wordGasCost(size, params.Ripemd160BaseGas, params.Ripemd160PerWordGas), nil
+}
+
+// define: int64_t blsVerify(const uint8_t *pubKey, size_t pubKeyLen, const uint8_t *sig, size_t sigLen, const uint8_t *msg, size_t msgLen);
+// Returns 1 if sig is a valid BLS signature of msg under pubKey, 0 otherwise.
+func envBlsVerify(vm *exec.VirtualMachine) int64 {
+	pubKey, sig, msg := verifyArgs(vm)
+
+	var pub bls.PublicKey
+	if err := pub.Deserialize(pubKey); err != nil {
+		return 0
+	}
+	var sign bls.Sign
+	if err := sign.Deserialize(sig); err != nil {
+		return 0
+	}
+	if len(msg) == 0 || !sign.Verify(&pub, string(msg)) {
+		return 0
+	}
+	return 1
+}
+
+func envBlsVerifyGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return params.BlsVerifyGas, nil
+}
+
+// define: int64_t vrfVerify(const uint8_t *pubKey, size_t pubKeyLen, const uint8_t *proof, size_t proofLen, const uint8_t *msg, size_t msgLen);
+// pubKey is a secp256k1 key, either compressed (33 bytes) or uncompressed (65 bytes).
+// Returns 1 if proof is a valid VRF proof of msg under pubKey, 0 otherwise.
+func envVrfVerify(vm *exec.VirtualMachine) int64 {
+	pubKey, proof, msg := verifyArgs(vm)
+
+	var (
+		pub *ecdsa.PublicKey
+		err error
+	)
+	if len(pubKey) == 33 {
+		pub, err = crypto.DecompressPubkey(pubKey)
+	} else {
+		pub, err = crypto.UnmarshalPubkey(pubKey)
+	}
+	if err != nil {
+		return 0
+	}
+	if ok, err := vrf.Verify(pub, proof, msg); err != nil || !ok {
+		return 0
+	}
+	return 1
+}
+
+func envVrfVerifyGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return params.VrfVerifyGas, nil
+}
+
+// wordGasCost returns base + perWord for every started 32 byte word of size,
+// the pricing of the hash precompiles.
+func wordGasCost(size, base, perWord uint64) uint64 {
+	return (size+31)/32*perWord + base
+}
+
+// verifyArgs reads the (pubKey, sig, msg) buffers shared by the verify imports.
+func verifyArgs(vm *exec.VirtualMachine) (pubKey, sig, msg []byte) {
+	locals := vm.GetCurrentFrame().Locals
+	buf := func(i int) []byte {
+		return memoryRange(vm, uint32(locals[i]), uint32(locals[i+1]))
+	}
+	return buf(0), buf(2), buf(4)
+}
@@ -0,0 +1,203 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/common"
+	"github.com/PlatONnetwork/PlatON-Go/crypto"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/bls"
+	"github.com/PlatONnetwork/PlatON-Go/crypto/vrf"
+	"github.com/PlatONnetwork/PlatON-Go/life/exec"
+)
+
+// precompiledTest is an input/output pair of the precompiled contract tests
+// in core/vm, whose vectors the native crypto imports share.
+type precompiledTest struct {
+	Input, Expected string
+	Gas             uint64
+	Name            string
+}
+
+func loadPrecompiledTests(t *testing.T, name string) []precompiledTest {
+	data, err := ioutil.ReadFile(fmt.Sprintf("../../core/vm/testdata/precompiles/%s.json", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []precompiledTest
+	if err := json.Unmarshal(data, &tests); err != nil {
+		t.Fatal(err)
+	}
+	return tests
+}
+
+// newTestVM returns a vm whose current frame holds locals and whose
+// memory holds the given buffers back to back. The offset of every
+// buffer is returned in order.
+func newTestVM(bufs ...[]byte) (*exec.VirtualMachine, []int64) {
+	mem := make([]byte, 1024)
+	offsets := make([]int64, 0, len(bufs))
+	pos := 0
+	for _, b := range bufs {
+		copy(mem[pos:], b)
+		offsets = append(offsets, int64(pos))
+		pos += len(b)
+	}
+	vm := &exec.VirtualMachine{
+		Context:   &exec.VMContext{},
+		CallStack: make([]exec.Frame, 1),
+		Memory:    &exec.Memory{Memory: mem},
+	}
+	return vm, offsets
+}
+
+func TestEnvEcrecover(t *testing.T) {
+	for _, test := range loadPrecompiledTests(t, "ecRecover") {
+		input := common.Hex2Bytes(test.Input)
+		hash := input[:32]
+		// precompile input is (hash, v, r, s), the import takes r || s || v
+		sig := append(append([]byte{}, input[64:128]...), input[63])
+
+		vm, offsets := newTestVM(hash, sig, make([]byte, 20))
+		vm.GetCurrentFrame().Locals = []int64{offsets[0], offsets[1], int64(len(sig)), offsets[2]}
+		ret := envEcrecover(vm)
+		if test.Expected == "" {
+			if ret != 1 {
+				t.Errorf("%s: ecrecover should fail", test.Name)
+			}
+			continue
+		}
+		if ret != 0 {
+			t.Fatalf("%s: ecrecover failed, ret %d", test.Name, ret)
+		}
+		addr := vm.Memory.Memory[offsets[2] : offsets[2]+20]
+		if have, want := common.Bytes2Hex(addr), test.Expected[24:]; have != want {
+			t.Errorf("%s: address mismatch, want %s, have %s", test.Name, want, have)
+		}
+		if gas, _ := envEcrecoverGasCost(vm); gas != test.Gas {
+			t.Errorf("%s: gas mismatch, want %d, have %d", test.Name, test.Gas, gas)
+		}
+	}
+}
+
+func TestEnvHashes(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		execute exec.Execute
+		gasCost exec.GasCost
+	}{
+		{"sha256", 32, envSha256, envSha256GasCost},
+		{"ripemd160", 20, envRipemd160, envRipemd160GasCost},
+	}
+	for _, test := range tests {
+		for _, vector := range loadPrecompiledTests(t, test.name) {
+			input := common.Hex2Bytes(vector.Input)
+			vm, offsets := newTestVM(input, make([]byte, 32))
+			vm.GetCurrentFrame().Locals = []int64{offsets[0], int64(len(input)), offsets[1], 32}
+			test.execute(vm)
+			out := vm.Memory.Memory[offsets[1] : offsets[1]+int64(test.size)]
+			// the precompile left pads the ripemd160 digest to a word
+			if have, want := common.Bytes2Hex(out), vector.Expected[len(vector.Expected)-2*test.size:]; have != want {
+				t.Errorf("%s-%s: result mismatch, want %s, have %s", test.name, vector.Name, want, have)
+			}
+			if gas, _ := test.gasCost(vm); gas != vector.Gas {
+				t.Errorf("%s-%s: gas mismatch, want %d, have %d", test.name, vector.Name, vector.Gas, gas)
+			}
+		}
+		// an output buffer shorter than the digest traps
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: short output buffer accepted", test.name)
+				}
+			}()
+			vm, offsets := newTestVM([]byte("platon"), make([]byte, 32))
+			vm.GetCurrentFrame().Locals = []int64{offsets[0], 6, offsets[1], int64(test.size - 1)}
+			test.execute(vm)
+		}()
+	}
+}
+
+// Tests that buffers reaching past the memory trap instead of being sliced.
+func TestEnvCryptoOutOfBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		execute exec.Execute
+		locals  []int64
+	}{
+		{"ecrecover-sig", envEcrecover, []int64{0, 32, 0xffffffff, 0}},
+		{"sha256-src", envSha256, []int64{1000, 100, 0, 32}},
+		{"sha256-dest", envSha256, []int64{0, 32, 1000, 32}},
+		{"ripemd160-src", envRipemd160, []int64{-1, 1, 0, 20}},
+		{"ripemd160-dest", envRipemd160, []int64{0, 32, 1010, 20}},
+		{"blsVerify", envBlsVerify, []int64{0, 32, 0, 32, 0x7fffffff, 2}},
+		{"vrfVerify", envVrfVerify, []int64{0, 33, 1024, 1, 0, 32}},
+	}
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if err := recover(); err == nil || !strings.Contains(fmt.Sprint(err), "out of bounds") {
+					t.Errorf("%s: trap mismatch: have %v", test.name, err)
+				}
+			}()
+			vm, _ := newTestVM()
+			vm.GetCurrentFrame().Locals = test.locals
+			test.execute(vm)
+		}()
+	}
+}
+
+func TestEnvBlsVerify(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+	var sec bls.SecretKey
+	sec.SetByCSPRNG()
+	msg := []byte("platon")
+	pub := sec.GetPublicKey().Serialize()
+	sig := sec.Sign(string(msg)).Serialize()
+
+	vm, offsets := newTestVM(pub, sig, msg)
+	vm.GetCurrentFrame().Locals = []int64{offsets[0], int64(len(pub)), offsets[1], int64(len(sig)), offsets[2], int64(len(msg))}
+	if ret := envBlsVerify(vm); ret != 1 {
+		t.Fatal("valid bls signature rejected")
+	}
+
+	vm, offsets = newTestVM(pub, sig, []byte("platoN"))
+	vm.GetCurrentFrame().Locals = []int64{offsets[0], int64(len(pub)), offsets[1], int64(len(sig)), offsets[2], int64(len(msg))}
+	if ret := envBlsVerify(vm); ret != 0 {
+		t.Fatal("bls signature of another message accepted")
+	}
+}
+
+func TestEnvVrfVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := make([]byte, 32)
+	rand.Read(msg)
+	proof, err := vrf.Prove(key, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pub := range [][]byte{crypto.FromECDSAPub(&key.PublicKey), crypto.CompressPubkey(&key.PublicKey)} {
+		vm, offsets := newTestVM(pub, proof, msg)
+		vm.GetCurrentFrame().Locals = []int64{offsets[0], int64(len(pub)), offsets[1], int64(len(proof)), offsets[2], int64(len(msg))}
+		if ret := envVrfVerify(vm); ret != 1 {
+			t.Fatalf("valid vrf proof rejected, pubkey length %d", len(pub))
+		}
+	}
+
+	other, _ := crypto.GenerateKey()
+	pub := crypto.FromECDSAPub(&other.PublicKey)
+	vm, offsets := newTestVM(pub, proof, msg)
+	vm.GetCurrentFrame().Locals = []int64{offsets[0], int64(len(pub)), offsets[1], int64(len(proof)), offsets[2], int64(len(msg))}
+	if ret := envVrfVerify(vm); ret != 0 {
+		t.Fatal("vrf proof accepted under another key")
+	}
+}
@@ -20,6 +20,19 @@ func NewResolver(lang int) exec.ImportResolver {
 	return nil
 }
 
+// NewPreHostForkResolver returns an import resolver without the host functions
+// added by the wasm host fork, resolving them to a trap the way unknown host
+// functions are.
+func NewPreHostForkResolver(lang int) exec.ImportResolver {
+	switch lang {
+	case clang:
+		return &CResolver{preHostFork: true}
+	case golang:
+	default:
+	}
+	return nil
+}
+
 func MallocString(vm *exec.VirtualMachine, str string) int64 {
 	mem := vm.Memory
 	size := len([]byte(str)) + 1
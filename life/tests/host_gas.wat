@@ -0,0 +1,13 @@
+(module
+    (import "env" "sha256" (func $sha256 (param i32 i32 i32 i32)))
+    (memory 1)
+    (func (result i32)
+        i32.const 0
+        i32.const 64
+        i32.const 64
+        i32.const 32
+        call $sha256
+        i32.const 64
+        i32.load8_u ;; 245, the sha256 of 64 zero bytes starts with 0xf5
+    )
+)
@@ -20,6 +20,9 @@ type AbiStruct struct {
 type InputParam struct {
 	Name string		`json:"name"`
 	Type string		`json:"type"`
+	// Indexed marks an input of an event that is stored as a log topic,
+	// in order of declaration, rather than in the log data.
+	Indexed bool	`json:"indexed,omitempty"`
 }
 
 type OutputsParam struct {
@@ -33,4 +36,16 @@ func (abi *WasmAbi) FromJson(body []byte) error {
 	}
 	err := json.Unmarshal(body, &abi.AbiArr)
 	return err
-}
\ No newline at end of file
+}
+
+// IndexedInputs returns the inputs of an event that are stored as log
+// topics, in order of declaration.
+func (abi *AbiStruct) IndexedInputs() []InputParam {
+	var indexed []InputParam
+	for _, input := range abi.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	return indexed
+}
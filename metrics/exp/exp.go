@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
 	"github.com/PlatONnetwork/PlatON-Go/metrics/prometheus"
 )
@@ -46,6 +47,21 @@ func Exp(r metrics.Registry) {
 	http.Handle("/debug/metrics/prometheus", prometheus.Handler(r))
 }
 
+// Setup starts a dedicated metrics server at the given address, serving the
+// default registry on "/debug/metrics" and "/debug/metrics/prometheus". The
+// labels are attached to every Prometheus sample.
+func Setup(address string, labels map[string]string) {
+	m := http.NewServeMux()
+	m.Handle("/debug/metrics", ExpHandler(metrics.DefaultRegistry))
+	m.Handle("/debug/metrics/prometheus", prometheus.LabeledHandler(metrics.DefaultRegistry, labels))
+	log.Info("Starting metrics server", "addr", fmt.Sprintf("http://%s/debug/metrics", address))
+	go func() {
+		if err := http.ListenAndServe(address, m); err != nil {
+			log.Error("Failure in running metrics server", "err", err)
+		}
+	}()
+}
+
 // ExpHandler will return an expvar powered metrics handler.
 func ExpHandler(r metrics.Registry) http.Handler {
 	e := exp{sync.Mutex{}, r}
@@ -30,19 +30,22 @@ var (
 	typeCounterTpl         = "# TYPE %s counter\n"
 	typeSummaryTpl         = "# TYPE %s summary\n"
 	keyValueTpl            = "%s %v\n\n"
-	keyQuantileTagValueTpl = "%s {quantile=\"%s\"} %v\n\n"
+	keyTagValueTpl         = "%s{%s} %v\n\n"
+	keyQuantileTagValueTpl = "%s {quantile=\"%s\"%s} %v\n\n"
 )
 
 // collector is a collection of byte buffers that aggregate Prometheus reports
 // for different metric types.
 type collector struct {
-	buff *bytes.Buffer
+	buff   *bytes.Buffer
+	labels string // pre-rendered labels attached to every sample
 }
 
 // newCollector createa a new Prometheus metric aggregator.
-func newCollector() *collector {
+func newCollector(labels string) *collector {
 	return &collector{
-		buff: &bytes.Buffer{},
+		buff:   &bytes.Buffer{},
+		labels: labels,
 	}
 }
 
@@ -95,19 +98,31 @@ func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
 func (c *collector) writeGaugeCounter(name string, value interface{}) {
 	name = mutateKey(name)
 	c.buff.WriteString(fmt.Sprintf(typeGaugeTpl, name))
-	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+	c.writeValue(name, value)
 }
 
 func (c *collector) writeSummaryCounter(name string, value interface{}) {
 	name = mutateKey(name + "_count")
 	c.buff.WriteString(fmt.Sprintf(typeCounterTpl, name))
-	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+	c.writeValue(name, value)
 }
 
 func (c *collector) writeSummaryPercentile(name, p string, value interface{}) {
 	name = mutateKey(name)
 	c.buff.WriteString(fmt.Sprintf(typeSummaryTpl, name))
-	c.buff.WriteString(fmt.Sprintf(keyQuantileTagValueTpl, name, p, value))
+	labels := c.labels
+	if labels != "" {
+		labels = "," + labels
+	}
+	c.buff.WriteString(fmt.Sprintf(keyQuantileTagValueTpl, name, p, labels, value))
+}
+
+func (c *collector) writeValue(name string, value interface{}) {
+	if c.labels == "" {
+		c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+	} else {
+		c.buff.WriteString(fmt.Sprintf(keyTagValueTpl, name, c.labels, value))
+	}
 }
 
 func mutateKey(key string) string {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/PlatONnetwork/PlatON-Go/log"
 	"github.com/PlatONnetwork/PlatON-Go/metrics"
@@ -28,6 +29,13 @@ import (
 
 // Handler returns an HTTP handler which dump metrics in Prometheus format.
 func Handler(reg metrics.Registry) http.Handler {
+	return LabeledHandler(reg, nil)
+}
+
+// LabeledHandler returns an HTTP handler which dump metrics in Prometheus
+// format, attaching the given labels to every sample.
+func LabeledHandler(reg metrics.Registry, labels map[string]string) http.Handler {
+	tags := formatLabels(labels)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Gather and pre-sort the metrics to avoid random listings
 		var names []string
@@ -37,7 +45,7 @@ func Handler(reg metrics.Registry) http.Handler {
 		sort.Strings(names)
 
 		// Aggregate all the metris into a Prometheus collector
-		c := newCollector()
+		c := newCollector(tags)
 
 		for _, name := range names {
 			i := reg.Get(name)
@@ -66,3 +74,14 @@ func Handler(reg metrics.Registry) http.Handler {
 		w.Write(c.buff.Bytes())
 	})
 }
+
+// formatLabels renders the labels as the sorted, comma separated list of
+// name="value" pairs found between the braces of a sample.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,45 @@
+package prometheus
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PlatONnetwork/PlatON-Go/metrics"
+)
+
+func init() {
+	metrics.Enabled = true
+}
+
+func TestLabeledHandler(t *testing.T) {
+	reg := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("p2p/peers", reg).Inc(3)
+	timer := metrics.NewRegisteredTimer("chain/inserts", reg)
+	timer.Update(1000)
+
+	rec := httptest.NewRecorder()
+	LabeledHandler(reg, map[string]string{"node_id": "abc", "chain_id": "100"}).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/metrics/prometheus", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`p2p_peers{chain_id="100",node_id="abc"} 3`,
+		`chain_inserts_count{chain_id="100",node_id="abc"} 1`,
+		`chain_inserts {quantile="0.5",chain_id="100",node_id="abc"} 1000`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing sample %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandlerWithoutLabels(t *testing.T) {
+	reg := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("txpool/pending", reg).Inc(7)
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/metrics/prometheus", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "txpool_pending 7\n") {
+		t.Errorf("missing unlabeled sample in:\n%s", body)
+	}
+}
@@ -716,7 +716,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 		return err
 	}
 	env := &environment{
-		signer: types.NewEIP155Signer(w.config.ChainID),
+		signer: types.MakeSigner(w.config, header.Number),
 		state:  state,
 		header: header,
 	}
@@ -1139,27 +1139,60 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	for _, accTxs := range pending {
 		txsCount = txsCount + len(accTxs)
 	}
-	// Split the pending transactions into locals and remotes
-	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
+	// Split the pending transactions into priority ones, locals and remotes. The
+	// priority lane takes the transactions of an account up to its last priority
+	// transaction, the ones after it are packed with the others.
+	priorityTxs, localTxs, remoteTxs := make(map[common.Address]types.Transactions), make(map[common.Address]types.Transactions), pending
+	for account, txs := range remoteTxs {
+		for i := len(txs) - 1; i >= 0; i-- {
+			if w.eth.TxPool().IsPriority(txs[i]) {
+				priorityTxs[account] = txs[:i+1]
+				if rest := txs[i+1:]; len(rest) > 0 {
+					remoteTxs[account] = rest
+				} else {
+					delete(remoteTxs, account)
+				}
+				break
+			}
+		}
+	}
 	for _, account := range w.eth.TxPool().Locals() {
 		if txs := remoteTxs[account]; len(txs) > 0 {
 			delete(remoteTxs, account)
 			localTxs[account] = txs
 		}
 	}
+	priorityTxsCount := 0
 	localTxsCount := 0
 	remoteTxsCount := 0
+	for _, paccTxs := range priorityTxs {
+		priorityTxsCount = priorityTxsCount + len(paccTxs)
+	}
 	for _, laccTxs := range localTxs {
 		localTxsCount = localTxsCount + len(laccTxs)
 	}
 	for _, raccTxs := range remoteTxs {
 		remoteTxsCount = remoteTxsCount + len(raccTxs)
 	}
-	log.Debug("Execute pending transactions", "number", header.Number, "localTxCount", localTxsCount, "remoteTxCount", remoteTxsCount, "txsCount", txsCount)
+	log.Debug("Execute pending transactions", "number", header.Number, "priorityTxCount", priorityTxsCount, "localTxCount", localTxsCount, "remoteTxCount", remoteTxsCount, "txsCount", txsCount)
+
+	startTime = time.Now()
+	var priorityTimeout = false
+	if len(priorityTxs) > 0 {
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, priorityTxs)
+		if ok, timeout := w.commitTransactionsWithHeader(header, txs, interrupt, timestamp, blockDeadline); ok {
+			return
+		} else {
+			priorityTimeout = timeout
+		}
+	}
+
+	commitPriorityTxCount := w.current.tcount
+	log.Debug("Priority transactions executing stat", "number", header.Number, "involvedTxCount", commitPriorityTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	startTime = time.Now()
-	var localTimeout = false
-	if len(localTxs) > 0 {
+	var localTimeout = priorityTimeout
+	if !priorityTimeout && len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
 		if ok, timeout := w.commitTransactionsWithHeader(header, txs, interrupt, timestamp, blockDeadline); ok {
 			return
@@ -1168,7 +1201,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		}
 	}
 
-	commitLocalTxCount := w.current.tcount
+	commitLocalTxCount := w.current.tcount - commitPriorityTxCount
 	log.Debug("Local transactions executing stat", "number", header.Number, "involvedTxCount", commitLocalTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	startTime = time.Now()
@@ -1178,7 +1211,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 			return
 		}
 	}
-	commitRemoteTxCount := w.current.tcount - commitLocalTxCount
+	commitRemoteTxCount := w.current.tcount - commitPriorityTxCount - commitLocalTxCount
 	log.Debug("Remote transactions executing stat", "number", header.Number, "involvedTxCount", commitRemoteTxCount, "time", common.PrettyDuration(time.Since(startTime)))
 
 	if err := w.commit(w.fullTaskHook, true, tstart); nil != err {
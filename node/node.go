@@ -27,6 +27,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/PlatONnetwork/PlatON-Go/core/rawdb"
 	"github.com/PlatONnetwork/PlatON-Go/core/snapshotdb"
 
 	"github.com/PlatONnetwork/PlatON-Go/accounts"
@@ -596,6 +597,40 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (ethdb.Database, er
 	return ethdb.NewLDBDatabase(n.config.ResolvePath(name), cache, handles)
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's instance
+// directory, also attaching a chain freezer to it that moves ancient chain data
+// from the database to immutable append-only files. No freezer is attached if
+// its directory is empty, a relative one is resolved within the database
+// directory. If the node is ephemeral, a memory database is returned.
+func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer string) (ethdb.Database, error) {
+	if n.config.DataDir == "" {
+		return ethdb.NewMemDatabase(), nil
+	}
+	return openDatabaseWithFreezer(n.config.ResolvePath(name), cache, handles, freezer)
+}
+
+// openDatabaseWithFreezer opens the leveldb at the given path and attaches the
+// chain freezer in the given directory to it.
+func openDatabaseWithFreezer(path string, cache, handles int, freezer string) (ethdb.Database, error) {
+	db, err := ethdb.NewLDBDatabase(path, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	if freezer == "" {
+		return db, nil
+	}
+	if !filepath.IsAbs(freezer) {
+		freezer = filepath.Join(path, freezer)
+	}
+	frdb, err := rawdb.NewDatabaseWithFreezer(db, freezer)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
 // ResolvePath returns the absolute path of a resource in the instance directory.
 func (n *Node) ResolvePath(x string) string {
 	return n.config.ResolvePath(x)
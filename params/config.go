@@ -158,9 +158,9 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, ""}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, ""}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), "", big.NewInt(0), big.NewInt(0), nil, new(CbftConfig), ""}
+	TestChainConfig = &ChainConfig{big.NewInt(1), "", big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(CbftConfig), ""}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -181,10 +181,16 @@ type TrustedCheckpoint struct {
 // that any network, identified by its genesis block, can have its own
 // set of configuration options.
 type ChainConfig struct {
-	ChainID     *big.Int `json:"chainId"` // chainId identifies the current chain and is used for replay protection
-	EmptyBlock  string   `json:"emptyBlock"`
-	EIP155Block *big.Int `json:"eip155Block,omitempty"` // EIP155 HF block
-	EWASMBlock  *big.Int `json:"ewasmBlock,omitempty"`  // EWASM switch block (nil = no fork, 0 = already activated)
+	ChainID           *big.Int `json:"chainId"` // chainId identifies the current chain and is used for replay protection
+	EmptyBlock        string   `json:"emptyBlock"`
+	EIP155Block       *big.Int `json:"eip155Block,omitempty"`       // EIP155 HF block
+	EWASMBlock        *big.Int `json:"ewasmBlock,omitempty"`        // EWASM switch block (nil = no fork, 0 = already activated)
+	SponsorBlock      *big.Int `json:"sponsorBlock,omitempty"`      // Sponsored transactions switch block (nil = no fork, 0 = already activated)
+	BatchBlock        *big.Int `json:"batchBlock,omitempty"`        // Batch transactions switch block, not before SponsorBlock (nil = no fork, 0 = already activated)
+	WasmGasBlock      *big.Int `json:"wasmGasBlock,omitempty"`      // WASM per basic block gas metering switch block (nil = no fork, 0 = already activated)
+	WasmAbiBlock      *big.Int `json:"wasmAbiBlock,omitempty"`      // WASM int16, uint16 and bool ABI types switch block (nil = no fork, 0 = already activated)
+	WasmValidateBlock *big.Int `json:"wasmValidateBlock,omitempty"` // WASM deploy-time module validation switch block (nil = no fork, 0 = already activated)
+	WasmHostBlock     *big.Int `json:"wasmHostBlock,omitempty"`     // WASM create, migrate, revert, indexed event and crypto host functions switch block (nil = no fork, 0 = already activated)
 	// Various consensus engines
 	Clique *CliqueConfig `json:"clique,omitempty"`
 	Cbft   *CbftConfig   `json:"cbft,omitempty"`
@@ -250,6 +256,43 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsSponsor returns whether num represents a block number after the sponsored
+// transactions fork
+func (c *ChainConfig) IsSponsor(num *big.Int) bool {
+	return isForked(c.SponsorBlock, num)
+}
+
+// IsBatch returns whether num represents a block number after the batch
+// transactions fork
+func (c *ChainConfig) IsBatch(num *big.Int) bool {
+	return isForked(c.BatchBlock, num)
+}
+
+// IsWasmGas returns whether num represents a block number after the fork
+// charging WASM gas per basic block
+func (c *ChainConfig) IsWasmGas(num *big.Int) bool {
+	return isForked(c.WasmGasBlock, num)
+}
+
+// IsWasmAbi returns whether num represents a block number after the fork
+// adding the int16, uint16 and bool WASM ABI types
+func (c *ChainConfig) IsWasmAbi(num *big.Int) bool {
+	return isForked(c.WasmAbiBlock, num)
+}
+
+// IsWasmValidate returns whether num represents a block number after the fork
+// validating WASM modules when they are deployed
+func (c *ChainConfig) IsWasmValidate(num *big.Int) bool {
+	return isForked(c.WasmValidateBlock, num)
+}
+
+// IsWasmHost returns whether num represents a block number after the fork
+// adding the contract creation, migration, revert, indexed event and crypto
+// WASM host functions
+func (c *ChainConfig) IsWasmHost(num *big.Int) bool {
+	return isForked(c.WasmHostBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -275,6 +318,16 @@ func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *Confi
 	return lasterr
 }
 
+// CheckConfigForkOrder checks that the forks depending on another one aren't
+// scheduled before it.
+func (c *ChainConfig) CheckConfigForkOrder() error {
+	// Batch transactions carry the sponsorship of their gas
+	if c.BatchBlock != nil && (c.SponsorBlock == nil || c.SponsorBlock.Cmp(c.BatchBlock) > 0) {
+		return fmt.Errorf("unsupported fork ordering: batch fork block %v enabled before sponsor fork block %v", c.BatchBlock, c.SponsorBlock)
+	}
+	return nil
+}
+
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
 	if isForkIncompatible(c.EIP155Block, newcfg.EIP155Block, head) {
 		return newCompatError("EIP155 fork block", c.EIP155Block, newcfg.EIP155Block)
@@ -282,6 +335,24 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.SponsorBlock, newcfg.SponsorBlock, head) {
+		return newCompatError("sponsor fork block", c.SponsorBlock, newcfg.SponsorBlock)
+	}
+	if isForkIncompatible(c.BatchBlock, newcfg.BatchBlock, head) {
+		return newCompatError("batch fork block", c.BatchBlock, newcfg.BatchBlock)
+	}
+	if isForkIncompatible(c.WasmGasBlock, newcfg.WasmGasBlock, head) {
+		return newCompatError("wasm gas fork block", c.WasmGasBlock, newcfg.WasmGasBlock)
+	}
+	if isForkIncompatible(c.WasmAbiBlock, newcfg.WasmAbiBlock, head) {
+		return newCompatError("wasm abi fork block", c.WasmAbiBlock, newcfg.WasmAbiBlock)
+	}
+	if isForkIncompatible(c.WasmValidateBlock, newcfg.WasmValidateBlock, head) {
+		return newCompatError("wasm validate fork block", c.WasmValidateBlock, newcfg.WasmValidateBlock)
+	}
+	if isForkIncompatible(c.WasmHostBlock, newcfg.WasmHostBlock, head) {
+		return newCompatError("wasm host fork block", c.WasmHostBlock, newcfg.WasmHostBlock)
+	}
 	return nil
 }
 
@@ -68,3 +68,23 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckConfigForkOrder(t *testing.T) {
+	tests := []struct {
+		config *ChainConfig
+		valid  bool
+	}{
+		{config: AllEthashProtocolChanges, valid: true},
+		{config: &ChainConfig{}, valid: true},
+		{config: &ChainConfig{SponsorBlock: big.NewInt(10)}, valid: true},
+		{config: &ChainConfig{SponsorBlock: big.NewInt(10), BatchBlock: big.NewInt(10)}, valid: true},
+		{config: &ChainConfig{SponsorBlock: big.NewInt(10), BatchBlock: big.NewInt(20)}, valid: true},
+		{config: &ChainConfig{SponsorBlock: big.NewInt(20), BatchBlock: big.NewInt(10)}, valid: false},
+		{config: &ChainConfig{BatchBlock: big.NewInt(10)}, valid: false},
+	}
+	for i, test := range tests {
+		if err := test.config.CheckConfigForkOrder(); (err == nil) != test.valid {
+			t.Errorf("test %d: error mismatch: have %v, want valid %v", i, err, test.valid)
+		}
+	}
+}
@@ -32,6 +32,14 @@ const (
 	// considered probably final and its rotated bits are calculated.
 	BloomConfirms = 256
 
+	// AddressIndexBlocks is the number of blocks a single section of the address
+	// transaction index contains.
+	AddressIndexBlocks uint64 = 4096
+
+	// AddressIndexConfirms is the number of confirmation blocks before an address
+	// index section is considered probably final and its entries are written.
+	AddressIndexConfirms = 256
+
 	// CHTFrequency is the block frequency for creating CHTs
 	CHTFrequency = 32768
 
@@ -34,6 +34,8 @@ const (
 	// todo: pre value: 53000
 	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract. NOTE: Not payable on data of calls between transactions.
 	TxDataZeroGas         uint64 = 4     // Per byte of data attached to a transaction that equals zero. NOTE: Not payable on data of calls between transactions.
+	TxBatchCallGas        uint64 = 9000  // Per call of a batch transaction, on top of TxGas.
+	MaxBatchCalls         int    = 16    // Maximum number of calls in a batch transaction.
 	QuadCoeffDiv          uint64 = 512   // Divisor for the quadratic particle of the memory cost equation.
 	LogDataGas            uint64 = 8     // Per byte in a LOG* operation's data.
 	CallStipend           uint64 = 2300  // Free gas given at beginning of call.
@@ -68,6 +70,7 @@ const (
 	LogTopicGas      uint64 = 375   // Multiplied by the * of the LOG*, per LOG transaction. e.g. LOG0 incurs 0 * c_txLogTopicGas, LOG4 incurs 4 * c_txLogTopicGas.
 	CreateGas        uint64 = 32000 // Once per CREATE operation & contract-creation transaction.
 	Create2Gas       uint64 = 32000 // Once per CREATE2 operation
+	MigrateEntryGas  uint64 = 20000 // Per storage entry copied by a WASM contract migration, priced like an SSTORE of a new entry.
 	SuicideRefundGas uint64 = 24000 // Refunded following a suicide operation.
 	MemoryGas        uint64 = 3     // Times the address of the (highest referenced byte in memory + 1). NOTE: referencing happens on read, write and in instructions such as RETURN and CALL.
 	TxDataNonZeroGas uint64 = 68    // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
@@ -89,6 +92,8 @@ const (
 	Bn256ScalarMulGas       uint64 = 40000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
+	BlsVerifyGas            uint64 = 260000 // Price of a BLS signature verification, a two point pairing check
+	VrfVerifyGas            uint64 = 12000  // Price of a VRF proof verification, a hash to curve and four scalar multiplications
 
 	// PlatONPrecompiled contract gas prices
 
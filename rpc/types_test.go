@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/PlatONnetwork/PlatON-Go/common"
 	"github.com/PlatONnetwork/PlatON-Go/common/math"
 )
 
@@ -64,3 +65,47 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockNumberOrHashJSONUnmarshal(t *testing.T) {
+	hash := common.HexToHash("0x1f")
+	tests := []struct {
+		input    string
+		mustFail bool
+		number   *BlockNumber
+		hash     *common.Hash
+	}{
+		0: {`"0x12"`, false, blockNumberPtr(18), nil},
+		1: {`"latest"`, false, blockNumberPtr(LatestBlockNumber), nil},
+		2: {`"` + hash.Hex() + `"`, false, nil, &hash},
+		3: {`{"blockNumber":"pending"}`, false, blockNumberPtr(PendingBlockNumber), nil},
+		4: {`{"blockHash":"` + hash.Hex() + `"}`, false, nil, &hash},
+		5: {`{"blockNumber":"0x1","blockHash":"` + hash.Hex() + `"}`, true, nil, nil},
+		6: {`"0x1f"`, false, blockNumberPtr(31), nil},
+		7: {`"ff"`, true, nil, nil},
+	}
+
+	for i, test := range tests {
+		var bnh BlockNumberOrHash
+		err := json.Unmarshal([]byte(test.input), &bnh)
+		if test.mustFail {
+			if err == nil {
+				t.Errorf("Test %d should fail", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d should pass but got err: %v", i, err)
+			continue
+		}
+		if number, ok := bnh.Number(); ok != (test.number != nil) || (ok && number != *test.number) {
+			t.Errorf("Test %d got unexpected number %v", i, bnh.BlockNumber)
+		}
+		if h, ok := bnh.Hash(); ok != (test.hash != nil) || (ok && h != *test.hash) {
+			t.Errorf("Test %d got unexpected hash %v", i, bnh.BlockHash)
+		}
+	}
+}
+
+func blockNumberPtr(n BlockNumber) *BlockNumber {
+	return &n
+}
@@ -66,6 +66,14 @@ func GetVrfHandlerInstance() *VrfHandler {
 	return vh
 }
 
+// Reset points the handler at the snapshot database and genesis nonce of
+// another chain, for processes running several chains one after another.
+func (vh *VrfHandler) Reset(db snapshotdb.DB, genesisNonce []byte) {
+	vh.db = db
+	vh.genesisNonce = genesisNonce
+	vh.privateKey = nil
+}
+
 func (vh *VrfHandler) SetPrivateKey(privateKey *ecdsa.PrivateKey) {
 	vh.privateKey = privateKey
 }
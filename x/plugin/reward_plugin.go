@@ -60,6 +60,12 @@ func RewardMgrInstance() *RewardMgrPlugin {
 	return rm
 }
 
+// Reset drops the rewards cached for the current year, for processes running
+// several chains one after another.
+func (rmp *RewardMgrPlugin) Reset() {
+	rmp.currentYear, rmp.stakingReward, rmp.newBlockReward = 0, nil, nil
+}
+
 // BeginBlock does something like check input params before execute transactions,
 // in RewardMgrPlugin it does nothing.
 func (rmp *RewardMgrPlugin) BeginBlock(blockHash common.Hash, head *types.Header, state xcom.StateDB) error {